@@ -22,7 +22,7 @@ import (
 const (
 	// DefaultCmdTimeout specifies the duration after which agent sends
 	// an IdleTimeout signal if a task's command does not run to completion.
-	DefaultCmdTimeout = 2 * time.Hour
+	DefaultCmdTimeout = model.DefaultCommandTimeoutSecs * time.Second
 
 	// DefaultExecTimeoutSecs specifies in seconds the maximum time a task is allowed to run
 	// for, even if it is not idle. This default is used if exec_timeout_secs is not specified
@@ -109,6 +109,10 @@ type Agent struct {
 	// intervals, to the API server.
 	statsCollector *StatsCollector
 
+	// systemStatsCollector samples the host's resource usage while the task
+	// runs and sends it to the API server.
+	systemStatsCollector *SystemStatsCollector
+
 	// logger handles all the logging (task, system, execution, local)
 	// by appending log messages for each type to the correct stream.
 	logger *comm.StreamLogger
@@ -142,6 +146,10 @@ type Agent struct {
 
 	// location of the .pid lock file
 	pidFilePath string
+
+	// commandFailed is set once any of the task's commands fails, for
+	// checking conditions on earlier commands' outcome.
+	commandFailed bool
 }
 
 // finishAndAwaitCleanup sends the returned TaskEndResponse and error
@@ -187,6 +195,11 @@ func (agt *Agent) finishAndAwaitCleanup(status string) (*apimodels.TaskEndRespon
 			agt.logger.LogExecution(slogger.ERROR, "Error cleaning up spawned processes: %v", err)
 		}
 	}
+	detail.Processes = shell.TaskProcesses(agt.taskConfig.Task.Id)
+	if detail.Processes != nil && len(detail.Processes.Leftovers) > 0 {
+		agt.logger.LogExecution(slogger.WARN, "Killed %v processes left running by the task.",
+			len(detail.Processes.Leftovers))
+	}
 	err := agt.removeTaskDirectory()
 	if err != nil {
 		agt.logger.LogExecution(slogger.ERROR, "Error removing task directory: %v", err)
@@ -425,19 +438,27 @@ func New(apiServerURL, taskId, taskSecret, cert, pidFilePath string) (*Agent, er
 		"df -h",
 		"${ps|ps}",
 	)
+	systemStatsCollector := NewSystemStatsCollector(
+		httpCommunicator,
+		streamLogger.System,
+		DefaultSystemStatsInterval,
+		DefaultSystemStatsBatchSize,
+		sh.stopBackgroundChan,
+	)
 
 	agt := &Agent{
-		signalHandler:      sh,
-		logger:             streamLogger,
-		TaskCommunicator:   httpCommunicator,
-		heartbeater:        hbTicker,
-		statsCollector:     statsCollector,
-		idleTimeoutWatcher: idleTimeoutWatcher,
-		APILogger:          apiLogger,
-		Registry:           plugin.NewSimpleRegistry(),
-		KillChan:           make(chan bool),
-		endChan:            make(chan *apimodels.TaskEndDetail, 1),
-		pidFilePath:        pidFilePath,
+		signalHandler:        sh,
+		logger:               streamLogger,
+		TaskCommunicator:     httpCommunicator,
+		heartbeater:          hbTicker,
+		statsCollector:       statsCollector,
+		systemStatsCollector: systemStatsCollector,
+		idleTimeoutWatcher:   idleTimeoutWatcher,
+		APILogger:            apiLogger,
+		Registry:             plugin.NewSimpleRegistry(),
+		KillChan:             make(chan bool),
+		endChan:              make(chan *apimodels.TaskEndDetail, 1),
+		pidFilePath:          pidFilePath,
 	}
 
 	return agt, nil
@@ -554,9 +575,22 @@ func (agt *Agent) RunTaskCommands() (*apimodels.TaskEndResponse, error) {
 // value is received.
 func (agt *Agent) RunCommands(commands []model.PluginCommandConf, returnOnError bool, stop chan bool) error {
 	for i, commandInfo := range commands {
+		run, err := agt.checkCondition(commandInfo, fmt.Sprintf("step %v of %v", i+1, len(commands)))
+		if err != nil {
+			agt.commandFailed = true
+			if returnOnError {
+				return err
+			}
+			continue
+		}
+		if !run {
+			continue
+		}
+
 		parsedCommands, err := agt.Registry.ParseCommandConf(commandInfo, agt.taskConfig.Project.Functions)
 		if err != nil {
 			agt.logger.LogTask(slogger.ERROR, "Couldn't parse plugin command '%v': %v", commandInfo.Command, err)
+			agt.commandFailed = true
 			if returnOnError {
 				return err
 			}
@@ -566,6 +600,7 @@ func (agt *Agent) RunCommands(commands []model.PluginCommandConf, returnOnError
 		cmds, err := agt.Registry.GetCommands(commandInfo, agt.taskConfig.Project.Functions)
 		if err != nil {
 			agt.logger.LogTask(slogger.ERROR, "Don't know how to run plugin action %s: %v", commandInfo.Command, err)
+			agt.commandFailed = true
 			if returnOnError {
 				return err
 			}
@@ -617,23 +652,85 @@ func (agt *Agent) RunCommands(commands []model.PluginCommandConf, returnOnError
 				for key, val := range commandInfo.Vars {
 					newVal, err := agt.taskConfig.Expansions.ExpandString(val)
 					if err != nil {
-						return fmt.Errorf("Can't expand '%v': %v", val, err)
+						return fmt.Errorf("Can't expand var '%v' of %v: %v", key, fullCommandName, err)
 					}
 					agt.taskConfig.Expansions.Put(key, newVal)
 				}
 			}
 
+			if commandInfo.Function != "" {
+				run, err = agt.checkCondition(parsedCommand, fmt.Sprintf("command %v", fullCommandName))
+				if err != nil {
+					agt.commandFailed = true
+					if returnOnError {
+						return err
+					}
+					continue
+				}
+				if !run {
+					continue
+				}
+			}
+
 			pluginCom := &comm.TaskJSONCommunicator{cmd.Plugin(), agt.TaskCommunicator}
 
-			agt.CheckIn(parsedCommand, timeoutPeriod)
+			retry := parsedCommand.Retry
+			if retry == nil {
+				retry = commandInfo.Retry
+			}
+			attempts := 1
+			if retry != nil && retry.Attempts > 1 {
+				attempts = retry.Attempts
+			}
+
+			attempt := 1
+			for ; attempt <= attempts; attempt++ {
+				if attempt > 1 {
+					// commands expand their values in place, so each
+					// attempt needs a new instance of the command
+					if cmd, err = agt.newCommand(commandInfo, j); err != nil {
+						break
+					}
+				}
+				agt.CheckIn(parsedCommand, timeoutPeriod)
 
-			start := time.Now()
-			err = cmd.Execute(commandLogger, pluginCom, agt.taskConfig, stop)
+				start := time.Now()
+				err = cmd.Execute(commandLogger, pluginCom, agt.taskConfig, stop)
 
-			agt.logger.LogExecution(slogger.INFO, "Finished %v in %v", fullCommandName, time.Since(start).String())
+				agt.logger.LogExecution(slogger.INFO, "Finished %v in %v", fullCommandName, time.Since(start).String())
+
+				// a command killed by the task being stopped isn't retried
+				if err == nil || attempt == attempts || isStopped(stop) {
+					break
+				}
+				backoff := retry.Backoff(attempt)
+				agt.logger.LogTask(slogger.WARN, "Command %v failed on attempt %v of %v, retrying in %v: %v",
+					fullCommandName, attempt, attempts, backoff, err)
+				// the backoff is silent, so it mustn't count as idle time
+				agt.CheckIn(parsedCommand, timeoutPeriod+backoff)
+				if !sleepUnlessStopped(backoff, stop) {
+					break
+				}
+			}
+			if err == nil && attempt > 1 {
+				agt.logger.LogTask(slogger.INFO, "Command %v succeeded on attempt %v of %v",
+					fullCommandName, attempt, attempts)
+			}
 
 			if err != nil {
-				agt.logger.LogTask(slogger.ERROR, "Command failed: %v", err)
+				agt.commandFailed = true
+				if attempts > 1 {
+					agt.logger.LogTask(slogger.ERROR, "Command %v failed after %v attempts: %v", fullCommandName, attempts, err)
+				} else {
+					agt.logger.LogTask(slogger.ERROR, "Command %v failed: %v", fullCommandName, err)
+				}
+				if isStopped(stop) {
+					return err
+				}
+				if parsedCommand.ContinueOnError || commandInfo.ContinueOnError {
+					agt.logger.LogTask(slogger.INFO, "Continuing after failure of %v", fullCommandName)
+					continue
+				}
 				if returnOnError {
 					return err
 				}
@@ -644,6 +741,60 @@ func (agt *Agent) RunCommands(commands []model.PluginCommandConf, returnOnError
 	return nil
 }
 
+// newCommand returns a new instance of the command at the given index of
+// those that the command config runs.
+func (agt *Agent) newCommand(commandInfo model.PluginCommandConf, index int) (plugin.Command, error) {
+	cmds, err := agt.Registry.GetCommands(commandInfo, agt.taskConfig.Project.Functions)
+	if err != nil {
+		return nil, err
+	}
+	if index >= len(cmds) {
+		return nil, fmt.Errorf("expected at least %v commands, got %v", index+1, len(cmds))
+	}
+	return cmds[index], nil
+}
+
+// sleepUnlessStopped waits for the duration, returning false if the stop
+// channel is signaled first.
+func sleepUnlessStopped(d time.Duration, stop chan bool) bool {
+	if isStopped(stop) {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// isStopped returns true if the stop channel has been closed, without
+// waiting for it.
+func isStopped(stop chan bool) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkCondition returns whether the command's condition, if it has one,
+// holds, logging why the command is skipped if it doesn't.
+func (agt *Agent) checkCondition(commandInfo model.PluginCommandConf, step string) (bool, error) {
+	run, reason, err := commandInfo.Condition.Check(agt.taskConfig, agt.commandFailed)
+	if err != nil {
+		agt.logger.LogTask(slogger.ERROR, "Couldn't check condition of %v: %v", step, err)
+		return false, err
+	}
+	if !run {
+		agt.logger.LogTask(slogger.INFO, "Skipping %v: %v", step, reason)
+	}
+	return run, nil
+}
+
 // registerPlugins makes plugins available for use by the agent.
 func registerPlugins(registry plugin.Registry, plugins []plugin.CommandPlugin, logger *comm.StreamLogger) error {
 	for _, pl := range plugins {
@@ -676,6 +827,7 @@ func (agt *Agent) callbackTimeoutSignal() chan bool {
 func (agt *Agent) StartBackgroundActions(signalHandler TerminateHandler) {
 	agt.heartbeater.StartHeartbeating()
 	agt.statsCollector.LogStats(agt.taskConfig.Expansions)
+	agt.systemStatsCollector.Start(agt.taskConfig.WorkDir)
 	agt.idleTimeoutWatcher.NotifyTimeouts(agt.signalHandler.idleTimeoutChan)
 	if agt.maxExecTimeoutWatcher != nil {
 		// default action is not to include a master timeout
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/comm"
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// killedPlugin has a command that stops the task while it runs and then
+// fails, the way a command killed by an abort or a timeout does.
+type killedPlugin struct {
+	runs int
+	stop chan bool
+}
+
+func (kp *killedPlugin) Name() string { return "killed" }
+
+func (kp *killedPlugin) NewCommand(string) (plugin.Command, error) {
+	return &killedCommand{kp}, nil
+}
+
+type killedCommand struct {
+	kp *killedPlugin
+}
+
+func (kc *killedCommand) ParseParams(map[string]interface{}) error { return nil }
+func (kc *killedCommand) Name() string                             { return "run" }
+func (kc *killedCommand) Plugin() string                           { return kc.kp.Name() }
+
+func (kc *killedCommand) Execute(plugin.Logger, plugin.PluginCommunicator, *model.TaskConfig, chan bool) error {
+	kc.kp.runs++
+	if kc.kp.runs == 1 {
+		close(kc.kp.stop)
+	}
+	return errors.New("killed")
+}
+
+func TestRunCommandsStopped(t *testing.T) {
+	Convey("With a command that fails because the task was stopped", t, func() {
+		stop := make(chan bool)
+		kp := &killedPlugin{stop: stop}
+		registry := plugin.NewSimpleRegistry()
+		So(registry.Register(kp), ShouldBeNil)
+
+		watcher := comm.NewTimeoutWatcher(make(chan struct{}))
+		apiLogger := comm.NewAPILogger(nil)
+		apiLogger.SendAfterLines = math.MaxInt32
+		logger, err := comm.NewStreamLogger(watcher, apiLogger)
+		So(err, ShouldBeNil)
+		agt := &Agent{
+			logger:             logger,
+			idleTimeoutWatcher: watcher,
+			Registry:           registry,
+			taskConfig: &model.TaskConfig{
+				Project:      &model.Project{},
+				BuildVariant: &model.BuildVariant{Name: "bv"},
+				Expansions:   &command.Expansions{},
+			},
+		}
+
+		Convey("it should not be retried, and later commands should not run", func() {
+			commands := []model.PluginCommandConf{
+				{
+					Command:         "killed.run",
+					Retry:           &model.CommandRetry{Attempts: 3},
+					ContinueOnError: true,
+				},
+				{Command: "killed.run"},
+			}
+			So(agt.RunCommands(commands, true, stop), ShouldNotBeNil)
+			So(kp.runs, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package comm
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// HostCommunicator handles the communication of a long-running agent with the
+// API server that is not scoped to a single task: polling for the next task
+// and downloading new agent executables.
+type HostCommunicator struct {
+	ServerURLRoot string
+	HostId        string
+	HostSecret    string
+	httpClient    *http.Client
+}
+
+// NewHostCommunicator returns an initialized HostCommunicator.
+// The cert parameter may be blank if default system certificates are being used.
+func NewHostCommunicator(serverURL, hostId, hostSecret, cert string) (*HostCommunicator, error) {
+	hostCommunicator := &HostCommunicator{
+		ServerURLRoot: fmt.Sprintf("%v/api/%v", serverURL, evergreen.AgentAPIVersion),
+		HostId:        hostId,
+		HostSecret:    hostSecret,
+		httpClient:    &http.Client{},
+	}
+
+	if cert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cert)) {
+			return nil, errors.New("failed to append HttpsCert to new cert pool")
+		}
+		tc := &tls.Config{RootCAs: pool}
+		hostCommunicator.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tc}}
+	}
+	return hostCommunicator, nil
+}
+
+// NextTask reports the agent's revision to the API server and returns the
+// task, if any, that the agent should run next. It returns HTTPConflictError
+// if the host's secret has changed, meaning that another agent has replaced
+// this one.
+func (h *HostCommunicator) NextTask(agentRevision string) (*apimodels.NextTaskResponse, error) {
+	data, err := json.Marshal(&apimodels.NextTaskRequest{AgentRevision: agentRevision})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.request("POST", "agent/next_task", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	nextTask := &apimodels.NextTaskResponse{}
+	if err = util.ReadJSONInto(resp.Body, nextTask); err != nil {
+		return nil, fmt.Errorf("error unmarshalling next task response: %v", err)
+	}
+	return nextTask, nil
+}
+
+// DownloadAgent writes the API server's current agent executable for the
+// host to w.
+func (h *HostCommunicator) DownloadAgent(w io.Writer) error {
+	resp, err := h.request("GET", "agent/binary", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err = io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("error downloading agent: %v", err)
+	}
+	return nil
+}
+
+// request makes an authenticated request to the given path under the host's
+// API root, returning an error for any response that is not a 200.
+func (h *HostCommunicator) request(method, path string, body io.Reader) (*http.Response, error) {
+	url := fmt.Sprintf("%v/host/%v/%v", h.ServerURLRoot, h.HostId, path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(evergreen.HostSecretHeader, h.HostSecret)
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusConflict {
+		resp.Body.Close()
+		return nil, HTTPConflictError
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %v from %v: %s", resp.StatusCode, url, msg)
+	}
+	return resp, nil
+}
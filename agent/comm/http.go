@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"time"
@@ -365,6 +366,23 @@ func (h *HTTPCommunicator) Heartbeat() (bool, error) {
 	return heartbeatResponse.Abort, nil
 }
 
+// SendSystemStats sends a batch of the host's resource usage samples for the
+// task to the API server.
+func (h *HTTPCommunicator) SendSystemStats(stats []model.SystemStats) error {
+	resp, retryFail, err := h.postJSON("system_stats", stats)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		if retryFail {
+			return fmt.Errorf("sending system stats failed after %v tries: %v",
+				h.MaxAttempts, err)
+		}
+		return err
+	}
+	return nil
+}
+
 func (h *HTTPCommunicator) TryGet(path string) (*http.Response, error) {
 	return h.tryRequestWithClient(path, "GET", h.httpClient, nil)
 }
@@ -374,6 +392,19 @@ func (h *HTTPCommunicator) TryPostJSON(path string, data interface{}) (
 	return h.tryRequestWithClient(path, "POST", h.httpClient, &data)
 }
 
+// TryPostFile does an HTTP POST of the reader's contents to the given task
+// path, without retrying.
+func (h *HTTPCommunicator) TryPostFile(path string, body io.Reader) (*http.Response, error) {
+	endpointUrl := fmt.Sprintf("%s/task/%s/%s", h.ServerURLRoot, h.TaskId, path)
+	req, err := http.NewRequest("POST", endpointUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(evergreen.TaskSecretHeader, h.TaskSecret)
+	req.Header.Add("Content-Type", "application/octet-stream")
+	return h.httpClient.Do(req)
+}
+
 // tryRequestWithClient does the given task HTTP request using the provided client, allowing
 // requests to be done with multiple client configurations/timeouts.
 func (h *HTTPCommunicator) tryRequestWithClient(path string, method string, client *http.Client,
@@ -1,6 +1,7 @@
 package comm
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen/apimodels"
@@ -21,7 +22,9 @@ type TaskCommunicator interface {
 	GetVersion() (*version.Version, error)
 	Log([]model.LogMessage) error
 	Heartbeat() (bool, error)
+	SendSystemStats([]model.SystemStats) error
 	FetchExpansionVars() (*apimodels.ExpansionVars, error)
 	TryGet(path string) (*http.Response, error)
 	TryPostJSON(path string, data interface{}) (*http.Response, error)
+	TryPostFile(path string, body io.Reader) (*http.Response, error)
 }
@@ -2,6 +2,7 @@ package comm
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 
@@ -29,6 +30,10 @@ func (*MockCommunicator) TryPostJSON(path string, data interface{}) (*http.Respo
 	return nil, nil
 }
 
+func (*MockCommunicator) TryPostFile(path string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+
 func (mc *MockCommunicator) Start(pid string) error {
 	mc.RLock()
 	defer mc.RUnlock()
@@ -90,6 +95,10 @@ func (mc *MockCommunicator) Log(logMessages []model.LogMessage) error {
 	return nil
 }
 
+func (mc *MockCommunicator) SendSystemStats(stats []model.SystemStats) error {
+	return nil
+}
+
 func (mc *MockCommunicator) Heartbeat() (bool, error) {
 	mc.RLock()
 	defer mc.RUnlock()
@@ -2,6 +2,7 @@ package comm
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 
@@ -28,6 +29,12 @@ func (t *TaskJSONCommunicator) TaskGetJSON(endpoint string) (*http.Response, err
 	return t.TryGet(fmt.Sprintf("%s/%s", t.PluginName, endpoint))
 }
 
+// TaskPostFile does an HTTP POST of a file's contents for the communicator's
+// plugin + task.
+func (t *TaskJSONCommunicator) TaskPostFile(endpoint string, body io.Reader) (*http.Response, error) {
+	return t.TryPostFile(fmt.Sprintf("%s/%s", t.PluginName, endpoint), body)
+}
+
 // TaskPostResults posts a set of test results for the communicator's task.
 func (t *TaskJSONCommunicator) TaskPostResults(results *task.TestResults) error {
 	retriableSendFile := util.RetriableFunc(
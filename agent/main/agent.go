@@ -1,20 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"encoding/pem"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent"
+	"github.com/evergreen-ci/evergreen/agent/comm"
 	_ "github.com/evergreen-ci/evergreen/plugin/config"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
 	"github.com/tychoish/grip/send"
 )
 
+// pollInterval is how often a long-running agent polls the API server while
+// it has no task to run.
+const pollInterval = 15 * time.Second
+
 func init() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "%s pulls tasks from the API server and runs them.\n", os.Args[0])
@@ -33,6 +41,8 @@ func main() {
 	httpsCertFile := flag.String("https_cert", "", "path to a self-signed private cert")
 	logPrefix := flag.String("log_prefix", "", "prefix for the agent's log filename")
 	pidFile := flag.String("pid_file", "", "path to pid file")
+	hostId := flag.String("host_id", "", "id of the host, for a long-running agent that polls for tasks")
+	hostSecretFile := flag.String("host_secret_file", "", "path to a file holding the secret of the host, for a long-running agent that polls for tasks")
 	flag.Parse()
 
 	httpsCert, err := getHTTPSCertFile(*httpsCertFile)
@@ -69,6 +79,16 @@ func main() {
 	go agent.DumpStackOnSIGQUIT(&agt)
 
 	exitCode := 0
+	if *hostId != "" {
+		hostSecret, err := ioutil.ReadFile(*hostSecretFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read host secret: %v\n", err)
+			agent.ExitAgent(nil, 1, *pidFile)
+		}
+		exitCode = pollForTasks(&agt, *apiServer, *hostId, strings.TrimSpace(string(hostSecret)), httpsCert, *pidFile)
+		agent.ExitAgent(nil, exitCode, *pidFile)
+	}
+
 	// run all tasks until an API server's response has RunNext set to false
 	for {
 		resp, err := agt.RunTask()
@@ -98,6 +118,87 @@ func main() {
 	agent.ExitAgent(nil, exitCode, *pidFile)
 }
 
+// pollForTasks runs a long-running agent that polls the API server for the
+// tasks assigned to its host, and replaces itself with the API server's agent
+// whenever that agent's revision differs from its own. Returns the exit code
+// for the agent once the API server tells it to stop.
+func pollForTasks(agt **agent.Agent, apiServer, hostId, hostSecret, httpsCert, pidFile string) int {
+	hc, err := comm.NewHostCommunicator(apiServer, hostId, hostSecret, httpsCert)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create host communicator: %v\n", err)
+		return 1
+	}
+
+	for {
+		resp, err := hc.NextTask(evergreen.BuildRevision)
+		if err == comm.HTTPConflictError {
+			grip.Info("host secret has changed; another agent has replaced this one")
+			return 0
+		}
+		if err != nil {
+			grip.Errorf("error polling for next task: %v", err)
+			time.Sleep(pollInterval)
+			continue
+		}
+		if resp.ShouldExit {
+			grip.Infof("exiting at the API server's request: %v", resp.Message)
+			return 0
+		}
+
+		if resp.AgentRevision != evergreen.BuildRevision {
+			grip.Infof("updating agent from revision %v to %v", evergreen.BuildRevision, resp.AgentRevision)
+			if err = updateAgent(hc, pidFile); err != nil {
+				grip.Errorf("error updating agent: %v", err)
+				time.Sleep(pollInterval)
+				continue
+			}
+			// the new agent owns the pid file now, so exit without removing it
+			os.Exit(0)
+		}
+
+		if resp.TaskId == "" {
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		// run all tasks until an API server's response has RunNext set to
+		// false, then go back to polling
+		taskId, taskSecret := resp.TaskId, resp.TaskSecret
+		for {
+			*agt, err = agent.New(apiServer, taskId, taskSecret, httpsCert, pidFile)
+			if err != nil {
+				grip.Errorf("could not create new agent for task '%v': %v", taskId, err)
+				// the API server may hand back the same task, so don't spin
+				time.Sleep(pollInterval)
+				break
+			}
+			endResp, err := (*agt).RunTask()
+			if err != nil {
+				grip.Errorf("error running task '%v': %v", taskId, err)
+				time.Sleep(pollInterval)
+				break
+			}
+			if endResp == nil || !endResp.RunNext {
+				break
+			}
+			taskId, taskSecret = endResp.TaskId, endResp.TaskSecret
+		}
+	}
+}
+
+// updateAgent replaces the running agent's executable with the API server's
+// current agent and starts it in place of this one.
+func updateAgent(hc *comm.HostCommunicator, pidFile string) error {
+	executable := &bytes.Buffer{}
+	if err := hc.DownloadAgent(executable); err != nil {
+		return err
+	}
+	if err := agent.ReplaceExecutable(os.Args[0], executable); err != nil {
+		return err
+	}
+	return agent.RestartAgent(os.Args[0], pidFile)
+}
+
 // logSuffix generates a unique log filename suffic that is namespaced
 // to the PID and Date of the agent's execution.
 func logSuffix() string {
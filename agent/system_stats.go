@@ -0,0 +1,252 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/agent/comm"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/tychoish/grip/slogger"
+)
+
+const (
+	// DefaultSystemStatsInterval is how often the agent samples the host's
+	// resource usage.
+	DefaultSystemStatsInterval = 10 * time.Second
+	// DefaultSystemStatsBatchSize is how many samples the agent sends to the
+	// API server at once.
+	DefaultSystemStatsBatchSize = 6
+)
+
+// errSystemStatsUnsupported is returned when resource usage can't be sampled
+// on the agent's platform.
+var errSystemStatsUnsupported = fmt.Errorf("system stats are not supported on this platform")
+
+// SystemStatsCollector samples the host's CPU, memory, disk and network usage
+// while a task runs, and sends the samples to the API server in batches.
+type SystemStatsCollector struct {
+	comm.TaskCommunicator
+	logger *slogger.Logger
+	// indicates the sampling frequency
+	Interval time.Duration
+	// how many samples to send at a time
+	BatchSize int
+	// when closed this sends any remaining samples and stops the collector
+	stop <-chan struct{}
+}
+
+// NewSystemStatsCollector creates a SystemStatsCollector that sends its
+// samples with the given communicator.
+func NewSystemStatsCollector(tc comm.TaskCommunicator, logger *slogger.Logger,
+	interval time.Duration, batchSize int, stop <-chan struct{}) *SystemStatsCollector {
+	return &SystemStatsCollector{
+		TaskCommunicator: tc,
+		logger:           logger,
+		Interval:         interval,
+		BatchSize:        batchSize,
+		stop:             stop,
+	}
+}
+
+// Start samples resource usage in the background until the collector is
+// stopped. Disk usage is measured for the filesystem holding dir.
+func (sc *SystemStatsCollector) Start(dir string) {
+	if sc.Interval <= 0 {
+		sc.Interval = DefaultSystemStatsInterval
+	}
+	if sc.BatchSize <= 0 {
+		sc.BatchSize = DefaultSystemStatsBatchSize
+	}
+
+	sampler := &procSampler{dir: dir}
+	// the first sample only primes the counters that later ones are
+	// measured against
+	if _, err := sampler.sample(time.Now()); err != nil {
+		sc.logger.Logf(slogger.WARN, "Not collecting system stats: %v", err)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(sc.Interval)
+		defer ticker.Stop()
+		batch := []model.SystemStats{}
+		for {
+			select {
+			case <-sc.stop:
+				sc.send(batch)
+				sc.logger.Logf(slogger.INFO, "SystemStatsCollector stopping.")
+				return
+			case now := <-ticker.C:
+				s, err := sampler.sample(now)
+				if err != nil {
+					sc.logger.Logf(slogger.WARN, "Error sampling system stats: %v", err)
+					continue
+				}
+				batch = append(batch, s)
+				if len(batch) >= sc.BatchSize {
+					sc.send(batch)
+					batch = []model.SystemStats{}
+				}
+			}
+		}
+	}()
+}
+
+// send sends a batch of samples, dropping it if the API server can't be
+// reached so that samples don't pile up.
+func (sc *SystemStatsCollector) send(batch []model.SystemStats) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := sc.SendSystemStats(batch); err != nil {
+		sc.logger.Logf(slogger.ERROR, "Error sending %v system stats samples: %v", len(batch), err)
+	}
+}
+
+// cpuTimes are the cumulative jiffies the CPUs have spent in total and idle.
+type cpuTimes struct {
+	total int64
+	idle  int64
+}
+
+// procSampler turns the cumulative counters that the OS reports into samples,
+// using the counters from the previous sample to work out CPU use and
+// network traffic since then.
+type procSampler struct {
+	dir      string
+	started  bool
+	prevCPU  cpuTimes
+	prevRecv int64
+	prevSent int64
+}
+
+// record builds a sample from raw counters, all sizes in bytes.
+func (p *procSampler) record(now time.Time, cpu cpuTimes, memTotal, memAvailable,
+	diskTotal, diskFree, recv, sent int64) model.SystemStats {
+	s := model.SystemStats{
+		Timestamp: now,
+		MemTotal:  memTotal,
+		MemUsed:   memTotal - memAvailable,
+		DiskTotal: diskTotal,
+		DiskUsed:  diskTotal - diskFree,
+	}
+	if p.started {
+		if elapsed := cpu.total - p.prevCPU.total; elapsed > 0 {
+			busy := elapsed - (cpu.idle - p.prevCPU.idle)
+			s.CPUPercent = 100 * float64(busy) / float64(elapsed)
+		}
+		// counters reset when interfaces go down, so ignore drops
+		if recv >= p.prevRecv {
+			s.NetRecv = recv - p.prevRecv
+		}
+		if sent >= p.prevSent {
+			s.NetSent = sent - p.prevSent
+		}
+	}
+	p.started = true
+	p.prevCPU = cpu
+	p.prevRecv = recv
+	p.prevSent = sent
+	return s
+}
+
+// parseProcStat reads the aggregate CPU times from the contents of /proc/stat.
+// Time spent waiting on I/O counts as idle.
+func parseProcStat(r io.Reader) (cpuTimes, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		times := cpuTimes{}
+		// guest time is already counted in user time, so stop at steal
+		for i, f := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			n, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				return cpuTimes{}, fmt.Errorf("bad cpu time '%v': %v", f, err)
+			}
+			times.total += n
+			// the idle and iowait columns
+			if i == 3 || i == 4 {
+				times.idle += n
+			}
+		}
+		return times, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return cpuTimes{}, err
+	}
+	return cpuTimes{}, fmt.Errorf("no aggregate cpu line found")
+}
+
+// parseMeminfo reads the total and available memory, in bytes, from the
+// contents of /proc/meminfo. Kernels too old to report MemAvailable have it
+// estimated from free memory and the page cache.
+func parseMeminfo(r io.Reader) (total int64, available int64, err error) {
+	values := map[string]int64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		// values are reported in kB
+		values[strings.TrimSuffix(fields[0], ":")] = n * 1024
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	total, ok := values["MemTotal"]
+	if !ok {
+		return 0, 0, fmt.Errorf("no MemTotal found")
+	}
+	available, ok = values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	return total, available, nil
+}
+
+// parseNetDev reads the total bytes received and sent on all interfaces
+// except loopback from the contents of /proc/net/dev.
+func parseNetDev(r io.Reader) (recv int64, sent int64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			// one of the header lines
+			continue
+		}
+		if strings.TrimSpace(line[:colon]) == "lo" {
+			continue
+		}
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) < 9 {
+			return 0, 0, fmt.Errorf("bad interface line '%v'", line)
+		}
+		r, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad received bytes '%v': %v", fields[0], err)
+		}
+		s, err := strconv.ParseInt(fields[8], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad sent bytes '%v': %v", fields[8], err)
+		}
+		recv += r
+		sent += s
+	}
+	return recv, sent, scanner.Err()
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// sample reads the host's current resource usage from /proc, and the disk
+// usage of the filesystem holding the sampler's directory.
+func (p *procSampler) sample(now time.Time) (model.SystemStats, error) {
+	stat, err := os.Open("/proc/stat")
+	if err != nil {
+		return model.SystemStats{}, err
+	}
+	defer stat.Close()
+	cpu, err := parseProcStat(stat)
+	if err != nil {
+		return model.SystemStats{}, fmt.Errorf("error reading /proc/stat: %v", err)
+	}
+
+	meminfo, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return model.SystemStats{}, err
+	}
+	defer meminfo.Close()
+	memTotal, memAvailable, err := parseMeminfo(meminfo)
+	if err != nil {
+		return model.SystemStats{}, fmt.Errorf("error reading /proc/meminfo: %v", err)
+	}
+
+	netDev, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return model.SystemStats{}, err
+	}
+	defer netDev.Close()
+	recv, sent, err := parseNetDev(netDev)
+	if err != nil {
+		return model.SystemStats{}, fmt.Errorf("error reading /proc/net/dev: %v", err)
+	}
+
+	fs := syscall.Statfs_t{}
+	if err = syscall.Statfs(p.dir, &fs); err != nil {
+		return model.SystemStats{}, fmt.Errorf("error getting disk usage of %v: %v", p.dir, err)
+	}
+	blockSize := int64(fs.Bsize)
+	diskTotal := int64(fs.Blocks) * blockSize
+	diskFree := int64(fs.Bfree) * blockSize
+
+	return p.record(now, cpu, memTotal, memAvailable, diskTotal, diskFree, recv, sent), nil
+}
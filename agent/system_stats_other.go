@@ -0,0 +1,14 @@
+// +build !linux
+
+package agent
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// sample is only implemented on linux, where usage can be read from /proc.
+func (p *procSampler) sample(now time.Time) (model.SystemStats, error) {
+	return model.SystemStats{}, errSystemStatsUnsupported
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testProcStat = `cpu  100 10 50 800 40 0 0 0 20 0
+cpu0 50 5 25 400 20 0 0 0 10 0
+intr 12345
+`
+	testMeminfo = `MemTotal:        2048000 kB
+MemFree:          512000 kB
+MemAvailable:    1024000 kB
+Buffers:           10000 kB
+Cached:           200000 kB
+`
+	testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:  9999       10    0    0    0     0          0         0     9999      10    0    0    0     0       0          0
+  eth0:  1000       10    0    0    0     0          0         0     2000      20    0    0    0     0       0          0
+  eth1:   500        5    0    0    0     0          0         0      250       2    0    0    0     0       0          0
+`
+)
+
+func TestSystemStatsParsing(t *testing.T) {
+	Convey("When parsing /proc files", t, func() {
+
+		Convey("cpu times should count iowait as idle and leave out guest"+
+			" time", func() {
+			times, err := parseProcStat(strings.NewReader(testProcStat))
+			So(err, ShouldBeNil)
+			So(times.total, ShouldEqual, 1000)
+			So(times.idle, ShouldEqual, 840)
+		})
+
+		Convey("memory should be read in bytes", func() {
+			total, available, err := parseMeminfo(strings.NewReader(testMeminfo))
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2048000*1024)
+			So(available, ShouldEqual, 1024000*1024)
+
+			Convey("and estimated when MemAvailable is missing", func() {
+				old := strings.Replace(testMeminfo, "MemAvailable:    1024000 kB\n", "", 1)
+				_, available, err = parseMeminfo(strings.NewReader(old))
+				So(err, ShouldBeNil)
+				So(available, ShouldEqual, (512000+10000+200000)*1024)
+			})
+		})
+
+		Convey("network traffic should leave out loopback", func() {
+			recv, sent, err := parseNetDev(strings.NewReader(testNetDev))
+			So(err, ShouldBeNil)
+			So(recv, ShouldEqual, 1500)
+			So(sent, ShouldEqual, 2250)
+		})
+
+		Convey("bad input should be rejected", func() {
+			_, err := parseProcStat(strings.NewReader("intr 12345\n"))
+			So(err, ShouldNotBeNil)
+			_, _, err = parseMeminfo(strings.NewReader("MemFree: 10 kB\n"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestProcSampler(t *testing.T) {
+	Convey("With a sampler", t, func() {
+		p := &procSampler{}
+		now := time.Now()
+
+		Convey("the first sample should have no cpu use or traffic", func() {
+			s := p.record(now, cpuTimes{total: 1000, idle: 800}, 100, 40, 1000, 250, 500, 600)
+			So(s.CPUPercent, ShouldEqual, 0)
+			So(s.NetRecv, ShouldEqual, 0)
+			So(s.MemUsed, ShouldEqual, 60)
+			So(s.DiskUsed, ShouldEqual, 750)
+
+			Convey("and later samples should measure use since the last one", func() {
+				s = p.record(now, cpuTimes{total: 1200, idle: 850}, 100, 40, 1000, 250, 800, 700)
+				So(s.CPUPercent, ShouldEqual, 75)
+				So(s.NetRecv, ShouldEqual, 300)
+				So(s.NetSent, ShouldEqual, 100)
+			})
+
+			Convey("and counters that reset should not count as traffic", func() {
+				s = p.record(now, cpuTimes{total: 1200, idle: 850}, 100, 40, 1000, 250, 10, 10)
+				So(s.NetRecv, ShouldEqual, 0)
+				So(s.NetSent, ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// ReplaceExecutable replaces the executable at path with the contents of r.
+// The new executable is written alongside the old one and renamed into place,
+// so a failed download never leaves a partial executable at path. The old
+// executable is kept at path + ".old", since on some platforms a running
+// executable can be renamed but not removed.
+func ReplaceExecutable(path string, r io.Reader) error {
+	newPath := path + ".new"
+	oldPath := path + ".old"
+
+	f, err := os.OpenFile(newPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating %v: %v", newPath, err)
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("error writing %v: %v", newPath, err)
+	}
+
+	if err = os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing %v: %v", oldPath, err)
+	}
+	if err = os.Rename(path, oldPath); err != nil {
+		return fmt.Errorf("error moving %v aside: %v", path, err)
+	}
+	if err = os.Rename(newPath, path); err != nil {
+		// put the old executable back so the agent can still be restarted
+		os.Rename(oldPath, path)
+		return fmt.Errorf("error moving %v into place: %v", newPath, err)
+	}
+	return nil
+}
+
+// RestartAgent starts the executable at path with the current process's
+// arguments, after removing the pid file so that the new agent can create its
+// own. The caller should exit once it returns successfully.
+func RestartAgent(path, pidFile string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing pid file: %v", err)
+	}
+
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("error starting new agent: %v", err)
+	}
+	return nil
+}
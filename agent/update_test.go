@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	Convey("With an existing agent executable", t, func() {
+		dir, err := ioutil.TempDir("", "agent-update")
+		testutil.HandleTestingErr(err, t, "error creating temp dir")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "main")
+		So(ioutil.WriteFile(path, []byte("old"), 0755), ShouldBeNil)
+
+		Convey("replacing it should put the new executable in place and keep the old one", func() {
+			So(ReplaceExecutable(path, strings.NewReader("new")), ShouldBeNil)
+			contents, err := ioutil.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "new")
+			contents, err = ioutil.ReadFile(path + ".old")
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "old")
+
+			info, err := os.Stat(path)
+			So(err, ShouldBeNil)
+			So(info.Mode()&0100, ShouldNotEqual, 0)
+
+			Convey("and replacing it again should overwrite the kept executable", func() {
+				So(ReplaceExecutable(path, strings.NewReader("newer")), ShouldBeNil)
+				contents, err := ioutil.ReadFile(path + ".old")
+				So(err, ShouldBeNil)
+				So(string(contents), ShouldEqual, "new")
+			})
+		})
+
+		Convey("a failed download should leave the old executable untouched", func() {
+			So(ReplaceExecutable(path, failingReader{}), ShouldNotBeNil)
+			contents, err := ioutil.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "old")
+			_, err = os.Stat(path + ".new")
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
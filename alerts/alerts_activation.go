@@ -1,6 +1,7 @@
 package alerts
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/model"
@@ -127,6 +128,37 @@ func RunSpawnWarningTriggers(host *host.Host) error {
 	return nil
 }
 
+// RunBudgetTriggers queues an alert if the project has crossed the soft limit
+// of one of its budgets, once per period.
+func RunBudgetTriggers(proj *model.ProjectRef, status *model.BudgetStatus) error {
+	ctx := triggerContext{projectRef: proj, budget: status}
+	for _, trigger := range AvailableBudgetTriggers {
+		shouldExec, err := trigger.ShouldExecute(ctx)
+		if err != nil {
+			return err
+		}
+		if !shouldExec {
+			continue
+		}
+		err = alert.EnqueueAlertRequest(&alert.AlertRequest{
+			Id:        bson.NewObjectId(),
+			Trigger:   trigger.Id(),
+			ProjectId: proj.Identifier,
+			Display: fmt.Sprintf("%v has spent $%.2f, crossing the soft limit of $%.2f of its %v budget",
+				proj.Identifier, status.Spent, status.SoftLimit, status.Period),
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		err = storeTriggerBookkeeping(ctx, []Trigger{trigger})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getTaskTriggerContext(t *task.Task) (*triggerContext, error) {
 	ctx := triggerContext{task: t}
 	t, err := task.FindOne(task.ByBeforeRevisionWithStatuses(t.RevisionOrderNumber, task.CompletedStatuses, t.BuildVariant,
@@ -125,6 +125,8 @@ func getTemplate(alertCtx AlertContext) string {
 		fallthrough
 	case alertrecord.SpawnHostTwelveHourWarning:
 		return "email/host_spawn.html"
+	case alertrecord.BudgetSoftLimitId:
+		return "email/budget.html"
 	default:
 		return "email/task_fail.html"
 	}
@@ -204,6 +206,8 @@ func getSubject(alertCtx AlertContext) string {
 	case alertrecord.SpawnHostTwelveHourWarning:
 		return fmt.Sprintf("Your %s host (%s) will expire in twelve hours.",
 			alertCtx.Host.Distro, alertCtx.Host.Id)
+	case alertrecord.BudgetSoftLimitId:
+		return fmt.Sprintf("Budget Warning: %s", alertCtx.AlertRequest.Display)
 		// TODO(EVG-224) alertrecord.SpawnHostExpired:
 	}
 	return taskFailureSubject(alertCtx)
@@ -215,3 +215,30 @@ func (lrnf LastRevisionNotFound) CreateAlertRecord(ctx triggerContext) *alertrec
 	rec := newAlertRecord(ctx, alertrecord.LastRevisionNotFound)
 	return rec
 }
+
+// BudgetSoftLimit is a trigger that queues an alert the first time in a
+// budget's period that a project's spending crosses the budget's soft limit.
+type BudgetSoftLimit struct{}
+
+func (bsl BudgetSoftLimit) Id() string { return alertrecord.BudgetSoftLimitId }
+func (bsl BudgetSoftLimit) Display() string {
+	return "the project's spending crosses a budget's soft limit"
+}
+
+func (bsl BudgetSoftLimit) ShouldExecute(ctx triggerContext) (bool, error) {
+	if ctx.budget == nil || !ctx.budget.OverSoftLimit {
+		return false, nil
+	}
+	rec, err := alertrecord.FindOne(alertrecord.ByBudgetPeriod(ctx.budget.ProjectId, ctx.budget.PeriodKey()))
+	if err != nil {
+		return false, err
+	}
+	return rec == nil, nil
+}
+
+func (bsl BudgetSoftLimit) CreateAlertRecord(ctx triggerContext) *alertrecord.AlertRecord {
+	rec := newAlertRecord(ctx, alertrecord.BudgetSoftLimitId)
+	rec.ProjectId = ctx.budget.ProjectId
+	rec.BudgetPeriod = ctx.budget.PeriodKey()
+	return rec
+}
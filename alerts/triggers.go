@@ -40,6 +40,7 @@ type triggerContext struct {
 	task              *task.Task
 	previousCompleted *task.Task
 	host              *host.Host
+	budget            *model.BudgetStatus
 }
 
 var (
@@ -56,6 +57,10 @@ var (
 		LastRevisionNotFound{},
 	}
 
+	// AvailableBudgetTriggers are the triggers for a project's spending,
+	// which can be configured alongside the task triggers.
+	AvailableBudgetTriggers = []Trigger{BudgetSoftLimit{}}
+
 	SpawnWarningTriggers = []Trigger{SpawnTwoHourWarning{}, SpawnTwelveHourWarning{}}
 )
 
@@ -69,6 +74,9 @@ func newAlertRecord(ctx triggerContext, alertType string) *alertrecord.AlertReco
 	if ctx.host != nil {
 		record.HostId = ctx.host.Id
 	}
+	if ctx.projectRef != nil {
+		record.ProjectId = ctx.projectRef.Identifier
+	}
 	if ctx.task != nil {
 		record.ProjectId = ctx.task.Project
 		record.VersionId = ctx.task.Version
@@ -308,3 +308,50 @@ func TestReachedFailureLimit(t *testing.T) {
 		})
 	})
 }
+
+func TestBudgetSoftLimitTrigger(t *testing.T) {
+	Convey("With a project over the soft limit of its daily budget", t, func() {
+		db.Clear(alertrecord.Collection)
+		status := &model.BudgetStatus{
+			ProjectId:     testProject.Identifier,
+			Period:        model.BudgetDaily,
+			PeriodStart:   time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC),
+			SoftLimit:     10,
+			Spent:         12,
+			OverSoftLimit: true,
+		}
+
+		trigger := BudgetSoftLimit{}
+		ctx := triggerContext{projectRef: &testProject, budget: status}
+		shouldExec, err := trigger.ShouldExecute(ctx)
+		So(err, ShouldBeNil)
+		So(shouldExec, ShouldBeTrue)
+
+		// run bookkeeping
+		err = storeTriggerBookkeeping(ctx, []Trigger{trigger})
+		So(err, ShouldBeNil)
+
+		Convey("the trigger should not execute again in the same period", func() {
+			shouldExec, err = trigger.ShouldExecute(ctx)
+			So(err, ShouldBeNil)
+			So(shouldExec, ShouldBeFalse)
+		})
+
+		Convey("the trigger should execute again in the next period", func() {
+			next := *status
+			next.PeriodStart = status.PeriodStart.AddDate(0, 0, 1)
+			shouldExec, err = trigger.ShouldExecute(triggerContext{projectRef: &testProject, budget: &next})
+			So(err, ShouldBeNil)
+			So(shouldExec, ShouldBeTrue)
+		})
+
+		Convey("the trigger should not execute under the soft limit", func() {
+			under := *status
+			under.PeriodStart = status.PeriodStart.AddDate(0, 0, 1)
+			under.OverSoftLimit = false
+			shouldExec, err = trigger.ShouldExecute(triggerContext{projectRef: &testProject, budget: &under})
+			So(err, ShouldBeNil)
+			So(shouldExec, ShouldBeFalse)
+		})
+	})
+}
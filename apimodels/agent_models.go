@@ -1,5 +1,7 @@
 package apimodels
 
+import "time"
+
 // TaskStartRequest holds information sent by the agent to the
 // API server at the beginning of each task run.
 type TaskStartRequest struct {
@@ -19,6 +21,35 @@ type TaskEndDetail struct {
 	Type        string `bson:"type,omitempty" json:"type,omitempty"`
 	Description string `bson:"desc,omitempty" json:"desc,omitempty"`
 	TimedOut    bool   `bson:"timed_out,omitempty" json:"timed_out,omitempty"`
+
+	// Processes reports what ran under the task, and what the agent had to
+	// clean up after it.
+	Processes *ProcessReport `bson:"processes,omitempty" json:"processes,omitempty"`
+}
+
+// ProcessReport describes the processes that ran under a task.
+type ProcessReport struct {
+	Processes []ProcessInfo `bson:"procs,omitempty" json:"procs,omitempty"`
+	// Leftovers are processes still running when the task ended, which the
+	// agent killed.
+	Leftovers []ProcessInfo `bson:"leftovers,omitempty" json:"leftovers,omitempty"`
+	// Dropped counts the processes that weren't recorded because the task
+	// started too many.
+	Dropped int `bson:"dropped,omitempty" json:"dropped,omitempty"`
+}
+
+// ProcessInfo describes a process spawned under a task. Processes the agent
+// started itself have an exact start time and exit code; their descendants
+// are only seen periodically, so their times are approximate and their exit
+// codes unknown.
+type ProcessInfo struct {
+	Pid       int       `bson:"pid" json:"pid"`
+	Command   string    `bson:"cmd" json:"cmd"`
+	StartTime time.Time `bson:"start" json:"start"`
+	EndTime   time.Time `bson:"end,omitempty" json:"end,omitempty"`
+	ExitCode  *int      `bson:"exit_code,omitempty" json:"exit_code,omitempty"`
+	// PeakRSS is the most memory the process had resident, in bytes.
+	PeakRSS int64 `bson:"peak_rss,omitempty" json:"peak_rss,omitempty"`
 }
 
 type TaskEndDetails struct {
@@ -35,5 +66,23 @@ type TaskEndResponse struct {
 	RunNext    bool   `json:"run_next,omitempty"`
 }
 
+// NextTaskRequest is sent by a long-running agent when it polls the API
+// server for its next task.
+type NextTaskRequest struct {
+	AgentRevision string `json:"agent_revision"`
+}
+
+// NextTaskResponse is sent by the API server in response to a
+// NextTaskRequest. TaskId is empty if there is no task for the agent to run.
+// If AgentRevision differs from the agent's own revision, the agent should
+// replace itself with the current agent before running any more tasks.
+type NextTaskResponse struct {
+	TaskId        string `json:"task_id,omitempty"`
+	TaskSecret    string `json:"task_secret,omitempty"`
+	AgentRevision string `json:"agent_revision"`
+	ShouldExit    bool   `json:"should_exit,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
 // ExpansionVars is a map of expansion variables for a project.
 type ExpansionVars map[string]string
@@ -0,0 +1,207 @@
+// Package artifactgc removes artifact files from their stores once their
+// projects' retention periods have passed, and marks them as expired so that
+// the UI doesn't show broken links.
+package artifactgc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/plugin/builtin/artifacts"
+	"github.com/evergreen-ci/evergreen/store"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/tychoish/grip/slogger"
+)
+
+// DefaultBatchSize is the most entries expired for each project and
+// requester in a run, if the settings don't say otherwise.
+const DefaultBatchSize = 500
+
+// Collector expires artifact entries.
+type Collector struct {
+	// stores returns the store that can delete files from the named store
+	// and bucket, or nil if there is none or the bucket isn't one that files
+	// may be deleted from
+	stores    func(name, bucket string) store.Deleter
+	batchSize int
+}
+
+// NewCollector returns a collector that deletes files with the credentials
+// and local store in the settings.
+func NewCollector(settings *evergreen.Settings) *Collector {
+	conf := settings.ArtifactGC
+	local, err := artifacts.LocalStore(settings)
+	if err != nil {
+		evergreen.Logger.Logf(slogger.WARN, "Not deleting files from the local store: %v", err)
+	}
+	if conf.AWSKey == "" || conf.AWSSecret == "" {
+		evergreen.Logger.Logf(slogger.WARN, "Not deleting files from S3: no AWS credentials are configured")
+	}
+
+	batchSize := conf.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Collector{
+		stores: func(name, bucket string) store.Deleter {
+			switch name {
+			case store.S3:
+				if conf.AWSKey == "" || conf.AWSSecret == "" || !util.SliceContains(conf.Buckets, bucket) {
+					return nil
+				}
+				return store.NewS3Store(conf.AWSKey, conf.AWSSecret, bucket, "", "")
+			case store.Local:
+				if local == nil {
+					return nil
+				}
+				return local
+			}
+			return nil
+		},
+		batchSize: batchSize,
+	}
+}
+
+// Collect expires every project's entries that are past its retention
+// periods as of now. Entries that can't be expired are logged and left for
+// the next run.
+func (c *Collector) Collect(now time.Time) error {
+	if err := c.recordTaskDetails(now); err != nil {
+		return err
+	}
+
+	refs, err := model.FindAllProjectRefs()
+	if err != nil {
+		return fmt.Errorf("error finding projects: %v", err)
+	}
+	failed := 0
+	for _, ref := range refs {
+		for requester, cutoff := range ref.ArtifactRetention.Cutoffs(now) {
+			q := artifact.ByRetentionExpired(ref.Identifier, requester, cutoff).Limit(c.batchSize)
+			entries, err := artifact.FindAll(q)
+			if err != nil {
+				return fmt.Errorf("error finding expired artifacts for project %v: %v", ref.Identifier, err)
+			}
+			for i := range entries {
+				e := &entries[i]
+				if err := c.expire(e); err != nil {
+					evergreen.Logger.Logf(slogger.ERROR, "Error expiring artifacts for task %v: %v",
+						e.TaskId, err)
+					failed++
+					c.recordFailure(e)
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to expire artifacts for %v tasks", failed)
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt to expire the entry, so that
+// entries that keep failing stop being tried ahead of the rest.
+func (c *Collector) recordFailure(e *artifact.Entry) {
+	if err := e.RecordExpireFailure(); err != nil {
+		evergreen.Logger.Logf(slogger.ERROR, "Error recording failure to expire artifacts for task %v: %v",
+			e.TaskId, err)
+		return
+	}
+	if e.ExpireFailures >= artifact.MaxExpireFailures {
+		evergreen.Logger.Logf(slogger.WARN, "Giving up on expiring artifacts for task %v after %v failures",
+			e.TaskId, e.ExpireFailures)
+	}
+}
+
+// recordTaskDetails records the details that retention is decided by with
+// entries attached before they were recorded. Entries whose tasks no longer
+// exist are given no project, so they're never expired.
+func (c *Collector) recordTaskDetails(now time.Time) error {
+	entries, err := artifact.FindAll(artifact.ByMissingCreateTime().Limit(c.batchSize))
+	if err != nil {
+		return fmt.Errorf("error finding artifacts to update: %v", err)
+	}
+	for i := range entries {
+		e := &entries[i]
+		t, err := task.FindOne(task.ById(e.TaskId))
+		if err != nil {
+			return fmt.Errorf("error finding task %v: %v", e.TaskId, err)
+		}
+		if t == nil {
+			err = e.SetTaskDetails("", "", "", now)
+		} else {
+			err = e.SetTaskDetails(t.Project, t.Version, t.Requester, t.CreateTime)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating artifacts for task %v: %v", e.TaskId, err)
+		}
+	}
+	return nil
+}
+
+// expire deletes the entry's files from their stores and marks them as
+// expired, unless the entry is from a mainline version that was pushed, in
+// which case it's retained.
+func (c *Collector) expire(e *artifact.Entry) error {
+	if e.Requester == evergreen.RepotrackerVersionRequester {
+		pushed, err := model.VersionWasPushed(e.Version)
+		if err != nil {
+			return fmt.Errorf("error checking for pushes from version %v: %v", e.Version, err)
+		}
+		if pushed {
+			return e.MarkRetained()
+		}
+	}
+
+	for i := range e.Files {
+		file := &e.Files[i]
+		if file.Expired {
+			continue
+		}
+		deleted, err := c.delete(*e, *file)
+		if err != nil {
+			return err
+		}
+		file.Expired = deleted
+	}
+	return e.MarkExpired()
+}
+
+// delete removes the entry's file from its store. It returns false if the
+// file isn't in a store that can be cleaned up, e.g. because it's a link to
+// an outside server, if it's outside the entry's project's directory of the
+// store or bucket, or if another entry that hasn't expired refers to it.
+// Files without a recorded store are never deleted, since only their links
+// are known. Buckets may be shared between projects, so a file's path must
+// start with its project's identifier for it to be deleted.
+func (c *Collector) delete(e artifact.Entry, file artifact.File) (bool, error) {
+	name, bucket, path := file.Store, file.Bucket, file.Path
+	if !artifacts.InProjectDir(e.Project, path) {
+		return false, nil
+	}
+	s := c.stores(name, bucket)
+	if s == nil {
+		return false, nil
+	}
+
+	// the file is left for the last entry that refers to it to delete
+	refs, err := artifact.Count(artifact.ByLiveReferences(e, file.Link, name, bucket, path))
+	if err != nil {
+		return false, fmt.Errorf("error finding other references to %v: %v", file.Link, err)
+	}
+	if refs > 0 {
+		return false, nil
+	}
+	if err := s.Delete(path); err != nil {
+		return false, fmt.Errorf("error deleting %v: %v", file.Link, err)
+	}
+	// entries that expired while the file was shared still link to it
+	if err := artifact.MarkFileExpired(file.Link, name, bucket, path); err != nil {
+		return false, fmt.Errorf("error marking %v as expired: %v", file.Link, err)
+	}
+	return true, nil
+}
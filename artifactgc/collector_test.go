@@ -0,0 +1,212 @@
+package artifactgc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/store"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testConfig = evergreen.TestConfig()
+
+func init() {
+	db.SetGlobalSessionProvider(db.SessionFactoryFromConfig(testConfig))
+}
+
+// recordingStore records the files deleted from it.
+type recordingStore struct {
+	deleted []string
+	err     error
+}
+
+func (s *recordingStore) Delete(remotePath string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.deleted = append(s.deleted, remotePath)
+	return nil
+}
+
+func TestCollect(t *testing.T) {
+	Convey("With a project that keeps patch artifacts for a week", t, func() {
+		testutil.HandleTestingErr(db.ClearCollections(artifact.Collection, model.ProjectRefCollection,
+			task.Collection, model.PushlogCollection), t, "error clearing collections")
+		now := time.Date(2017, 3, 15, 12, 0, 0, 0, time.UTC)
+
+		ref := &model.ProjectRef{
+			Identifier:        "p1",
+			ArtifactRetention: model.ArtifactRetention{PatchDays: 7, MainlineDays: 30},
+		}
+		So(ref.Insert(), ShouldBeNil)
+
+		s3 := &recordingStore{}
+		local := &recordingStore{}
+		c := &Collector{
+			stores: func(name, bucket string) store.Deleter {
+				if name == store.S3 && bucket == "bucket" {
+					return s3
+				}
+				if name == store.Local {
+					return local
+				}
+				return nil
+			},
+			batchSize: 10,
+		}
+
+		insert := func(taskId, requester string, created time.Time, files ...artifact.File) {
+			entry := artifact.Entry{
+				TaskId:     taskId,
+				Project:    "p1",
+				Version:    taskId + "_version",
+				Requester:  requester,
+				CreateTime: created,
+				Files:      files,
+			}
+			So(entry.Upsert(), ShouldBeNil)
+		}
+		s3File := func(path string) artifact.File {
+			return artifact.File{Name: path, Link: "https://s3.amazonaws.com/bucket/" + path,
+				Store: store.S3, Bucket: "bucket", Path: path}
+		}
+
+		Convey("old patch artifacts should be deleted and marked expired", func() {
+			insert("old_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -8),
+				s3File("p1/a.tgz"),
+				artifact.File{Name: "legacy", Link: "https://s3.amazonaws.com/bucket/p1/legacy.tgz"},
+				artifact.File{Name: "external", Link: "https://example.com/report.html"})
+			insert("new_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -6), s3File("p1/b.tgz"))
+
+			So(c.Collect(now), ShouldBeNil)
+			So(s3.deleted, ShouldResemble, []string{"p1/a.tgz"})
+
+			entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Expired, ShouldBeTrue)
+			So(entry.Files[0].Expired, ShouldBeTrue)
+			So(entry.Files[1].Expired, ShouldBeFalse)
+			So(entry.Files[2].Expired, ShouldBeFalse)
+
+			entry, err = artifact.FindOne(artifact.ByTaskId("new_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Expired, ShouldBeFalse)
+
+			Convey("and not be collected again", func() {
+				So(c.Collect(now), ShouldBeNil)
+				So(len(s3.deleted), ShouldEqual, 1)
+			})
+		})
+
+		Convey("only files in the entry's project's part of the local store should be deleted", func() {
+			insert("old_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -8),
+				artifact.File{Name: "own", Link: "/plugin/artifacts/files/p1/a.tgz", Store: store.Local, Path: "p1/a.tgz"},
+				artifact.File{Name: "other", Link: "/plugin/artifacts/files/p2/a.tgz", Store: store.Local, Path: "p2/a.tgz"},
+				artifact.File{Name: "escape", Link: "/plugin/artifacts/files/p2/b.tgz", Store: store.Local, Path: "p1/../p2/b.tgz"})
+
+			So(c.Collect(now), ShouldBeNil)
+			So(local.deleted, ShouldResemble, []string{"p1/a.tgz"})
+			entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Files[0].Expired, ShouldBeTrue)
+			So(entry.Files[1].Expired, ShouldBeFalse)
+			So(entry.Files[2].Expired, ShouldBeFalse)
+		})
+
+		Convey("only files in the entry's project's part of a bucket should be deleted", func() {
+			insert("old_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -8),
+				s3File("p1/a.tgz"), s3File("p2/a.tgz"), s3File("a.tgz"))
+
+			So(c.Collect(now), ShouldBeNil)
+			So(s3.deleted, ShouldResemble, []string{"p1/a.tgz"})
+			entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Files[0].Expired, ShouldBeTrue)
+			So(entry.Files[1].Expired, ShouldBeFalse)
+			So(entry.Files[2].Expired, ShouldBeFalse)
+		})
+
+		Convey("artifacts from pushed mainline versions should be retained", func() {
+			insert("pushed", evergreen.RepotrackerVersionRequester, now.AddDate(0, 0, -31), s3File("p1/c.tgz"))
+			So((&task.Task{Id: "pushed_push", Version: "pushed_version"}).Insert(), ShouldBeNil)
+			So((&model.PushLog{TaskId: "pushed_push", Status: evergreen.PushLogSuccess}).Insert(), ShouldBeNil)
+
+			So(c.Collect(now), ShouldBeNil)
+			So(s3.deleted, ShouldBeEmpty)
+			entry, err := artifact.FindOne(artifact.ByTaskId("pushed"))
+			So(err, ShouldBeNil)
+			So(entry.Retained, ShouldBeTrue)
+			So(entry.Files[0].Expired, ShouldBeFalse)
+		})
+
+		Convey("entries whose files can't be deleted should be left for the next run", func() {
+			insert("old_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -8), s3File("p1/a.tgz"))
+			s3.err = fmt.Errorf("access denied")
+
+			So(c.Collect(now), ShouldNotBeNil)
+			entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Expired, ShouldBeFalse)
+			So(entry.Files[0].Expired, ShouldBeFalse)
+			So(entry.ExpireFailures, ShouldEqual, 1)
+
+			Convey("until they've failed too many times", func() {
+				for i := 1; i < artifact.MaxExpireFailures; i++ {
+					So(c.Collect(now), ShouldNotBeNil)
+				}
+				So(c.Collect(now), ShouldBeNil)
+				entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+				So(err, ShouldBeNil)
+				So(entry.ExpireFailures, ShouldEqual, artifact.MaxExpireFailures)
+			})
+		})
+
+		Convey("files other entries still refer to should not be deleted", func() {
+			insert("old_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -8), s3File("p1/shared.tgz"))
+			insert("new_patch", evergreen.PatchVersionRequester, now.AddDate(0, 0, -6), s3File("p1/shared.tgz"))
+
+			So(c.Collect(now), ShouldBeNil)
+			So(s3.deleted, ShouldBeEmpty)
+			entry, err := artifact.FindOne(artifact.ByTaskId("old_patch"))
+			So(err, ShouldBeNil)
+			So(entry.Expired, ShouldBeTrue)
+			So(entry.Files[0].Expired, ShouldBeFalse)
+
+			Convey("until the last of them expires", func() {
+				So(c.Collect(now.AddDate(0, 0, 2)), ShouldBeNil)
+				So(s3.deleted, ShouldResemble, []string{"p1/shared.tgz"})
+				for _, id := range []string{"old_patch", "new_patch"} {
+					entry, err := artifact.FindOne(artifact.ByTaskId(id))
+					So(err, ShouldBeNil)
+					So(entry.Expired, ShouldBeTrue)
+					So(entry.Files[0].Expired, ShouldBeTrue)
+				}
+			})
+		})
+
+		Convey("entries attached before their task's details were recorded should get them", func() {
+			So(db.Insert(artifact.Collection, map[string]interface{}{
+				artifact.TaskIdKey:   "legacy",
+				artifact.TaskNameKey: "",
+				artifact.BuildIdKey:  "",
+				artifact.FilesKey:    []artifact.File{s3File("p1/d.tgz")},
+			}), ShouldBeNil)
+			So((&task.Task{Id: "legacy", Project: "p1", Version: "v", Requester: evergreen.PatchVersionRequester,
+				CreateTime: now.AddDate(0, 0, -10)}).Insert(), ShouldBeNil)
+
+			So(c.Collect(now), ShouldBeNil)
+			So(s3.deleted, ShouldResemble, []string{"p1/d.tgz"})
+			entry, err := artifact.FindOne(artifact.ByTaskId("legacy"))
+			So(err, ShouldBeNil)
+			So(entry.Project, ShouldEqual, "p1")
+			So(entry.Expired, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package artifactgc
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/tychoish/grip/slogger"
+)
+
+type Runner struct{}
+
+const (
+	RunnerName  = "artifactgc"
+	Description = "remove artifact files from their stores once their retention period has passed"
+)
+
+func (r *Runner) Name() string {
+	return RunnerName
+}
+
+func (r *Runner) Description() string {
+	return Description
+}
+
+func (r *Runner) Run(config *evergreen.Settings) error {
+	startTime := time.Now()
+	evergreen.Logger.Logf(slogger.INFO, "Starting artifact garbage collection at time %v", startTime)
+
+	if err := NewCollector(config).Collect(startTime); err != nil {
+		return evergreen.Logger.Errorf(slogger.ERROR, "error collecting expired artifacts: %v", err)
+	}
+
+	runtime := time.Now().Sub(startTime)
+	if err := model.SetProcessRuntimeCompleted(RunnerName, runtime); err != nil {
+		evergreen.Logger.Errorf(slogger.ERROR, "error updating process status: %v", err)
+	}
+	evergreen.Logger.Logf(slogger.INFO, "Artifact garbage collection took %v to run", runtime)
+	return nil
+}
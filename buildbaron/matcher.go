@@ -0,0 +1,241 @@
+// Package buildbaron suggests existing JIRA tickets that may describe a
+// task's failure, so that build barons can triage failures without filing
+// duplicate tickets.
+package buildbaron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/alerts"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+const (
+	// MaxSuggestions is the most tickets suggested for a failure.
+	MaxSuggestions = 10
+
+	// maxSearchResults is how many tickets are fetched from JIRA to be ranked.
+	maxSearchResults = 50
+
+	// maxQueryTests is the most failed tests searched for, to keep the query
+	// to a reasonable length.
+	maxQueryTests = 10
+)
+
+// points awarded to a ticket for each thing it mentions
+const (
+	testInSummaryScore     = 3
+	testInDescriptionScore = 1
+	taskInSummaryScore     = 2
+	taskInDescriptionScore = 1
+	variantMentionedScore  = 1
+)
+
+// Searcher finds JIRA tickets. It is implemented by thirdparty.JiraHandler.
+type Searcher interface {
+	JQLSearch(query string, startAt, maxResults int) (*thirdparty.JiraSearchResults, error)
+	IssueURL(key string) string
+}
+
+// Failure describes a failed task to find tickets for.
+type Failure struct {
+	TaskName     string
+	BuildVariant string
+	Tests        []string
+}
+
+// Suggestion is a ticket that may describe a failure.
+type Suggestion struct {
+	Key          string   `json:"key"`
+	Summary      string   `json:"summary"`
+	Status       string   `json:"status"`
+	URL          string   `json:"url"`
+	Score        int      `json:"score"`
+	MatchedTests []string `json:"matched_tests"`
+	MatchedTask  bool     `json:"matched_task"`
+}
+
+// FailureFromTask describes the failure of a task, using the names of its
+// failed tests without their paths.
+func FailureFromTask(t *task.Task) Failure {
+	f := Failure{TaskName: t.DisplayName, BuildVariant: t.BuildVariant}
+	for _, test := range t.TestResults {
+		if test.Status == evergreen.TestFailedStatus {
+			f.Tests = append(f.Tests, testName(test.TestFile))
+		}
+	}
+	return f
+}
+
+// testName strips the directories from a test file's path.
+func testName(path string) string {
+	path = strings.TrimRight(path, `/\`)
+	if i := strings.LastIndexAny(path, `/\`); i != -1 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// SearchProjects returns the JIRA projects to search for a failure in the
+// given Evergreen project: the ones configured for failure matching, and the
+// ones the project files its JIRA alerts in.
+func SearchProjects(conf evergreen.JiraConfig, ref *model.ProjectRef) []string {
+	projects := append([]string{}, conf.FailureProjects...)
+	if ref != nil {
+		for _, alertConfs := range ref.Alerts {
+			for _, alertConf := range alertConfs {
+				if alertConf.Provider != alerts.JiraProvider {
+					continue
+				}
+				if project, ok := alertConf.Settings["project"].(string); ok && project != "" {
+					projects = append(projects, project)
+				}
+			}
+		}
+	}
+	projects = util.UniqueStrings(projects)
+	sort.Strings(projects)
+	return projects
+}
+
+// Matcher suggests open tickets in a set of JIRA projects for failures.
+type Matcher struct {
+	searcher Searcher
+	projects []string
+}
+
+// NewMatcher returns a Matcher that searches the given JIRA projects.
+func NewMatcher(searcher Searcher, projects []string) *Matcher {
+	return &Matcher{searcher: searcher, projects: projects}
+}
+
+// Suggest searches for open tickets that mention the failure's tests or
+// task, and returns the most relevant ones, best first.
+func (m *Matcher) Suggest(f Failure) ([]Suggestion, error) {
+	if len(m.projects) == 0 {
+		return nil, fmt.Errorf("no JIRA projects to search")
+	}
+	query := BuildQuery(m.projects, f)
+	results, err := m.searcher.JQLSearch(query, 0, maxSearchResults)
+	if err != nil {
+		return nil, fmt.Errorf("error searching JIRA: %v", err)
+	}
+
+	suggestions := Rank(results.Issues, f)
+	for i := range suggestions {
+		suggestions[i].URL = m.searcher.IssueURL(suggestions[i].Key)
+	}
+	return suggestions, nil
+}
+
+// BuildQuery returns a JQL query for the unresolved tickets in the given
+// projects that mention any of the failure's tests, or the task in their
+// summary. The most recently updated tickets come first.
+func BuildQuery(projects []string, f Failure) string {
+	quoted := make([]string, 0, len(projects))
+	for _, p := range projects {
+		quoted = append(quoted, jqlString(p))
+	}
+
+	terms := []string{}
+	tests := f.Tests
+	if len(tests) > maxQueryTests {
+		tests = tests[:maxQueryTests]
+	}
+	for _, test := range tests {
+		terms = append(terms, fmt.Sprintf("text ~ %v", jqlPhrase(test)))
+	}
+	if f.TaskName != "" {
+		terms = append(terms, fmt.Sprintf("summary ~ %v", jqlPhrase(f.TaskName)))
+	}
+
+	query := fmt.Sprintf("project in (%v) AND resolution is EMPTY", strings.Join(quoted, ", "))
+	if len(terms) > 0 {
+		query += fmt.Sprintf(" AND (%v)", strings.Join(terms, " OR "))
+	}
+	return query + " ORDER BY updated DESC"
+}
+
+// jqlString quotes a value for use in a JQL query.
+func jqlString(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return `"` + value + `"`
+}
+
+// jqlPhrase quotes a value so that JIRA's text search looks for it as a
+// whole, rather than for any of its words.
+func jqlPhrase(value string) string {
+	value = strings.Replace(value, `"`, "", -1)
+	return jqlString(`"` + value + `"`)
+}
+
+// Rank scores each ticket by how much of the failure it mentions, and returns
+// the ones that mention its tests or task, best first. Tickets that score the same keep their
+// order.
+func Rank(tickets []thirdparty.JiraTicket, f Failure) []Suggestion {
+	suggestions := []Suggestion{}
+	for _, ticket := range tickets {
+		if ticket.Fields == nil {
+			continue
+		}
+		summary := strings.ToLower(ticket.Fields.Summary)
+		description := strings.ToLower(ticket.Fields.Description)
+		s := Suggestion{Key: ticket.Key, Summary: ticket.Fields.Summary, MatchedTests: []string{}}
+		if ticket.Fields.Status != nil {
+			s.Status = ticket.Fields.Status.Name
+		}
+
+		for _, test := range f.Tests {
+			name := strings.ToLower(test)
+			switch {
+			case strings.Contains(summary, name):
+				s.Score += testInSummaryScore
+			case strings.Contains(description, name):
+				s.Score += testInDescriptionScore
+			default:
+				continue
+			}
+			s.MatchedTests = append(s.MatchedTests, test)
+		}
+
+		if name := strings.ToLower(f.TaskName); name != "" {
+			switch {
+			case strings.Contains(summary, name):
+				s.Score += taskInSummaryScore
+				s.MatchedTask = true
+			case strings.Contains(description, name):
+				s.Score += taskInDescriptionScore
+				s.MatchedTask = true
+			}
+		}
+
+		if variant := strings.ToLower(f.BuildVariant); variant != "" &&
+			(strings.Contains(summary, variant) || strings.Contains(description, variant)) {
+			s.Score += variantMentionedScore
+		}
+
+		// mentioning the variant alone doesn't make a ticket relevant
+		if len(s.MatchedTests) > 0 || s.MatchedTask {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	sort.Stable(byScore(suggestions))
+	if len(suggestions) > MaxSuggestions {
+		suggestions = suggestions[:MaxSuggestions]
+	}
+	return suggestions
+}
+
+type byScore []Suggestion
+
+func (s byScore) Len() int           { return len(s) }
+func (s byScore) Less(i, j int) bool { return s[i].Score > s[j].Score }
+func (s byScore) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,141 @@
+package buildbaron
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func ticket(key, summary, description string) thirdparty.JiraTicket {
+	return thirdparty.JiraTicket{
+		Key: key,
+		Fields: &thirdparty.TicketFields{
+			Summary:     summary,
+			Description: description,
+			Status:      &thirdparty.JiraStatus{Name: "Open"},
+		},
+	}
+}
+
+// stubJira starts a JIRA server that answers every search with the given
+// tickets, and records the queries it is sent.
+func stubJira(tickets []thirdparty.JiraTicket, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/latest/search" {
+			http.NotFound(w, r)
+			return
+		}
+		*queries = append(*queries, r.FormValue("jql"))
+		json.NewEncoder(w).Encode(thirdparty.JiraSearchResults{
+			MaxResults: len(tickets),
+			Total:      len(tickets),
+			Issues:     tickets,
+		})
+	}))
+}
+
+func TestFailureFromTask(t *testing.T) {
+	Convey("A failure should name the task's failed tests without their paths", t, func() {
+		f := FailureFromTask(&task.Task{
+			DisplayName:  "jsCore",
+			BuildVariant: "linux-64",
+			TestResults: []task.TestResult{
+				{Status: evergreen.TestFailedStatus, TestFile: "jstests/core/basic.js"},
+				{Status: evergreen.TestSucceededStatus, TestFile: "jstests/core/other.js"},
+				{Status: evergreen.TestFailedStatus, TestFile: `C:\jstests\core\windows.js`},
+			},
+		})
+		So(f.TaskName, ShouldEqual, "jsCore")
+		So(f.BuildVariant, ShouldEqual, "linux-64")
+		So(f.Tests, ShouldResemble, []string{"basic.js", "windows.js"})
+	})
+}
+
+func TestSearchProjects(t *testing.T) {
+	Convey("The projects searched should include the ones alerts are filed in", t, func() {
+		ref := &model.ProjectRef{
+			Alerts: map[string][]model.AlertConfig{
+				"task_failed": {
+					{Provider: "jira", Settings: map[string]interface{}{"project": "BFG", "issue": "Build Failure"}},
+					{Provider: "email", Settings: map[string]interface{}{"recipient": "a@b.com"}},
+				},
+			},
+		}
+		conf := evergreen.JiraConfig{FailureProjects: []string{"BF", "BFG"}}
+		So(SearchProjects(conf, ref), ShouldResemble, []string{"BF", "BFG"})
+		So(SearchProjects(evergreen.JiraConfig{}, nil), ShouldResemble, []string{})
+	})
+}
+
+func TestBuildQuery(t *testing.T) {
+	Convey("The query should search the projects for the tests and the task", t, func() {
+		q := BuildQuery([]string{"BF"}, Failure{TaskName: "jsCore", Tests: []string{"basic.js", `we"ird.js`}})
+		So(q, ShouldEqual, `project in ("BF") AND resolution is EMPTY AND `+
+			`(text ~ "\"basic.js\"" OR text ~ "\"weird.js\"" OR summary ~ "\"jsCore\"") ORDER BY updated DESC`)
+
+		Convey("and should only search the projects if there is nothing to look for", func() {
+			q := BuildQuery([]string{"BF", "EVG"}, Failure{})
+			So(q, ShouldEqual, `project in ("BF", "EVG") AND resolution is EMPTY ORDER BY updated DESC`)
+		})
+	})
+}
+
+func TestRank(t *testing.T) {
+	Convey("With a failure and some tickets", t, func() {
+		f := Failure{TaskName: "jsCore", BuildVariant: "linux-64", Tests: []string{"basic.js", "index.js"}}
+		tickets := []thirdparty.JiraTicket{
+			ticket("BF-1", "Failure: jsCore on linux-64", "nothing about tests"),
+			ticket("BF-2", "basic.js and index.js fail", ""),
+			ticket("BF-3", "something else on linux-64", "also unrelated"),
+			ticket("BF-4", "flaky test", "see basic.js"),
+		}
+
+		Convey("tickets should be ordered by how much they mention", func() {
+			suggestions := Rank(tickets, f)
+			So(len(suggestions), ShouldEqual, 3)
+			So(suggestions[0].Key, ShouldEqual, "BF-2")
+			So(suggestions[0].Score, ShouldEqual, 6)
+			So(suggestions[0].MatchedTests, ShouldResemble, []string{"basic.js", "index.js"})
+			So(suggestions[1].Key, ShouldEqual, "BF-1")
+			So(suggestions[1].MatchedTask, ShouldBeTrue)
+			So(suggestions[1].Score, ShouldEqual, 3)
+			So(suggestions[2].Key, ShouldEqual, "BF-4")
+			So(suggestions[2].Status, ShouldEqual, "Open")
+		})
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	Convey("With a stub JIRA server", t, func() {
+		queries := []string{}
+		server := stubJira([]thirdparty.JiraTicket{
+			ticket("BF-10", "Failure: jsCore (basic.js)", ""),
+			ticket("BF-11", "unrelated", ""),
+		}, &queries)
+		defer server.Close()
+		jira := thirdparty.NewJiraHandler(server.URL, "user", "password")
+
+		Convey("suggestions should be ranked and link to the tickets", func() {
+			m := NewMatcher(&jira, []string{"BF"})
+			suggestions, err := m.Suggest(Failure{TaskName: "jsCore", Tests: []string{"basic.js"}})
+			So(err, ShouldBeNil)
+			So(len(queries), ShouldEqual, 1)
+			So(queries[0], ShouldContainSubstring, `text ~ "\"basic.js\""`)
+			So(len(suggestions), ShouldEqual, 1)
+			So(suggestions[0].Key, ShouldEqual, "BF-10")
+			So(suggestions[0].URL, ShouldEqual, server.URL+"/browse/BF-10")
+		})
+
+		Convey("there should be an error without projects to search", func() {
+			_, err := NewMatcher(&jira, nil).Suggest(Failure{TaskName: "jsCore"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
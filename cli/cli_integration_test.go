@@ -140,7 +140,7 @@ func TestCLIFetchSource(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(testTask, ShouldNotBeNil)
 
-		err = fetchSource(ac, rc, "", testTask.Id, false)
+		err = fetchSource(ac, rc, "", "", testTask.Id, false)
 		So(err, ShouldBeNil)
 
 		fileStat, err := os.Stat("./source-patch-1_sample/README.md")
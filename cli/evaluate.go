@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 
 	"github.com/evergreen-ci/evergreen/model"
 	"gopkg.in/yaml.v2"
@@ -11,8 +12,9 @@ import (
 // EvaluateCommand reads in a project config, expanding tags and matrix definitions,
 // then prints the expanded definitions back out as yaml.
 type EvaluateCommand struct {
-	Tasks    bool `short:"t" long:"tasks" description:"only show task and function definitions"`
-	Variants bool `short:"v" long:"variants" description:"only show variant definitions"`
+	Tasks    bool   `short:"t" long:"tasks" description:"only show task and function definitions"`
+	Variants bool   `short:"v" long:"variants" description:"only show variant definitions"`
+	Root     string `short:"r" long:"root" description:"directory that included files are read from (defaults to the current directory)"`
 }
 
 func (ec *EvaluateCommand) Execute(args []string) error {
@@ -25,7 +27,7 @@ func (ec *EvaluateCommand) Execute(args []string) error {
 	}
 
 	p := &model.Project{}
-	err = model.LoadProjectInto(configBytes, "", p)
+	err = model.LoadProjectWithIncludes(configBytes, "", p, localIncludes(ec.Root))
 	if err != nil {
 		return fmt.Errorf("error loading project: %v", err)
 	}
@@ -57,3 +59,15 @@ func (ec *EvaluateCommand) Execute(args []string) error {
 
 	return nil
 }
+
+// localIncludes returns an IncludeFetcher that reads files included by a
+// project config from the local checkout at root. Files in modules aren't
+// checked out, so they can't be read.
+func localIncludes(root string) model.IncludeFetcher {
+	return func(module *model.Module, path string) ([]byte, error) {
+		if module != nil {
+			return nil, fmt.Errorf("files in module '%v' can't be read locally", module.Name)
+		}
+		return ioutil.ReadFile(filepath.Join(root, path))
+	}
+}
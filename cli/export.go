@@ -15,6 +15,7 @@ var (
 	HostUtilizationStat     = "host"
 	AverageScheduledToStart = "avg"
 	OptimalMakespanStat     = "makespan"
+	CostStat                = "cost"
 )
 
 // ExportCommand is used to export statistics
@@ -25,11 +26,13 @@ type ExportCommand struct {
 	Granularity string `long:"granularity" description:"set the granularity, default hour, options are 'second', 'minute', 'hour'"`
 	Days        int    `long:"days" description:"set the number of days, default 1, max of 30 days back"`
 	StatsType   string `long:"stat" 
-						description:"include the type of stats - 'host' for host utilization,'avg' for average scheduled to start times, 'makespan' for makespan ratios" 
+						description:"include the type of stats - 'host' for host utilization,'avg' for average scheduled to start times, 'makespan' for makespan ratios, 'cost' for task and idle host cost" 
 						required:"true"`
 	DistroId string `long:"distro" description:"distro id - required for average scheduled to start times"`
 	Number   int    `long:"number" description:"set the number of revisions (for getting build makespan), default 100"`
 	Filepath string `long:"filepath" description:"path to directory where csv file is to be saved"`
+	Project  string `long:"project" description:"project id - limits cost statistics to one project"`
+	GroupBy  string `long:"group_by" description:"comma-separated dimensions to roll up cost statistics by: 'day', 'project', 'variant', 'task', 'requester' (default all)"`
 }
 
 func (ec *ExportCommand) Execute(args []string) error {
@@ -89,6 +92,11 @@ func (ec *ExportCommand) Execute(args []string) error {
 		if err != nil {
 			return err
 		}
+	case CostStat:
+		body, err = rc.GetCostReport(ec.Days, ec.Project, ec.GroupBy, isCSV)
+		if err != nil {
+			return err
+		}
 
 	default:
 		return fmt.Errorf("%v is not a valid stats type. The current valid one is 'host'", ec.StatsType)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,8 +16,10 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/service"
+	"github.com/evergreen-ci/evergreen/store"
 	"github.com/evergreen-ci/evergreen/util"
 )
 
@@ -27,6 +30,7 @@ type FetchCommand struct {
 	GlobalOpts *Options `no-flag:"true"`
 
 	Source    bool   `long:"source" description:"clones the source for the given task"`
+	Reproduce bool   `long:"reproduce" description:"set up the task's working directory with its expansions and a script of its commands"`
 	Artifacts bool   `long:"artifacts" description:"fetch artifacts for the task and all its recursive dependents"`
 	Shallow   bool   `long:"shallow" description:"don't recursively download artifacts from dependency tasks"`
 	NoPatch   bool   `long:"no-patch" description:"when using --source with a patch task, skip applying the patch"`
@@ -55,11 +59,28 @@ func (fc *FetchCommand) Execute(args []string) error {
 		return fmt.Errorf("must specify a task ID with -t.")
 	}
 
-	if !fc.Source && !fc.Artifacts {
-		return fmt.Errorf("must specify at least one of either --artifacts or --source.")
+	if !fc.Source && !fc.Artifacts && !fc.Reproduce {
+		return fmt.Errorf("must specify at least one of --artifacts, --source or --reproduce.")
+	}
+	sourceDir := ""
+	if fc.Reproduce {
+		// everything else is fetched into the task's directory, where the
+		// agent would have put it
+		taskDir, taskSourceDir, err := reproduceTask(rc, wd, fc.TaskId)
+		if err != nil {
+			// only project admins may see a task's expansions; the source
+			// and artifacts are still worth fetching without them
+			apiErr, ok := err.(APIError)
+			if !ok || apiErr.code != http.StatusForbidden || (!fc.Source && !fc.Artifacts) {
+				return err
+			}
+			fmt.Printf("Warning: not reproducing the task's working directory: %v\n", err)
+		} else {
+			wd, sourceDir = taskDir, taskSourceDir
+		}
 	}
 	if fc.Source {
-		err = fetchSource(ac, rc, wd, fc.TaskId, fc.NoPatch)
+		err = fetchSource(ac, rc, wd, sourceDir, fc.TaskId, fc.NoPatch)
 		if err != nil {
 			return err
 		}
@@ -90,7 +111,71 @@ func executeCommands(cmds []*exec.Cmd, verbose bool) error {
 	return nil
 }
 
-func fetchSource(ac, rc *APIClient, rootPath, taskId string, noPatch bool) error {
+// reproduceTask creates the directory the agent would have run the task in,
+// and writes the task's expansions and a script of its commands into it. It
+// returns the task's directory and the directory, usually relative to it,
+// that the task clones its source into.
+func reproduceTask(rc *APIClient, rootPath, taskId string) (string, string, error) {
+	task, err := rc.GetTask(taskId)
+	if err != nil {
+		return "", "", err
+	}
+	if task == nil {
+		return "", "", fmt.Errorf("task not found.")
+	}
+
+	config, err := rc.GetConfig(task.Version)
+	if err != nil {
+		return "", "", err
+	}
+
+	taskExpansions, err := rc.GetTaskExpansions(taskId)
+	if err != nil {
+		return "", "", err
+	}
+
+	taskDir, err := filepath.Abs(filepath.Join(rootPath, model.TaskDirectoryName(task.Id, task.Execution)))
+	if err != nil {
+		return "", "", err
+	}
+	if err = os.MkdirAll(taskDir, 0755); err != nil {
+		return "", "", err
+	}
+
+	expansions := command.NewExpansions(taskExpansions.Expansions)
+	expansions.Put("workdir", taskDir)
+
+	expansionsYAML, err := model.TaskExpansionsYAML(expansions)
+	if err != nil {
+		return "", "", err
+	}
+	expansionsPath := filepath.Join(taskDir, model.TaskExpansionsFileName)
+	if err = ioutil.WriteFile(expansionsPath, expansionsYAML, 0600); err != nil {
+		return "", "", err
+	}
+
+	script, err := config.TaskScript(task.BuildVariant, task.DisplayName, expansions)
+	if err != nil {
+		return "", "", err
+	}
+	scriptPath := filepath.Join(taskDir, model.TaskScriptFileName)
+	if err = ioutil.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		return "", "", err
+	}
+
+	sourceDir, err := expansions.ExpandString(config.TaskSourceDirectory(task.BuildVariant, task.DisplayName))
+	if err != nil {
+		return "", "", err
+	}
+
+	fmt.Printf("Wrote expansions to %v\n", expansionsPath)
+	fmt.Printf("Wrote the task's commands to %v\n", scriptPath)
+	return taskDir, sourceDir, nil
+}
+
+// fetchSource clones the task's source into cloneDir under rootPath, or into
+// a directory named after the task's project and revision if cloneDir is empty.
+func fetchSource(ac, rc *APIClient, rootPath, cloneDir, taskId string, noPatch bool) error {
 	task, err := rc.GetTask(taskId)
 	if err != nil {
 		return err
@@ -109,20 +194,25 @@ func fetchSource(ac, rc *APIClient, rootPath, taskId string, noPatch bool) error
 		return err
 	}
 
-	cloneDir := util.CleanForPath(fmt.Sprintf("source-%v", task.Project))
+	defaultCloneDir := util.CleanForPath(fmt.Sprintf("source-%v", task.Project))
 	var patch *service.RestPatch
 	if task.Requester == evergreen.PatchVersionRequester {
-		cloneDir = util.CleanForPath(fmt.Sprintf("source-patch-%v_%v", task.PatchNumber, task.Project))
+		defaultCloneDir = util.CleanForPath(fmt.Sprintf("source-patch-%v_%v", task.PatchNumber, task.Project))
 		patch, err = rc.GetPatch(task.PatchId)
 		if err != nil {
 			return err
 		}
 	} else {
 		if len(task.Revision) >= 5 {
-			cloneDir = util.CleanForPath(fmt.Sprintf("source-%v-%v", task.Project, task.Revision[0:6]))
+			defaultCloneDir = util.CleanForPath(fmt.Sprintf("source-%v-%v", task.Project, task.Revision[0:6]))
 		}
 	}
-	cloneDir = filepath.Join(rootPath, cloneDir)
+	if cloneDir == "" {
+		cloneDir = defaultCloneDir
+	}
+	if !filepath.IsAbs(cloneDir) {
+		cloneDir = filepath.Join(rootPath, cloneDir)
+	}
 
 	err = cloneSource(task, project, config, cloneDir)
 	if err != nil {
@@ -330,6 +420,8 @@ func searchDependencies(rc *APIClient, seed *service.RestTask, found map[string]
 type artifactDownload struct {
 	url  string
 	path string
+	// sha256 is the checksum the download is verified against, if known
+	sha256 string
 }
 
 func getArtifactFolderName(task *service.RestTask) string {
@@ -363,7 +455,7 @@ func getUrlsChannel(rc *APIClient, seed *service.RestTask, shallow bool) (chan a
 		for _, t := range allTasks {
 			for _, f := range t.Files {
 				directoryName := getArtifactFolderName(t)
-				urls <- artifactDownload{f.URL, directoryName}
+				urls <- artifactDownload{f.URL, directoryName, f.SHA256}
 			}
 		}
 		close(urls)
@@ -473,13 +565,18 @@ func downloadUrls(root string, urls chan artifactDownload, workers int) error {
 				justFile = filepath.Base(fileName)
 				fmt.Printf("(worker %v) Downloading %v to directory %s%s\n", workerId, justFile, u.path, sizeLog)
 				//sizeTracker := util.SizeTrackingReader{0, resp.Body}
-				_, err = io.Copy(out, resp.Body)
+				body := store.NewVerifyingReader(resp.Body, justFile, u.sha256)
+				_, err = io.Copy(out, body)
+				body.Close()
+				out.Close()
 				if err != nil {
+					if _, ok := err.(store.ErrChecksumMismatch); ok {
+						// don't leave a corrupt file behind
+						os.Remove(fileName)
+					}
 					errs <- fmt.Errorf("Couldn't download %v: %v", u.url, err)
 					continue
 				}
-				resp.Body.Close()
-				out.Close()
 				counter++
 			}
 		}(i)
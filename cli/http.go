@@ -431,6 +431,28 @@ func (ac *APIClient) GetTask(taskId string) (*service.RestTask, error) {
 	return &reply, nil
 }
 
+// GetTaskExpansions fetches the expansions for a task, minus its project's
+// private variables.
+func (ac *APIClient) GetTaskExpansions(taskId string) (*service.RestTaskExpansions, error) {
+	resp, err := ac.get(fmt.Sprintf("tasks/%v/expansions", taskId), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewAPIError(resp)
+	}
+
+	reply := service.RestTaskExpansions{}
+	if err := util.ReadJSONInto(resp.Body, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 // GetHostUtilizationStats takes in an integer granularity, which is in seconds, and the number of days back and makes a
 // REST API call to get host utilization statistics.
 func (ac *APIClient) GetHostUtilizationStats(granularity, daysBack int, csv bool) (io.ReadCloser, error) {
@@ -486,3 +508,57 @@ func (ac *APIClient) GetOptimalMakespans(numberBuilds int, csv bool) (io.ReadClo
 
 	return resp.Body, nil
 }
+
+// GetCostReport makes a REST API call to get the cost of tasks and idle hosts over the given number of days,
+// optionally limited to a project and rolled up by the given comma-separated dimensions.
+func (ac *APIClient) GetCostReport(daysBack int, projectId, groupBy string, csv bool) (io.ReadCloser, error) {
+	params := url.Values{}
+	params.Set("numberDays", fmt.Sprintf("%v", daysBack))
+	params.Set("csv", fmt.Sprintf("%v", csv))
+	if projectId != "" {
+		params.Set("project", projectId)
+	}
+	if groupBy != "" {
+		params.Set("group_by", groupBy)
+	}
+	resp, err := ac.get("cost/report?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewAPIError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// SearchLogs makes a REST API call to search the logs of a task or, if versionId
+// is set, of every task in a version. The given parameters are passed through as
+// the query string.
+func (ac *APIClient) SearchLogs(taskId, versionId string, params url.Values) (*service.RestLogSearchResults, error) {
+	path := fmt.Sprintf("tasks/%v/log_search", taskId)
+	if versionId != "" {
+		path = fmt.Sprintf("versions/%v/log_search", versionId)
+	}
+	resp, err := ac.get(path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewAPIError(resp)
+	}
+
+	reply := service.RestLogSearchResults{}
+	if err := util.ReadJSONInto(resp.Body, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
@@ -26,6 +26,7 @@ func main() {
 	parser.AddCommand("validate", "validate a config file", "", &cli.ValidateCommand{GlobalOpts: &opts})
 	parser.AddCommand("evaluate", "display a project file's evaluated and expanded form", "", &cli.EvaluateCommand{})
 	parser.AddCommand("fetch", "fetch data associated with a task", "", &cli.FetchCommand{GlobalOpts: &opts})
+	parser.AddCommand("search-logs", "search the logs of a task or version for a regular expression", "", &cli.SearchLogsCommand{GlobalOpts: &opts})
 	parser.AddCommand("export", "export statistics as csv or json for given options", "", &cli.ExportCommand{GlobalOpts: &opts})
 	_, err := parser.Parse()
 	if err != nil {
@@ -94,6 +94,7 @@ type ValidateCommand struct {
 	Positional struct {
 		FileName string `positional-arg-name:"filename" description:"path to an evergreen project file"`
 	} `positional-args:"1" required:"yes"`
+	Root string `short:"r" long:"root" description:"directory that included files are read from (defaults to the current directory)"`
 }
 
 // CancelPatchCommand is used to cancel a patch.
@@ -225,6 +226,12 @@ func (vc *ValidateCommand) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+	// the API server can't read the files the config includes, so send it
+	// the config with them merged in
+	confFile, err = model.MergeIncludes(confFile, localIncludes(vc.Root))
+	if err != nil {
+		return err
+	}
 	projErrors, err := ac.ValidateLocalConfig(confFile)
 	if err != nil {
 		return nil
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/service"
+)
+
+// SearchLogsCommand searches the logs of a task or version on the server
+// without downloading them.
+type SearchLogsCommand struct {
+	GlobalOpts *Options `no-flag:"true"`
+
+	TaskId     string `short:"t" long:"task" description:"task whose logs should be searched"`
+	VersionId  string `short:"v" long:"version" description:"version whose tasks' logs should be searched"`
+	Pattern    string `short:"e" long:"regexp" description:"regular expression to search for" required:"true"`
+	Context    int    `short:"C" long:"context" description:"lines of context to show around each match"`
+	LogType    string `long:"type" description:"log type to search: 'T' (task), 'E' (agent), 'S' (system) or 'ALL', defaults to 'ALL'"`
+	Execution  *int   `long:"execution" description:"task execution to search, defaults to the latest"`
+	NoTestLogs bool   `long:"no-test-logs" description:"don't search the task's test logs"`
+	Limit      int    `long:"limit" description:"maximum number of matches to return"`
+}
+
+func (slc *SearchLogsCommand) Execute(args []string) error {
+	if (slc.TaskId == "") == (slc.VersionId == "") {
+		return fmt.Errorf("must specify exactly one of --task or --version")
+	}
+	if _, err := model.ParseLogSearchPattern(slc.Pattern); err != nil {
+		return err
+	}
+
+	ac, rc, _, err := getAPIClients(slc.GlobalOpts)
+	if err != nil {
+		return err
+	}
+	notifyUserUpdate(ac)
+
+	params := url.Values{}
+	params.Set("q", slc.Pattern)
+	params.Set("context", strconv.Itoa(slc.Context))
+	params.Set("test_logs", strconv.FormatBool(!slc.NoTestLogs))
+	if slc.LogType != "" {
+		params.Set("type", slc.LogType)
+	}
+	if slc.Execution != nil {
+		params.Set("execution", strconv.Itoa(*slc.Execution))
+	}
+	if slc.Limit > 0 {
+		params.Set("limit", strconv.Itoa(slc.Limit))
+	}
+
+	results, err := rc.SearchLogs(slc.TaskId, slc.VersionId, params)
+	if err != nil {
+		return err
+	}
+	printLogSearchResults(os.Stdout, results, slc.VersionId != "")
+	return nil
+}
+
+// printLogSearchResults writes matches in a grep-like format, with context
+// lines separated from matching lines by '-' instead of ':'.
+func printLogSearchResults(out io.Writer, results *service.RestLogSearchResults, showTask bool) {
+	for i, match := range results.Matches {
+		if i > 0 && (len(match.Before) > 0 || len(match.After) > 0) {
+			fmt.Fprintln(out, "--")
+		}
+		prefix := logSearchMatchSource(match)
+		if showTask {
+			prefix = match.TaskId + ":" + prefix
+		}
+		lineNum := match.LineNum - len(match.Before)
+		for _, line := range match.Before {
+			fmt.Fprintf(out, "%v-%v-%v\n", prefix, lineNum, line)
+			lineNum++
+		}
+		fmt.Fprintf(out, "%v:%v:%v\n", prefix, match.LineNum, match.Line)
+		for j, line := range match.After {
+			fmt.Fprintf(out, "%v-%v-%v\n", prefix, match.LineNum+j+1, line)
+		}
+	}
+	if results.Truncated {
+		fmt.Fprintf(out, "(results truncated after %v matches)\n", len(results.Matches))
+	}
+}
+
+// logSearchMatchSource describes which log a match came from.
+func logSearchMatchSource(match model.LogSearchMatch) string {
+	if match.Source == model.LogSearchSourceTestLog {
+		return "test/" + match.TestName
+	}
+	return match.LogType
+}
@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -114,6 +115,29 @@ type CloudCostCalculator interface {
 	CostForDuration(host *host.Host, start time.Time, end time.Time) (float64, error)
 }
 
+// CostForHourlyRate returns what a span of time costs at the given hourly
+// rate, for providers whose hosts cost a flat rate set in their distros.
+func CostForHourlyRate(rate float64, start, end time.Time) (float64, error) {
+	if end.Before(start) || util.IsZeroTime(start) || util.IsZeroTime(end) {
+		return 0, fmt.Errorf("task timing data is malformed")
+	}
+	if rate < 0 {
+		return 0, fmt.Errorf("hourly rate %v is negative", rate)
+	}
+	return rate * end.Sub(start).Hours(), nil
+}
+
+// CloudStopStarter is an interface for cloud managers that can stop a host
+// without destroying it, preserving its disks, and later start it again.
+type CloudStopStarter interface {
+	// StopInstance stops the host in the underlying provider and marks it stopped
+	StopInstance(host *host.Host) error
+
+	// StartInstance starts a stopped host and marks it running once it is
+	// reachable again, updating its DNS name if the provider changed it
+	StartInstance(host *host.Host) error
+}
+
 // HostOptions is a struct of options that are commonly passed around when creating a
 // new cloud host.
 type HostOptions struct {
@@ -195,3 +219,25 @@ func (cloudHost *CloudHost) GetDNSName() (string, error) {
 func (cloudHost *CloudHost) GetSSHOptions() ([]string, error) {
 	return cloudHost.CloudMgr.GetSSHOptions(cloudHost.Host, cloudHost.KeyPath)
 }
+
+// CanStopAndStart returns true if the host's provider can stop and restart it.
+func (cloudHost *CloudHost) CanStopAndStart() bool {
+	_, ok := cloudHost.CloudMgr.(CloudStopStarter)
+	return ok
+}
+
+func (cloudHost *CloudHost) StopInstance() error {
+	stopStarter, ok := cloudHost.CloudMgr.(CloudStopStarter)
+	if !ok {
+		return fmt.Errorf("provider for host %v does not support stopping hosts", cloudHost.Host.Id)
+	}
+	return stopStarter.StopInstance(cloudHost.Host)
+}
+
+func (cloudHost *CloudHost) StartInstance() error {
+	stopStarter, ok := cloudHost.CloudMgr.(CloudStopStarter)
+	if !ok {
+		return fmt.Errorf("provider for host %v does not support starting hosts", cloudHost.Host.Id)
+	}
+	return stopStarter.StartInstance(cloudHost.Host)
+}
@@ -36,14 +36,18 @@ type Settings struct {
 	SizeId   int `mapstructure:"size_id" json:"size_id" bson:"size_id"`
 	RegionId int `mapstructure:"region_id" json:"region_id" bson:"region_id"`
 	SSHKeyId int `mapstructure:"ssh_key_id" json:"ssh_key_id" bson:"ssh_key_id"`
+
+	// HourlyRate is what a droplet of this size costs per hour
+	HourlyRate float64 `mapstructure:"hourly_rate" json:"hourly_rate,omitempty" bson:"hourly_rate,omitempty"`
 }
 
 var (
 	// bson fields for the Settings struct
-	ImageIdKey  = bsonutil.MustHaveTag(Settings{}, "ImageId")
-	SizeIdKey   = bsonutil.MustHaveTag(Settings{}, "SizeId")
-	RegionIdKey = bsonutil.MustHaveTag(Settings{}, "RegionId")
-	SSHKeyIdKey = bsonutil.MustHaveTag(Settings{}, "SSHKeyId")
+	ImageIdKey    = bsonutil.MustHaveTag(Settings{}, "ImageId")
+	SizeIdKey     = bsonutil.MustHaveTag(Settings{}, "SizeId")
+	RegionIdKey   = bsonutil.MustHaveTag(Settings{}, "RegionId")
+	SSHKeyIdKey   = bsonutil.MustHaveTag(Settings{}, "SSHKeyId")
+	HourlyRateKey = bsonutil.MustHaveTag(Settings{}, "HourlyRate")
 )
 
 //Validate checks that the settings from the config file are sane.
@@ -64,6 +68,10 @@ func (self *Settings) Validate() error {
 		return fmt.Errorf("SSH Key ID must not be blank")
 	}
 
+	if self.HourlyRate < 0 {
+		return fmt.Errorf("Hourly rate must not be negative")
+	}
+
 	return nil
 }
 
@@ -298,3 +306,13 @@ func (digoMgr *DigitalOceanManager) TimeTilNextPayment(host *host.Host) time.Dur
 
 	return nextPaymentTime.Sub(now)
 }
+
+// CostForDuration returns the cost of running a droplet between the given
+// start and end times, at the hourly rate set in its distro.
+func (digoMgr *DigitalOceanManager) CostForDuration(h *host.Host, start, end time.Time) (float64, error) {
+	digoSettings := &Settings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, digoSettings); err != nil {
+		return 0, fmt.Errorf("Error decoding params for distro %v: %v", h.Distro.Id, err)
+	}
+	return cloud.CostForHourlyRate(digoSettings.HourlyRate, start, end)
+}
@@ -50,6 +50,10 @@ type Settings struct {
 	ClientPort int        `mapstructure:"client_port" json:"client_port" bson:"client_port"`
 	PortRange  *portRange `mapstructure:"port_range" json:"port_range" bson:"port_range"`
 	Auth       *auth      `mapstructure:"auth" json:"auth" bson:"auth"`
+
+	// HourlyRate is what a container costs per hour, e.g. its share of the
+	// docker host
+	HourlyRate float64 `mapstructure:"hourly_rate" json:"hourly_rate,omitempty" bson:"hourly_rate,omitempty"`
 }
 
 var (
@@ -60,6 +64,7 @@ var (
 	ClientPort = bsonutil.MustHaveTag(Settings{}, "ClientPort")
 	PortRange  = bsonutil.MustHaveTag(Settings{}, "PortRange")
 	Auth       = bsonutil.MustHaveTag(Settings{}, "Auth")
+	HourlyRate = bsonutil.MustHaveTag(Settings{}, "HourlyRate")
 
 	// bson fields for the portRange struct
 	MinPort = bsonutil.MustHaveTag(portRange{}, "MinPort")
@@ -191,6 +196,10 @@ func (settings *Settings) Validate() error {
 		}
 	}
 
+	if settings.HourlyRate < 0 {
+		return fmt.Errorf("Hourly rate must not be negative")
+	}
+
 	if settings.Auth == nil {
 		return fmt.Errorf("Authentication materials must not be blank")
 	} else if settings.Auth.Cert == "" {
@@ -360,9 +369,12 @@ func (dockerMgr *DockerManager) TerminateInstance(host *host.Host) error {
 		return err
 	}
 
-	err = dockerClient.StopContainer(host.Id, TimeoutSeconds)
-	if err != nil {
-		return evergreen.Logger.Errorf(slogger.ERROR, "Failed to stop container '%v': %v", host.Id, err)
+	// stopped spawn hosts have nothing left to stop
+	if host.Status != evergreen.HostStopped {
+		err = dockerClient.StopContainer(host.Id, TimeoutSeconds)
+		if _, notRunning := err.(*docker.ContainerNotRunning); err != nil && !notRunning {
+			return evergreen.Logger.Errorf(slogger.ERROR, "Failed to stop container '%v': %v", host.Id, err)
+		}
 	}
 
 	err = dockerClient.RemoveContainer(
@@ -377,6 +389,49 @@ func (dockerMgr *DockerManager) TerminateInstance(host *host.Host) error {
 	return host.Terminate()
 }
 
+//StopInstance stops a container without removing it, so that it can be
+//started again later with its filesystem intact.
+func (dockerMgr *DockerManager) StopInstance(host *host.Host) error {
+	if host.Status != evergreen.HostRunning {
+		return fmt.Errorf("Can not stop %v - host status is '%v', not '%v'",
+			host.Id, host.Status, evergreen.HostRunning)
+	}
+	if host.RunningTask != "" {
+		return fmt.Errorf("Can not stop %v - it is running task %v", host.Id, host.RunningTask)
+	}
+
+	dockerClient, _, err := generateClient(&host.Distro)
+	if err != nil {
+		return err
+	}
+
+	if err = dockerClient.StopContainer(host.Id, TimeoutSeconds); err != nil {
+		return evergreen.Logger.Errorf(slogger.ERROR, "Failed to stop container '%v': %v", host.Id, err)
+	}
+
+	return host.SetStopped()
+}
+
+//StartInstance starts a stopped container. The container keeps the port
+//bindings it was created with, so its DNS name does not change.
+func (dockerMgr *DockerManager) StartInstance(host *host.Host) error {
+	if host.Status != evergreen.HostStopped {
+		return fmt.Errorf("Can not start %v - host status is '%v', not '%v'",
+			host.Id, host.Status, evergreen.HostStopped)
+	}
+
+	dockerClient, _, err := generateClient(&host.Distro)
+	if err != nil {
+		return err
+	}
+
+	if err = dockerClient.StartContainer(host.Id, nil); err != nil {
+		return evergreen.Logger.Errorf(slogger.ERROR, "Failed to start container '%v': %v", host.Id, err)
+	}
+
+	return host.SetRunning()
+}
+
 //Configure populates a DockerManager by reading relevant settings from the
 //config object.
 func (dockerMgr *DockerManager) Configure(settings *evergreen.Settings) error {
@@ -429,3 +484,13 @@ func (dockerMgr *DockerManager) GetSSHOptions(host *host.Host, keyPath string) (
 func (dockerMgr *DockerManager) TimeTilNextPayment(host *host.Host) time.Duration {
 	return time.Duration(0)
 }
+
+// CostForDuration returns the cost of running a container between the given
+// start and end times, at the hourly rate set in its distro.
+func (dockerMgr *DockerManager) CostForDuration(h *host.Host, start, end time.Time) (float64, error) {
+	settings := &Settings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+		return 0, fmt.Errorf("Error decoding params for distro %v: %v", h.Distro.Id, err)
+	}
+	return cloud.CostForHourlyRate(settings.HourlyRate, start, end)
+}
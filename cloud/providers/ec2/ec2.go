@@ -33,6 +33,12 @@ const (
 	EC2StatusStopped      = "stopped"
 )
 
+const (
+	// how often, and how many times, to check whether a started instance is up
+	StartInstancePollInterval = 10 * time.Second
+	StartInstancePollAttempts = 30
+)
+
 type EC2ProviderSettings struct {
 	AMI          string       `mapstructure:"ami" json:"ami,omitempty" bson:"ami,omitempty"`
 	InstanceType string       `mapstructure:"instance_type" json:"instance_type,omitempty" bson:"instance_type,omitempty"`
@@ -227,7 +233,17 @@ func (cloudManager *EC2Manager) GetDNSName(host *host.Host) (string, error) {
 	return instanceInfo.DNSName, nil
 }
 
+// StopInstance stops an EBS-backed instance, preserving its volumes so that
+// it can be started again later with StartInstance.
 func (cloudManager *EC2Manager) StopInstance(host *host.Host) error {
+	if host.Status != evergreen.HostRunning {
+		return fmt.Errorf("Can not stop %v - host status is '%v', not '%v'",
+			host.Id, host.Status, evergreen.HostRunning)
+	}
+	if host.RunningTask != "" {
+		return fmt.Errorf("Can not stop %v - it is running task %v", host.Id, host.RunningTask)
+	}
+
 	ec2Handle := getUSEast(*cloudManager.awsCredentials)
 	// stop the instance
 	resp, err := ec2Handle.StopInstances(host.Id)
@@ -240,13 +256,49 @@ func (cloudManager *EC2Manager) StopInstance(host *host.Host) error {
 		evergreen.Logger.Logf(slogger.INFO, "Stopped %v", stateChange.InstanceId)
 	}
 
-	err = host.ClearRunningTask()
+	return host.SetStopped()
+}
+
+// StartInstance starts a stopped instance and waits for it to come up. Since
+// EC2 assigns a new public DNS name on every start, the host's DNS name is
+// updated before it is marked as running.
+func (cloudManager *EC2Manager) StartInstance(host *host.Host) error {
+	if host.Status != evergreen.HostStopped {
+		return fmt.Errorf("Can not start %v - host status is '%v', not '%v'",
+			host.Id, host.Status, evergreen.HostStopped)
+	}
 
+	ec2Handle := getUSEast(*cloudManager.awsCredentials)
+	resp, err := ec2Handle.StartInstances(host.Id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	for _, stateChange := range resp.StateChanges {
+		evergreen.Logger.Logf(slogger.INFO, "Started %v", stateChange.InstanceId)
+	}
+
+	var dnsName string
+	_, err = util.Retry(func() error {
+		instanceInfo, err := getInstanceInfo(ec2Handle, host.Id)
+		if err != nil {
+			return util.RetriableError{Failure: err}
+		}
+		if instanceInfo.State.Name != EC2StatusRunning || instanceInfo.DNSName == "" {
+			return util.RetriableError{Failure: fmt.Errorf("host %v is not yet running (state '%v')",
+				host.Id, instanceInfo.State.Name)}
+		}
+		dnsName = instanceInfo.DNSName
+		return nil
+	}, StartInstancePollAttempts, StartInstancePollInterval)
+	if err != nil {
+		return fmt.Errorf("error waiting for host %v to start: %v", host.Id, err)
+	}
+
+	if err = host.UpdateDNSName(dnsName); err != nil {
+		return fmt.Errorf("error updating DNS name for host %v: %v", host.Id, err)
+	}
+	return host.SetRunning()
 }
 
 func (cloudManager *EC2Manager) TerminateInstance(host *host.Host) error {
@@ -73,3 +73,13 @@ func (staticMgr *MockCloudManager) GetSSHOptions(host *host.Host, keyPath string
 func (staticMgr *MockCloudManager) TimeTilNextPayment(host *host.Host) time.Duration {
 	return time.Duration(0)
 }
+
+// stop an instance
+func (staticMgr *MockCloudManager) StopInstance(host *host.Host) error {
+	return host.SetStopped()
+}
+
+// start a stopped instance
+func (staticMgr *MockCloudManager) StartInstance(host *host.Host) error {
+	return host.SetRunning()
+}
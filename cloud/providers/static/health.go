@@ -0,0 +1,68 @@
+package static
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mitchellh/mapstructure"
+	"github.com/tychoish/grip/slogger"
+)
+
+// DefaultQuarantineAfter is how many failures in a row quarantine a static
+// host when its distro doesn't say otherwise.
+const DefaultQuarantineAfter = 3
+
+// quarantineThreshold returns how many failures in a row quarantine a host.
+func (s *Settings) quarantineThreshold() int {
+	if s.QuarantineAfter > 0 {
+		return s.QuarantineAfter
+	}
+	return DefaultQuarantineAfter
+}
+
+// DistroSettings decodes the static settings of the host's distro.
+func DistroSettings(h *host.Host) (*Settings, error) {
+	settings := &Settings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+		return nil, fmt.Errorf("invalid static settings for '%v': %v", h.Distro.Id, err)
+	}
+	return settings, nil
+}
+
+// RecordFailure counts a system failure or failed reachability check against
+// a static host, quarantining the host once its distro's threshold of
+// failures in a row is reached. Returns true if the host was quarantined.
+// Hosts from other providers are left alone.
+func RecordFailure(h *host.Host, reason string) (bool, error) {
+	if h.Provider != evergreen.HostTypeStatic {
+		return false, nil
+	}
+	settings, err := DistroSettings(h)
+	if err != nil {
+		return false, err
+	}
+	failures, err := h.IncConsecutiveFailures()
+	if err != nil {
+		return false, fmt.Errorf("error counting failure on host %v: %v", h.Id, err)
+	}
+	if failures < settings.quarantineThreshold() {
+		return false, nil
+	}
+
+	evergreen.Logger.Logf(slogger.WARN, "Quarantining static host %v after %v failures in a row: %v",
+		h.Id, failures, reason)
+	reason = fmt.Sprintf("%v failures in a row, the last one: %v", failures, reason)
+	if err = h.Quarantine(reason); err != nil {
+		return false, fmt.Errorf("error quarantining host %v: %v", h.Id, err)
+	}
+	return true, nil
+}
+
+// RecordSuccess clears a static host's run of failures.
+func RecordSuccess(h *host.Host) error {
+	if h.Provider != evergreen.HostTypeStatic {
+		return nil
+	}
+	return h.ResetConsecutiveFailures()
+}
@@ -0,0 +1,85 @@
+package static
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(db.SessionFactoryFromConfig(evergreen.TestConfig()))
+}
+
+func TestStaticHostQuarantine(t *testing.T) {
+	Convey("With a static host in a distro that quarantines after two failures", t, func() {
+		testutil.HandleTestingErr(db.Clear(host.Collection), t, "Error clearing '%v' collection",
+			host.Collection)
+
+		h := &host.Host{
+			Id:       "static-host",
+			Provider: evergreen.HostTypeStatic,
+			Status:   evergreen.HostRunning,
+			Distro: distro.Distro{
+				Id:               "static",
+				ProviderSettings: &map[string]interface{}{"quarantine_after": 2},
+			},
+		}
+		So(h.Insert(), ShouldBeNil)
+
+		Convey("the host should be quarantined on its second failure in a row", func() {
+			quarantined, err := RecordFailure(h, "unreachable")
+			So(err, ShouldBeNil)
+			So(quarantined, ShouldBeFalse)
+			So(h.Status, ShouldEqual, evergreen.HostRunning)
+
+			quarantined, err = RecordFailure(h, "unreachable")
+			So(err, ShouldBeNil)
+			So(quarantined, ShouldBeTrue)
+
+			dbHost, err := host.FindOne(host.ById(h.Id))
+			So(err, ShouldBeNil)
+			So(dbHost.Status, ShouldEqual, evergreen.HostQuarantined)
+		})
+
+		Convey("a success should restart the count", func() {
+			_, err := RecordFailure(h, "unreachable")
+			So(err, ShouldBeNil)
+			So(RecordSuccess(h), ShouldBeNil)
+
+			quarantined, err := RecordFailure(h, "unreachable")
+			So(err, ShouldBeNil)
+			So(quarantined, ShouldBeFalse)
+		})
+
+		Convey("the default threshold should apply when the distro doesn't set one", func() {
+			h.Distro.ProviderSettings = &map[string]interface{}{}
+			for i := 1; i < DefaultQuarantineAfter; i++ {
+				quarantined, err := RecordFailure(h, "unreachable")
+				So(err, ShouldBeNil)
+				So(quarantined, ShouldBeFalse)
+			}
+			quarantined, err := RecordFailure(h, "unreachable")
+			So(err, ShouldBeNil)
+			So(quarantined, ShouldBeTrue)
+		})
+
+		Convey("hosts from other providers should never be quarantined", func() {
+			h.Provider = "ec2"
+			for i := 0; i < 5; i++ {
+				quarantined, err := RecordFailure(h, "unreachable")
+				So(err, ShouldBeNil)
+				So(quarantined, ShouldBeFalse)
+			}
+		})
+
+		Convey("a negative threshold should fail validation", func() {
+			settings := &Settings{QuarantineAfter: -1}
+			So(settings.Validate(), ShouldNotBeNil)
+		})
+	})
+}
@@ -19,30 +19,68 @@ type StaticManager struct{}
 
 type Settings struct {
 	Hosts []Host `mapstructure:"hosts" json:"hosts" bson:"hosts"`
+
+	// HourlyRate is the amortized cost per hour of each of the hosts, unless
+	// a host has its own rate
+	HourlyRate float64 `mapstructure:"hourly_rate" json:"hourly_rate,omitempty" bson:"hourly_rate,omitempty"`
+
+	// QuarantineAfter is how many system failures or failed reachability
+	// checks in a row take a host out of service. Defaults to
+	// DefaultQuarantineAfter.
+	QuarantineAfter int `mapstructure:"quarantine_after" json:"quarantine_after,omitempty" bson:"quarantine_after,omitempty"`
+
+	// HealthCheck is a script that must succeed on a quarantined host before
+	// it is returned to service
+	HealthCheck string `mapstructure:"health_check" json:"health_check,omitempty" bson:"health_check,omitempty"`
 }
 
 type Host struct {
-	Name string `mapstructure:"name" json:"name" bson:"name"`
+	Name       string  `mapstructure:"name" json:"name" bson:"name"`
+	HourlyRate float64 `mapstructure:"hourly_rate" json:"hourly_rate,omitempty" bson:"hourly_rate,omitempty"`
 }
 
 var (
 	// bson fields for the Settings struct
-	HostsKey = bsonutil.MustHaveTag(Settings{}, "Hosts")
+	HostsKey           = bsonutil.MustHaveTag(Settings{}, "Hosts")
+	HourlyRateKey      = bsonutil.MustHaveTag(Settings{}, "HourlyRate")
+	QuarantineAfterKey = bsonutil.MustHaveTag(Settings{}, "QuarantineAfter")
+	HealthCheckKey     = bsonutil.MustHaveTag(Settings{}, "HealthCheck")
 
 	// bson fields for the Host struct
-	NameKey = bsonutil.MustHaveTag(Host{}, "Name")
+	NameKey           = bsonutil.MustHaveTag(Host{}, "Name")
+	HostHourlyRateKey = bsonutil.MustHaveTag(Host{}, "HourlyRate")
 )
 
 // Validate checks that the settings from the configuration are valid.
 func (s *Settings) Validate() error {
+	if s.HourlyRate < 0 {
+		return fmt.Errorf("'hourly_rate' field can not be negative")
+	}
+	if s.QuarantineAfter < 0 {
+		return fmt.Errorf("'quarantine_after' field can not be negative")
+	}
 	for _, h := range s.Hosts {
 		if h.Name == "" {
 			return fmt.Errorf("host 'name' field can not be blank")
 		}
+		if h.HourlyRate < 0 {
+			return fmt.Errorf("host '%v' 'hourly_rate' field can not be negative", h.Name)
+		}
 	}
 	return nil
 }
 
+// hourlyRate returns the amortized hourly rate of the static host with the
+// given name.
+func (s *Settings) hourlyRate(name string) float64 {
+	for _, h := range s.Hosts {
+		if h.Name == name && h.HourlyRate > 0 {
+			return h.HourlyRate
+		}
+	}
+	return s.HourlyRate
+}
+
 func (staticMgr *StaticManager) SpawnInstance(distro *distro.Distro, hostOpts cloud.HostOptions) (*host.Host, error) {
 	return nil, fmt.Errorf("cannot start new instances with static provider")
 }
@@ -117,3 +155,13 @@ func (staticMgr *StaticManager) GetSSHOptions(h *host.Host, keyPath string) (opt
 func (staticMgr *StaticManager) TimeTilNextPayment(host *host.Host) time.Duration {
 	return time.Duration(0)
 }
+
+// CostForDuration returns the cost of using a static host between the given
+// start and end times, at the amortized hourly rate set in its distro.
+func (staticMgr *StaticManager) CostForDuration(h *host.Host, start, end time.Time) (float64, error) {
+	settings, err := DistroSettings(h)
+	if err != nil {
+		return 0, err
+	}
+	return cloud.CostForHourlyRate(settings.hourlyRate(h.Id), start, end)
+}
@@ -0,0 +1,54 @@
+package static
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStaticCostForDuration(t *testing.T) {
+	Convey("With a static distro with an hourly rate and a host with its own rate", t, func() {
+		d := distro.Distro{
+			Id: "static",
+			ProviderSettings: &map[string]interface{}{
+				"hourly_rate": 2.0,
+				"hosts": []interface{}{
+					map[string]interface{}{"name": "cheap"},
+					map[string]interface{}{"name": "pricey", "hourly_rate": 5.0},
+				},
+			},
+		}
+		mgr := &StaticManager{}
+		start := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+		end := start.Add(90 * time.Minute)
+
+		Convey("a host without its own rate should cost the distro's rate", func() {
+			cost, err := mgr.CostForDuration(&host.Host{Id: "cheap", Distro: d}, start, end)
+			So(err, ShouldBeNil)
+			So(cost, ShouldEqual, 3)
+		})
+
+		Convey("a host with its own rate should cost its rate", func() {
+			cost, err := mgr.CostForDuration(&host.Host{Id: "pricey", Distro: d}, start, end)
+			So(err, ShouldBeNil)
+			So(cost, ShouldEqual, 7.5)
+		})
+
+		Convey("malformed times should be an error", func() {
+			_, err := mgr.CostForDuration(&host.Host{Id: "cheap", Distro: d}, end, start)
+			So(err, ShouldNotBeNil)
+			_, err = mgr.CostForDuration(&host.Host{Id: "cheap", Distro: d}, time.Time{}, end)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("a negative rate should fail validation", func() {
+			settings := &Settings{Hosts: []Host{{Name: "h", HourlyRate: -1}}}
+			So(settings.Validate(), ShouldNotBeNil)
+			settings = &Settings{HourlyRate: -1}
+			So(settings.Validate(), ShouldNotBeNil)
+		})
+	})
+}
@@ -1,18 +1,14 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"regexp"
-	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
-var (
-	expansionRegex = regexp.MustCompile("\\$\\{.*?\\}")
-)
-
 // Wrapper for an expansions map, with some utility functions.
 type Expansions map[string]string
 
@@ -47,6 +43,38 @@ func (self *Expansions) UpdateFromYaml(filename string) error {
 	return nil
 }
 
+// Read an object of keys/values from the given JSON file, and update the
+// expansions to include them (overwriting any duplicates with the new value).
+// Numbers and booleans are stored in their JSON form; other values that
+// aren't strings are an error.
+func (self *Expansions) UpdateFromJSON(filename string) error {
+	filedata, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(filedata))
+	decoder.UseNumber()
+	values := make(map[string]interface{})
+	if err = decoder.Decode(&values); err != nil {
+		return err
+	}
+	newExpansions := make(map[string]string)
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			newExpansions[key] = v
+		case json.Number:
+			newExpansions[key] = v.String()
+		case bool:
+			newExpansions[key] = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("value of '%v' in %v is not a string, number or boolean", key, filename)
+		}
+	}
+	self.Update(newExpansions)
+	return nil
+}
+
 // Set a single value in the expansions.
 func (self *Expansions) Put(expansion string, value string) {
 	(*self)[expansion] = value
@@ -67,42 +95,17 @@ func (self *Expansions) Exists(expansion string) bool {
 	return ok
 }
 
-// Apply the expansions to a single string.
-// Return the expanded string, or an error if the input string is malformed.
+// Apply the expansions to a single string. Besides ${name} and
+// ${name|default}, where the default may itself contain expansions, the
+// string may call the functions in expansionFuncs, as in ${upper(name)} or
+// ${replace(${branch}, "/", "-")}.
+// Return the expanded string, or an error describing where the input string
+// is malformed.
 func (self *Expansions) ExpandString(toExpand string) (string, error) {
-	// replace all expandable parts of the string
-	malformedFound := false
-	expanded := string(expansionRegex.ReplaceAllFunc([]byte(toExpand),
-		func(matchByte []byte) []byte {
-
-			match := string(matchByte)
-			// trim off ${ and }
-			match = match[2 : len(match)-1]
-
-			// if there is a ${ within the match, then it is malformed (we
-			// caught an unmatched ${)
-			if strings.Contains(match, "${") {
-				malformedFound = true
-			}
-
-			// parse into the name and default value
-			var defaultVal string
-			if idx := strings.Index(match, "|"); idx != -1 {
-				defaultVal = match[idx+1:]
-				match = match[0:idx]
-			}
-
-			// return the specified expansion, if it is present.
-			if self.Exists(match) {
-				return []byte(self.Get(match))
-			}
-
-			return []byte(defaultVal)
-		}))
-
-	if malformedFound || strings.Contains(expanded, "${") {
-		return expanded, fmt.Errorf("\"%v\" contains an unclosed expansion", expanded)
+	p := &expansionParser{expansions: self, input: toExpand}
+	expanded, err := p.text(false)
+	if err != nil {
+		return toExpand, fmt.Errorf("error expanding \"%v\": %v", toExpand, err)
 	}
-
 	return expanded, nil
 }
@@ -0,0 +1,228 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// expansionFunc is a function that can be called in an expansion. Functions
+// are pure: they only compute a value from their arguments.
+type expansionFunc func(args []string) (string, error)
+
+// expansionFuncs are the functions that can be called in an expansion, by
+// name.
+var expansionFuncs = map[string]expansionFunc{
+	"upper":    unaryFunc("upper", strings.ToUpper),
+	"lower":    unaryFunc("lower", strings.ToLower),
+	"trim":     unaryFunc("trim", strings.TrimSpace),
+	"basename": unaryFunc("basename", filepath.Base),
+	"replace": func(args []string) (string, error) {
+		if len(args) != 3 {
+			return "", fmt.Errorf("replace takes 3 arguments (value, old, new), not %v", len(args))
+		}
+		return strings.Replace(args[0], args[1], args[2], -1), nil
+	},
+	"join": func(args []string) (string, error) {
+		if len(args) == 0 {
+			return "", fmt.Errorf("join takes a separator and the values to join")
+		}
+		return strings.Join(args[1:], args[0]), nil
+	},
+}
+
+// unaryFunc makes an expansion function that takes a single argument.
+func unaryFunc(name string, f func(string) string) expansionFunc {
+	return func(args []string) (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("%v takes 1 argument, not %v", name, len(args))
+		}
+		return f(args[0]), nil
+	}
+}
+
+// expansionParser expands a string as it reads it. An expansion is one of
+//   ${name}
+//   ${name|default}, where the default is text that may contain expansions
+//   ${func(arg, ...)}, where each argument is a name, a "quoted string", an
+//     expansion, or another function call
+type expansionParser struct {
+	expansions *Expansions
+	input      string
+	pos        int
+}
+
+// text reads literal text and expansions until the end of the input, or
+// until the brace that ends a default value.
+func (p *expansionParser) text(inDefault bool) (string, error) {
+	out := bytes.Buffer{}
+	for p.pos < len(p.input) {
+		if p.startsExpansion() {
+			value, err := p.expansion()
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(value)
+			continue
+		}
+		if inDefault && p.input[p.pos] == '}' {
+			return out.String(), nil
+		}
+		out.WriteByte(p.input[p.pos])
+		p.pos++
+	}
+	return out.String(), nil
+}
+
+func (p *expansionParser) startsExpansion() bool {
+	return strings.HasPrefix(p.input[p.pos:], "${")
+}
+
+// expansion reads and evaluates an expansion, starting at its "${".
+func (p *expansionParser) expansion() (string, error) {
+	start := p.pos
+	p.pos += 2
+	nameStart := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune("|}(", rune(p.input[p.pos])) {
+		if p.startsExpansion() {
+			return "", fmt.Errorf("expansion at position %v has an expansion in its name", start)
+		}
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		return "", fmt.Errorf("expansion at position %v is not closed", start)
+	}
+	name := strings.TrimSpace(p.input[nameStart:p.pos])
+
+	switch p.input[p.pos] {
+	case '(':
+		value, err := p.call(name, nameStart)
+		if err != nil {
+			return "", err
+		}
+		p.skipSpace()
+		if p.pos == len(p.input) || p.input[p.pos] != '}' {
+			return "", fmt.Errorf("expansion at position %v is not closed after calling %v", start, name)
+		}
+		p.pos++
+		return value, nil
+	case '|':
+		p.pos++
+		defaultValue, err := p.text(true)
+		if err != nil {
+			return "", err
+		}
+		if p.pos == len(p.input) {
+			return "", fmt.Errorf("expansion at position %v is not closed", start)
+		}
+		p.pos++
+		if p.expansions.Exists(name) {
+			return p.expansions.Get(name), nil
+		}
+		return defaultValue, nil
+	default:
+		p.pos++
+		return p.expansions.Get(name), nil
+	}
+}
+
+// call reads the arguments of a function call, starting at its "(", and
+// calls the function.
+func (p *expansionParser) call(name string, start int) (string, error) {
+	f, ok := expansionFuncs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown function '%v' at position %v", name, start)
+	}
+	p.pos++
+	args := []string{}
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == ')' {
+		p.pos++
+	} else if err := p.args(name, start, &args); err != nil {
+		return "", err
+	}
+	value, err := f(args)
+	if err != nil {
+		return "", fmt.Errorf("error calling %v at position %v: %v", name, start, err)
+	}
+	return value, nil
+}
+
+// args reads a function's comma-separated arguments up to and including the
+// closing parenthesis.
+func (p *expansionParser) args(name string, start int, args *[]string) error {
+	for {
+		arg, err := p.arg()
+		if err != nil {
+			return err
+		}
+		*args = append(*args, arg)
+		p.skipSpace()
+		if p.pos == len(p.input) {
+			return fmt.Errorf("call to %v at position %v is not closed", name, start)
+		}
+		if p.input[p.pos] == ')' {
+			p.pos++
+			return nil
+		}
+		if p.input[p.pos] != ',' {
+			return fmt.Errorf("unexpected '%c' at position %v in call to %v", p.input[p.pos], p.pos, name)
+		}
+		p.pos++
+	}
+}
+
+// arg reads and evaluates a single function argument.
+func (p *expansionParser) arg() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return "", fmt.Errorf("missing argument at end of input")
+	}
+	if p.startsExpansion() {
+		return p.expansion()
+	}
+	if p.input[p.pos] == '"' {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" \t,()\"}", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return "", fmt.Errorf("missing argument at position %v", start)
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		return p.call(name, start)
+	}
+	return p.expansions.Get(name), nil
+}
+
+// quoted reads a double-quoted string, in which \" and \\ are escapes.
+func (p *expansionParser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+	out := bytes.Buffer{}
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return out.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			out.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		default:
+			out.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("string at position %v is not closed", start)
+}
+
+func (p *expansionParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
@@ -83,6 +83,31 @@ func TestExpansions(t *testing.T) {
 			So(expansions.Get("key1"), ShouldEqual, "blah")
 		})
 
+		Convey("updating from a json file should get keys copied over", func() {
+			expansions := NewExpansions(map[string]string{
+				"key1": "val1",
+				"key2": "val2",
+			})
+
+			err := expansions.UpdateFromJSON(filepath.Join(
+				testutil.GetDirectoryOfFile(), "testdata", "expansions.json"))
+			So(err, ShouldBeNil)
+			So(expansions.Get("marge"), ShouldEqual, "1")
+			So(expansions.Get("lisa"), ShouldEqual, "2")
+			So(expansions.Get("bart"), ShouldEqual, "true")
+			So(expansions.Get("key1"), ShouldEqual, "blah")
+			So(expansions.Get("key2"), ShouldEqual, "val2")
+		})
+
+		Convey("updating from a json file with nested values should fail", func() {
+			expansions := NewExpansions(map[string]string{})
+
+			err := expansions.UpdateFromJSON(filepath.Join(
+				testutil.GetDirectoryOfFile(), "testdata", "nested_expansions.json"))
+			So(err, ShouldNotBeNil)
+			So(expansions.Exists("marge"), ShouldBeFalse)
+		})
+
 	})
 }
 
@@ -163,6 +188,46 @@ func TestExpandString(t *testing.T) {
 
 		})
 
+		Convey("defaults may contain expansions", func() {
+
+			exp, err := expansions.ExpandString("${key3|${key1}}-${key1|${key2}}-${key3|${key4|x}y}")
+			So(err, ShouldBeNil)
+			So(exp, ShouldEqual, "val1-val1-xy")
+		})
+
+		Convey("functions should be applied to their arguments", func() {
+
+			expansions.Put("path", "/data/db/ Branch/Name ")
+			exp, err := expansions.ExpandString("${upper(key1)} ${lower(\"ABC\")} " +
+				"${trim(${path})} ${basename(trim(path))}")
+			So(err, ShouldBeNil)
+			So(exp, ShouldEqual, "VAL1 abc /data/db/ Branch/Name Name")
+
+			exp, err = expansions.ExpandString("${replace(${key1}, \"val\", \"v\\\"\")}")
+			So(err, ShouldBeNil)
+			So(exp, ShouldEqual, "v\"1")
+
+			exp, err = expansions.ExpandString("${join(\",\", key1, key2, ${key3|none})}")
+			So(err, ShouldBeNil)
+			So(exp, ShouldEqual, "val1,val2,none")
+		})
+
+		Convey("misused functions should cause an error", func() {
+
+			_, err := expansions.ExpandString("${nope(key1)}")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "unknown function 'nope'")
+
+			_, err = expansions.ExpandString("${upper(key1, key2)}")
+			So(err, ShouldNotBeNil)
+
+			_, err = expansions.ExpandString("${upper(key1}")
+			So(err, ShouldNotBeNil)
+
+			_, err = expansions.ExpandString("${replace(key1, \"a)}")
+			So(err, ShouldNotBeNil)
+		})
+
 		Convey("badly formed command strings should cause an error", func() {
 
 			badStr1 := "hello ${key1|blah}${key3}hello${ ${key4} " +
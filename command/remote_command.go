@@ -14,6 +14,7 @@ type RemoteCommand struct {
 	Id        string
 	CmdString string
 
+	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
 
@@ -73,6 +74,7 @@ func (rc *RemoteCommand) Start() error {
 
 	// set up execution
 	cmd := exec.Command("ssh", cmdArray...)
+	cmd.Stdin = rc.Stdin
 	cmd.Stdout = rc.Stdout
 	cmd.Stderr = rc.Stderr
 
@@ -146,6 +146,10 @@ type SMTPConfig struct {
 type SchedulerConfig struct {
 	LogFile     string
 	MergeToggle int
+
+	// FallbackQueueLength is how many tasks a distro's queue may hold before
+	// tasks that can run on other distros are placed on those instead.
+	FallbackQueueLength int `yaml:"fallback_queue_length"`
 }
 
 // TaskRunnerConfig holds logging settings for the scheduler process.
@@ -176,6 +180,39 @@ type JiraConfig struct {
 	Host     string
 	Username string
 	Password string
+	// FailureProjects are the keys of the JIRA projects searched for tickets
+	// that match a failed task, along with any projects that the task's
+	// project files alerts in.
+	FailureProjects []string `yaml:"failure_projects"`
+}
+
+// ArtifactGCConfig holds settings for the process that removes artifact files
+// from their stores once their projects' retention periods have passed.
+type ArtifactGCConfig struct {
+	// AWSKey and AWSSecret are used to delete expired files from S3. Files
+	// in S3 are left alone if they aren't set.
+	AWSKey    string `yaml:"aws_key"`
+	AWSSecret string `yaml:"aws_secret"`
+	// Buckets are the S3 buckets that files may be deleted from. Files in
+	// any other bucket, or outside their project's directory of the bucket
+	// (paths starting with "<project>/"), are left alone.
+	Buckets []string `yaml:"buckets"`
+	// BatchSize is the most artifact entries expired for each project in a
+	// single run.
+	BatchSize int `yaml:"batch_size"`
+}
+
+// SignedURLConfig holds the credentials that links to private artifact files
+// in S3 are signed with, so that they can be downloaded for a short time
+// without the files being public.
+type SignedURLConfig struct {
+	AWSKey    string `yaml:"aws_key"`
+	AWSSecret string `yaml:"aws_secret"`
+	// Buckets are the S3 buckets whose private files get signed links.
+	// Private files in any other bucket keep their links.
+	Buckets []string `yaml:"buckets"`
+	// ExpirationMinutes is how long a signed link works for.
+	ExpirationMinutes int `yaml:"expiration_minutes"`
 }
 
 // PluginConfig holds plugin-specific settings, which are handled.
@@ -225,6 +262,8 @@ type Settings struct {
 	Runner              RunnerConfig      `yaml:"runner"`
 	Scheduler           SchedulerConfig   `yaml:"scheduler"`
 	TaskRunner          TaskRunnerConfig  `yaml:"taskrunner"`
+	ArtifactGC          ArtifactGCConfig  `yaml:"artifact_gc"`
+	SignedURLs          SignedURLConfig   `yaml:"signed_urls"`
 	Expansions          map[string]string `yaml:"expansions"`
 	Plugins             PluginConfig      `yaml:"plugins"`
 	IsProd              bool              `yaml:"isprod"`
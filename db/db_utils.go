@@ -28,6 +28,21 @@ func Insert(collection string, item interface{}) error {
 	return db.C(collection).Insert(item)
 }
 
+// InsertMany inserts the specified items into the specified collection in a
+// single batch.
+func InsertMany(collection string, items ...interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+	session, db, err := GetGlobalSessionFactory().GetSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	return db.C(collection).Insert(items...)
+}
+
 // Clear removes all documents from a specified collection.
 func Clear(collection string) error {
 	session, db, err := GetGlobalSessionFactory().GetSession()
@@ -25,6 +25,7 @@ const (
 	HostUnreachable     = "unreachable"
 	HostQuarantined     = "quarantined"
 	HostDecommissioned  = "decommissioned"
+	HostStopped         = "stopped"
 
 	HostStatusSuccess = "success"
 	HostStatusFailed  = "failed"
@@ -91,6 +92,7 @@ const (
 const (
 	AuthTokenCookie  = "mci-token"
 	TaskSecretHeader = "Task-Secret"
+	HostSecretHeader = "Host-Secret"
 )
 
 // HTTP constants. Added after Go1.4. Here for compatibility with GCCGO
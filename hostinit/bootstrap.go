@@ -0,0 +1,94 @@
+package hostinit
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/tychoish/grip/slogger"
+)
+
+// runBootstrapSteps runs the distro's bootstrap steps on the host in order,
+// stopping at the first one that fails. Each step's outcome is recorded as a
+// host event. Returns the combined output of the steps that were run.
+func (init *HostInit) runBootstrapSteps(h *host.Host, sshOptions []string) (string, error) {
+	output := &bytes.Buffer{}
+	for i, step := range h.Distro.BootstrapSteps {
+		logs, err := init.runBootstrapStep(h, i, step, sshOptions)
+		fmt.Fprintf(output, "=== bootstrap step '%v'\n%v\n", step.Name, logs)
+		if err != nil {
+			return output.String(), err
+		}
+	}
+	return output.String(), nil
+}
+
+// runBootstrapStep runs a single bootstrap step, retrying it as configured.
+// The step is skipped if its check already passes, and only succeeds if its
+// check passes after it runs.
+func (init *HostInit) runBootstrapStep(h *host.Host, index int, step distro.BootstrapStep,
+	sshOptions []string) (string, error) {
+	start := time.Now()
+
+	runScript, err := step.RunScript()
+	if err != nil {
+		event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepFailed, err.Error(), 0, 0)
+		return "", err
+	}
+
+	checkScriptName := ""
+	if checkScript := step.CheckScript(); checkScript != "" {
+		checkScriptName = fmt.Sprintf("bootstrap_%v_check.sh", index)
+		if err = init.copyScript(h, checkScriptName, checkScript); err != nil {
+			err = fmt.Errorf("error copying check for bootstrap step '%v': %v", step.Name, err)
+			event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepFailed, err.Error(), 0, 0)
+			return "", err
+		}
+		if _, err = hostutil.RunRemoteScriptWithTimeout(h, checkScriptName, sshOptions, step.Timeout()); err == nil {
+			evergreen.Logger.Logf(slogger.INFO, "Skipping bootstrap step '%v' on host %v: check passed",
+				step.Name, h.Id)
+			event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepSkipped, "", 0, time.Since(start))
+			return "check passed, skipped", nil
+		}
+	}
+
+	runScriptName := fmt.Sprintf("bootstrap_%v.sh", index)
+	if err = init.copyScript(h, runScriptName, runScript); err != nil {
+		err = fmt.Errorf("error copying bootstrap step '%v': %v", step.Name, err)
+		event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepFailed, err.Error(), 0, 0)
+		return "", err
+	}
+
+	attempts := step.Retries + 1
+	var logs string
+	for attempt := 1; attempt <= attempts; attempt++ {
+		evergreen.Logger.Logf(slogger.INFO, "Running bootstrap step '%v' on host %v (attempt %v of %v)",
+			step.Name, h.Id, attempt, attempts)
+		logs, err = hostutil.RunRemoteScriptWithTimeout(h, runScriptName, sshOptions, step.Timeout())
+		if err == nil && checkScriptName != "" {
+			var checkLogs string
+			if checkLogs, err = hostutil.RunRemoteScriptWithTimeout(h, checkScriptName, sshOptions, step.Timeout()); err != nil {
+				logs += checkLogs
+				err = fmt.Errorf("check did not pass after running: %v", err)
+			}
+		}
+		if err == nil {
+			event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepSucceeded, logs, attempt, time.Since(start))
+			return logs, nil
+		}
+
+		evergreen.Logger.Logf(slogger.WARN, "Bootstrap step '%v' failed on host %v (attempt %v of %v): %v",
+			step.Name, h.Id, attempt, attempts, err)
+		if attempt < attempts {
+			time.Sleep(step.RetryWait())
+		}
+	}
+
+	event.LogHostBootstrapStep(h.Id, step.Name, event.BootstrapStepFailed, logs, attempts, time.Since(start))
+	return logs, fmt.Errorf("bootstrap step '%v' failed after %v attempt(s): %v", step.Name, attempts, err)
+}
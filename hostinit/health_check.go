@@ -0,0 +1,112 @@
+package hostinit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud/providers"
+	"github.com/evergreen-ci/evergreen/cloud/providers/static"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/tychoish/grip/slogger"
+)
+
+const (
+	healthCheckScriptName = "health_check.sh"
+
+	// HealthCheckTimeout is the longest a static host's health check may run.
+	HealthCheckTimeout = 10 * time.Minute
+)
+
+// healthChecks holds the ids of the hosts whose health check is running, so
+// that a host is only checked once at a time.
+var healthChecks = struct {
+	sync.Mutex
+	running map[string]bool
+}{running: map[string]bool{}}
+
+// ReturnToService starts the health check of a quarantined static host's
+// distro on the host, which puts the host back in service if it passes.
+// The check runs in the background, and its outcome is recorded as a host
+// event. Returns an error if the host can't be checked.
+func (init *HostInit) ReturnToService(h *host.Host, user string) error {
+	if h.Status != evergreen.HostQuarantined {
+		return fmt.Errorf("host %v is not quarantined", h.Id)
+	}
+	if h.Provider != evergreen.HostTypeStatic {
+		return fmt.Errorf("host %v is not a static host", h.Id)
+	}
+	settings, err := static.DistroSettings(h)
+	if err != nil {
+		return err
+	}
+	if settings.HealthCheck == "" {
+		return fmt.Errorf("distro %v has no health check", h.Distro.Id)
+	}
+
+	healthChecks.Lock()
+	defer healthChecks.Unlock()
+	if healthChecks.running[h.Id] {
+		return fmt.Errorf("host %v is already being health checked", h.Id)
+	}
+	healthChecks.running[h.Id] = true
+	go func() {
+		defer func() {
+			healthChecks.Lock()
+			delete(healthChecks.running, h.Id)
+			healthChecks.Unlock()
+		}()
+		if err := init.returnToService(h, user, settings.HealthCheck); err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error returning host %v to service: %v", h.Id, err)
+		}
+	}()
+	return nil
+}
+
+// returnToService runs the health check on the host, recording its outcome
+// as a host event, and puts the host back in service if it passes.
+func (init *HostInit) returnToService(h *host.Host, user, script string) error {
+	start := time.Now()
+	logs, err := init.runHealthCheck(h, script)
+	if err != nil {
+		// the error is all there is to show for a check that never ran
+		logs = strings.TrimSpace(logs + "\n" + err.Error())
+	}
+	event.LogHostHealthCheck(h.Id, user, logs, err == nil, time.Since(start))
+	if err != nil {
+		return err
+	}
+
+	evergreen.Logger.Logf(slogger.INFO, "Host %v passed its health check, returning it to service", h.Id)
+	if err = h.ResetConsecutiveFailures(); err != nil {
+		return fmt.Errorf("error clearing failures for host %v: %v", h.Id, err)
+	}
+	if err = h.SetRunning(); err != nil {
+		return fmt.Errorf("error returning host %v to service: %v", h.Id, err)
+	}
+	return nil
+}
+
+// runHealthCheck copies the health check script to the host and runs it.
+func (init *HostInit) runHealthCheck(h *host.Host, script string) (string, error) {
+	cloudHost, err := providers.GetCloudHost(h, init.Settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cloud host for %v: %v", h.Id, err)
+	}
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return "", fmt.Errorf("error getting ssh options for host %v: %v", h.Id, err)
+	}
+	if err = init.copyScript(h, healthCheckScriptName, script); err != nil {
+		return "", fmt.Errorf("error copying health check to host %v: %v", h.Id, err)
+	}
+	logs, err := hostutil.RunRemoteScriptWithTimeout(h, healthCheckScriptName, sshOptions, HealthCheckTimeout)
+	if err != nil {
+		return logs, fmt.Errorf("health check failed on host %v: %v", h.Id, err)
+	}
+	return logs, nil
+}
@@ -27,7 +27,6 @@ import (
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/tychoish/grip/slogger"
-	"gopkg.in/mgo.v2"
 )
 
 const (
@@ -203,7 +202,7 @@ func (init *HostInit) setupHost(targetHost *host.Host) (string, error) {
 
 	// mark the host as initializing
 	if err := targetHost.SetInitializing(); err != nil {
-		if err == mgo.ErrNotFound {
+		if host.IsStatusTransitionError(err) {
 			return "", ErrHostAlreadyInitializing
 		} else {
 			return "", fmt.Errorf("database error: %v", err)
@@ -237,19 +236,27 @@ func (init *HostInit) setupHost(targetHost *host.Host) (string, error) {
 		}
 	}
 
+	logs := ""
+	if len(targetHost.Distro.BootstrapSteps) > 0 {
+		logs, err = init.runBootstrapSteps(targetHost, sshOptions)
+		if err != nil {
+			return logs, err
+		}
+	}
+
 	if targetHost.Distro.Setup != "" {
 		err = init.copyScript(targetHost, setupScriptName, targetHost.Distro.Setup)
 		if err != nil {
-			return "", fmt.Errorf("error copying script %v to host %v: %v",
+			return logs, fmt.Errorf("error copying script %v to host %v: %v",
 				setupScriptName, targetHost.Id, err)
 		}
-		logs, err := hostutil.RunRemoteScript(targetHost, setupScriptName, sshOptions)
+		setupLogs, err := hostutil.RunRemoteScript(targetHost, setupScriptName, sshOptions)
+		logs += setupLogs
 		if err != nil {
 			return logs, fmt.Errorf("error running setup script over ssh: %v", err)
 		}
-		return logs, nil
 	}
-	return "", nil
+	return logs, nil
 }
 
 // copyScript writes a given script as file "name" to the target host. This works
@@ -367,7 +374,9 @@ func (init *HostInit) ProvisionHost(h *host.Host) error {
 		} else if err == nil && len(h.ProvisionOptions.TaskId) > 0 {
 			evergreen.Logger.Logf(slogger.INFO, "Fetching data for task %v onto host %v", h.ProvisionOptions.TaskId, h.Id)
 			err = init.fetchRemoteTaskData(h.ProvisionOptions.TaskId, lcr.BinaryPath, lcr.ConfigPath, h)
-			evergreen.Logger.Logf(slogger.ERROR, "Failed to fetch data onto host %v: %v", h.Id, err)
+			if err != nil {
+				evergreen.Logger.Logf(slogger.ERROR, "Failed to fetch data onto host %v: %v", h.Id, err)
+			}
 		}
 	}
 
@@ -531,6 +540,9 @@ func (init *HostInit) LoadClient(target *host.Host) (*LoadClientResult, error) {
 	}, nil
 }
 
+// fetchRemoteTaskData runs the CLI on the target host to set up the task's
+// working directory under the distro's work dir, with its source, artifacts,
+// expansions and a script of its commands.
 func (init *HostInit) fetchRemoteTaskData(taskId, cliPath, confPath string, target *host.Host) error {
 	hostSSHInfo, err := util.ParseSSHInfo(target.Host)
 	if err != nil {
@@ -557,7 +569,7 @@ func (init *HostInit) fetchRemoteTaskData(taskId, cliPath, confPath string, targ
 
 	cmdOutput := &util.CappedWriter{&bytes.Buffer{}, 1024 * 1024}
 	makeShellCmd := &command.RemoteCommand{
-		CmdString:      fmt.Sprintf("%s -c '%s' fetch -t %s --source --artifacts --dir='%s'", cliPath, confPath, taskId, target.Distro.WorkDir),
+		CmdString:      fmt.Sprintf("%s -c '%s' fetch -t %s --source --artifacts --reproduce --dir='%s'", cliPath, confPath, taskId, target.Distro.WorkDir),
 		Stdout:         cmdOutput,
 		Stderr:         cmdOutput,
 		RemoteHostName: hostSSHInfo.Hostname,
@@ -14,6 +14,12 @@ const SSHTimeout = time.Minute * 10
 // RunRemoteScript executes a shell script that already exists on the remote host,
 // returning logs and any errors that occur. Logs may still be returned for some errors.
 func RunRemoteScript(h *host.Host, script string, sshOptions []string) (string, error) {
+	return RunRemoteScriptWithTimeout(h, script, sshOptions, SSHTimeout)
+}
+
+// RunRemoteScriptWithTimeout is like RunRemoteScript, but stops the script if it
+// runs for longer than the given timeout.
+func RunRemoteScriptWithTimeout(h *host.Host, script string, sshOptions []string, timeout time.Duration) (string, error) {
 	// parse the hostname into the user, host and port
 	hostInfo, err := util.ParseSSHInfo(h.Host)
 	if err != nil {
@@ -52,7 +58,10 @@ func RunRemoteScript(h *host.Host, script string, sshOptions []string) (string,
 	// run the ssh command with given timeout
 	err = util.RunFunctionWithTimeout(
 		cmd.Run,
-		SSHTimeout,
+		timeout,
 	)
+	if err == util.ErrTimedOut {
+		cmd.Stop()
+	}
 	return sshCmdStd.String(), err
 }
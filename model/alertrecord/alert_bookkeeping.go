@@ -21,6 +21,11 @@ var (
 	LastRevisionNotFound   = "last_revision_not_found"
 )
 
+// Project triggers
+var (
+	BudgetSoftLimitId = "budget_soft_limit"
+)
+
 // Host triggers
 var (
 	SpawnFailed                = "spawn_failed"
@@ -40,6 +45,7 @@ type AlertRecord struct {
 	TaskName            string        `bson:"task_name,omitempty"`
 	Variant             string        `bson:"variant,omitempty"`
 	RevisionOrderNumber int           `bson:"order,omitempty"`
+	BudgetPeriod        string        `bson:"budget_period,omitempty"`
 }
 
 var (
@@ -52,6 +58,7 @@ var (
 	ProjectIdKey           = bsonutil.MustHaveTag(AlertRecord{}, "ProjectId")
 	VersionIdKey           = bsonutil.MustHaveTag(AlertRecord{}, "VersionId")
 	RevisionOrderNumberKey = bsonutil.MustHaveTag(AlertRecord{}, "RevisionOrderNumber")
+	BudgetPeriodKey        = bsonutil.MustHaveTag(AlertRecord{}, "BudgetPeriod")
 )
 
 // FindOne gets one AlertRecord for the given query.
@@ -114,6 +121,16 @@ func ByLastRevNotFound(projectId, versionId string) db.Q {
 	}).Limit(1)
 }
 
+// ByBudgetPeriod finds the alert record stored when a project crossed the
+// soft limit of the budget for the given period.
+func ByBudgetPeriod(projectId, period string) db.Q {
+	return db.Query(bson.M{
+		TypeKey:         BudgetSoftLimitId,
+		ProjectIdKey:    projectId,
+		BudgetPeriodKey: period,
+	}).Limit(1)
+}
+
 func (ar *AlertRecord) Insert() error {
 	return db.Insert(Collection, ar)
 }
@@ -0,0 +1,72 @@
+package annotations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const Collection = "task_annotations"
+
+const (
+	// classifications for annotated failures
+	KnownFailure   = "known_failure"
+	Infrastructure = "infrastructure"
+	ProductBug     = "product_bug"
+	TestBug        = "test_bug"
+)
+
+var ValidClassifications = []string{KnownFailure, Infrastructure, ProductBug, TestBug, ""}
+
+// issueKeyRegex matches issue keys like "EVG-1234".
+var issueKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]*-[0-9]+$`)
+
+// Annotation is a note that a user attached to an execution of a task,
+// usually to record what is known about why it failed. Annotations are kept
+// apart from the task, and copied to the task's next execution when it is
+// restarted.
+type Annotation struct {
+	Id            bson.ObjectId `bson:"_id" json:"id"`
+	TaskId        string        `bson:"task_id" json:"task_id"`
+	TaskExecution int           `bson:"task_execution" json:"task_execution"`
+	// the task's identity, so that annotations can be found across tasks
+	Project      string `bson:"project" json:"project"`
+	BuildVariant string `bson:"build_variant" json:"build_variant"`
+	TaskName     string `bson:"task_name" json:"task_name"`
+
+	Note           string `bson:"note,omitempty" json:"note,omitempty"`
+	IssueKey       string `bson:"issue_key,omitempty" json:"issue_key,omitempty"`
+	Classification string `bson:"classification,omitempty" json:"classification,omitempty"`
+	// SuspectedCulprit is the revision of the commit thought to have caused
+	// the failure.
+	SuspectedCulprit string `bson:"suspected_culprit,omitempty" json:"suspected_culprit,omitempty"`
+
+	Author    string    `bson:"author" json:"author"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	// CarriedFrom is the execution the annotation was first made on, if it
+	// was carried forward from an earlier one.
+	CarriedFrom *int `bson:"carried_from,omitempty" json:"carried_from,omitempty"`
+}
+
+// Validate checks that the annotation says something, and that its issue key
+// and classification are well formed.
+func (a *Annotation) Validate() error {
+	a.Note = strings.TrimSpace(a.Note)
+	a.IssueKey = strings.TrimSpace(a.IssueKey)
+	a.SuspectedCulprit = strings.TrimSpace(a.SuspectedCulprit)
+
+	if a.Note == "" && a.IssueKey == "" && a.Classification == "" && a.SuspectedCulprit == "" {
+		return fmt.Errorf("annotation must have a note, issue, classification or suspected culprit")
+	}
+	if a.IssueKey != "" && !issueKeyRegex.MatchString(a.IssueKey) {
+		return fmt.Errorf("'%v' is not a valid issue key", a.IssueKey)
+	}
+	if !util.SliceContains(ValidClassifications, a.Classification) {
+		return fmt.Errorf("'%v' is not a valid classification", a.Classification)
+	}
+	return nil
+}
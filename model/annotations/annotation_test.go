@@ -0,0 +1,115 @@
+package annotations
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(db.SessionFactoryFromConfig(evergreen.TestConfig()))
+}
+
+func reset(t *testing.T) {
+	testutil.HandleTestingErr(
+		db.Clear(Collection),
+		t, "Error clearing collection")
+}
+
+func TestValidate(t *testing.T) {
+	Convey("With an annotation", t, func() {
+		Convey("an empty annotation should be invalid", func() {
+			a := &Annotation{TaskId: "t1", Note: "   "}
+			So(a.Validate(), ShouldNotBeNil)
+		})
+		Convey("an annotation with only a note should be valid", func() {
+			a := &Annotation{TaskId: "t1", Note: " flaky network "}
+			So(a.Validate(), ShouldBeNil)
+			So(a.Note, ShouldEqual, "flaky network")
+		})
+		Convey("issue keys should be checked", func() {
+			a := &Annotation{TaskId: "t1", IssueKey: "EVG-1234"}
+			So(a.Validate(), ShouldBeNil)
+			a.IssueKey = "evg-1234"
+			So(a.Validate(), ShouldNotBeNil)
+			a.IssueKey = "EVG"
+			So(a.Validate(), ShouldNotBeNil)
+		})
+		Convey("classifications should be checked", func() {
+			a := &Annotation{TaskId: "t1", Classification: Infrastructure}
+			So(a.Validate(), ShouldBeNil)
+			a.Classification = "bad_luck"
+			So(a.Validate(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCarryForward(t *testing.T) {
+	Convey("With annotations on several executions of a task", t, func() {
+		reset(t)
+		first := &Annotation{TaskId: "t1", TaskExecution: 0, Note: "first", Author: "me"}
+		second := &Annotation{TaskId: "t1", TaskExecution: 1, Note: "second", Author: "me"}
+		other := &Annotation{TaskId: "t2", TaskExecution: 1, Note: "other", Author: "me"}
+		So(first.Insert(), ShouldBeNil)
+		So(second.Insert(), ShouldBeNil)
+		So(other.Insert(), ShouldBeNil)
+
+		Convey("carrying forward should copy only that execution's annotations", func() {
+			So(CarryForward("t1", 1, 2), ShouldBeNil)
+			carried, err := Find(ByTaskExecution("t1", 2))
+			So(err, ShouldBeNil)
+			So(len(carried), ShouldEqual, 1)
+			So(carried[0].Note, ShouldEqual, "second")
+			So(carried[0].Id, ShouldNotEqual, second.Id)
+			So(*carried[0].CarriedFrom, ShouldEqual, 1)
+
+			Convey("and carrying forward again should keep the original execution", func() {
+				So(CarryForward("t1", 2, 3), ShouldBeNil)
+				carried, err := Find(ByTaskExecution("t1", 3))
+				So(err, ShouldBeNil)
+				So(len(carried), ShouldEqual, 1)
+				So(*carried[0].CarriedFrom, ShouldEqual, 1)
+			})
+		})
+
+		Convey("carrying forward an execution without annotations should do nothing", func() {
+			So(CarryForward("t2", 0, 1), ShouldBeNil)
+			found, err := Find(ByTaskExecution("t2", 1))
+			So(err, ShouldBeNil)
+			So(len(found), ShouldEqual, 1)
+		})
+
+		Convey("the latest annotations for each task should be found", func() {
+			found, err := Find(ByTaskIds([]string{"t1", "t2"}))
+			So(err, ShouldBeNil)
+			latest := LatestByTask(found)
+			So(len(latest), ShouldEqual, 2)
+			So(len(latest["t1"]), ShouldEqual, 1)
+			So(latest["t1"][0].Note, ShouldEqual, "second")
+			So(latest["t2"][0].Note, ShouldEqual, "other")
+		})
+	})
+}
+
+func TestByFilter(t *testing.T) {
+	Convey("With annotations across a project", t, func() {
+		reset(t)
+		So((&Annotation{TaskId: "t1", Project: "p", TaskName: "compile", IssueKey: "EVG-1", Author: "me"}).Insert(), ShouldBeNil)
+		So((&Annotation{TaskId: "t2", Project: "p", TaskName: "test", IssueKey: "EVG-2", Author: "me"}).Insert(), ShouldBeNil)
+		So((&Annotation{TaskId: "t3", Project: "q", TaskName: "compile", IssueKey: "EVG-1", Author: "me"}).Insert(), ShouldBeNil)
+
+		Convey("filtering should only return the project's matching annotations", func() {
+			found, err := Find(ByFilter(Filter{Project: "p", IssueKey: "EVG-1"}))
+			So(err, ShouldBeNil)
+			So(len(found), ShouldEqual, 1)
+			So(found[0].TaskId, ShouldEqual, "t1")
+
+			found, err = Find(ByFilter(Filter{Project: "p"}))
+			So(err, ShouldBeNil)
+			So(len(found), ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package annotations
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/db/bsonutil"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var (
+	// BSON fields for annotations
+	IdKey               = bsonutil.MustHaveTag(Annotation{}, "Id")
+	TaskIdKey           = bsonutil.MustHaveTag(Annotation{}, "TaskId")
+	TaskExecutionKey    = bsonutil.MustHaveTag(Annotation{}, "TaskExecution")
+	ProjectKey          = bsonutil.MustHaveTag(Annotation{}, "Project")
+	BuildVariantKey     = bsonutil.MustHaveTag(Annotation{}, "BuildVariant")
+	TaskNameKey         = bsonutil.MustHaveTag(Annotation{}, "TaskName")
+	IssueKeyKey         = bsonutil.MustHaveTag(Annotation{}, "IssueKey")
+	ClassificationKey   = bsonutil.MustHaveTag(Annotation{}, "Classification")
+	SuspectedCulpritKey = bsonutil.MustHaveTag(Annotation{}, "SuspectedCulprit")
+	CreatedAtKey        = bsonutil.MustHaveTag(Annotation{}, "CreatedAt")
+)
+
+// === Queries ===
+
+// ById returns a query for the annotation with the given id.
+func ById(id bson.ObjectId) db.Q {
+	return db.Query(bson.M{IdKey: id})
+}
+
+// ByTaskExecution returns a query for the annotations on an execution of a
+// task, oldest first.
+func ByTaskExecution(taskId string, execution int) db.Q {
+	return db.Query(bson.M{
+		TaskIdKey:        taskId,
+		TaskExecutionKey: execution,
+	}).Sort([]string{CreatedAtKey})
+}
+
+// ByTaskIds returns a query for the annotations on any execution of the
+// given tasks.
+func ByTaskIds(taskIds []string) db.Q {
+	return db.Query(bson.M{TaskIdKey: bson.M{"$in": taskIds}}).Sort([]string{CreatedAtKey})
+}
+
+// Filter narrows down a search for a project's annotations. Empty fields
+// match anything.
+type Filter struct {
+	Project          string
+	BuildVariant     string
+	TaskName         string
+	IssueKey         string
+	Classification   string
+	SuspectedCulprit string
+}
+
+// ByFilter returns a query for the annotations that match the filter, newest
+// first.
+func ByFilter(f Filter) db.Q {
+	query := bson.M{ProjectKey: f.Project}
+	fields := map[string]string{
+		BuildVariantKey:     f.BuildVariant,
+		TaskNameKey:         f.TaskName,
+		IssueKeyKey:         f.IssueKey,
+		ClassificationKey:   f.Classification,
+		SuspectedCulpritKey: f.SuspectedCulprit,
+	}
+	for key, value := range fields {
+		if value != "" {
+			query[key] = value
+		}
+	}
+	return db.Query(query).Sort([]string{"-" + CreatedAtKey})
+}
+
+// === DB Logic ===
+
+// Insert writes the annotation to the db, setting its id and creation time.
+// Callers are expected to have validated it.
+func (a *Annotation) Insert() error {
+	a.Id = bson.NewObjectId()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return db.Insert(Collection, a)
+}
+
+// Remove deletes the annotation with the given id.
+func Remove(id bson.ObjectId) error {
+	return db.Remove(Collection, bson.M{IdKey: id})
+}
+
+// FindOne gets one Annotation for the given query.
+func FindOne(query db.Q) (*Annotation, error) {
+	annotation := &Annotation{}
+	err := db.FindOneQ(Collection, query, annotation)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return annotation, err
+}
+
+// Find gets every Annotation for the given query.
+func Find(query db.Q) ([]Annotation, error) {
+	annotations := []Annotation{}
+	err := db.FindAllQ(Collection, query, &annotations)
+	return annotations, err
+}
+
+// CarryForward copies the annotations on one execution of a task to another,
+// so that what is known about a failure isn't lost when the task is
+// restarted.
+func CarryForward(taskId string, from, to int) error {
+	annotations, err := Find(ByTaskExecution(taskId, from))
+	if err != nil {
+		return err
+	}
+	docs := make([]interface{}, 0, len(annotations))
+	for _, a := range annotations {
+		if a.CarriedFrom == nil {
+			execution := a.TaskExecution
+			a.CarriedFrom = &execution
+		}
+		a.Id = bson.NewObjectId()
+		a.TaskExecution = to
+		docs = append(docs, a)
+	}
+	return db.InsertMany(Collection, docs...)
+}
+
+// LatestByTask groups annotations by task, keeping only the ones on the
+// latest execution of each task that has any.
+func LatestByTask(annotations []Annotation) map[string][]Annotation {
+	latest := map[string][]Annotation{}
+	for _, a := range annotations {
+		existing := latest[a.TaskId]
+		if len(existing) > 0 && existing[0].TaskExecution > a.TaskExecution {
+			continue
+		}
+		if len(existing) > 0 && existing[0].TaskExecution < a.TaskExecution {
+			existing = nil
+		}
+		latest[a.TaskId] = append(existing, a)
+	}
+	return latest
+}
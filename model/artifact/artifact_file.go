@@ -1,5 +1,7 @@
 package artifact
 
+import "time"
+
 const Collection = "artifact_files"
 
 const (
@@ -20,8 +22,31 @@ type Entry struct {
 	TaskDisplayName string `json:"task_name" bson:"task_name"`
 	BuildId         string `json:"build" bson:"build"`
 	Files           []File `json:"files" bson:"files"`
+
+	// Project, Version, Requester and CreateTime are copied from the task
+	// when its first files are attached, so that the files' retention
+	// can be decided without looking the task up.
+	Project    string    `json:"project,omitempty" bson:"project,omitempty"`
+	Version    string    `json:"version,omitempty" bson:"version,omitempty"`
+	Requester  string    `json:"requester,omitempty" bson:"requester,omitempty"`
+	CreateTime time.Time `json:"create_time" bson:"create_time,omitempty"`
+
+	// Expired is set once the entry's retention period has passed and its
+	// files have been removed from their stores.
+	Expired bool `json:"expired,omitempty" bson:"expired,omitempty"`
+	// Retained is set for entries whose files are kept forever, because
+	// their version was pushed.
+	Retained bool `json:"retained,omitempty" bson:"retained,omitempty"`
+	// ExpireFailures counts the runs that failed to remove the entry's
+	// files. Entries that fail MaxExpireFailures times are no longer tried.
+	ExpireFailures int `json:"expire_failures,omitempty" bson:"expire_failures,omitempty"`
 }
 
+// MaxExpireFailures is how many times removing an entry's files may fail
+// before the entry is left alone, so that files that can never be deleted
+// don't hold up the entries behind them.
+const MaxExpireFailures = 5
+
 // Params stores file entries as key-value pairs, for easy parameter parsing.
 //  Key = Human-readable name for file
 //  Value = link for the file
@@ -35,6 +60,21 @@ type File struct {
 	Link string `json:"link" bson:"link"`
 	// Visibility determines who can see the file in the UI
 	Visibility string `json:"visibility" bson:"visibility"`
+	// Store names the artifact store holding the file, e.g. "s3" or "local",
+	// if it was uploaded by an Evergreen command
+	Store string `json:"store,omitempty" bson:"store,omitempty"`
+	// Bucket is the S3 bucket holding the file, for files kept in S3
+	Bucket string `json:"bucket,omitempty" bson:"bucket,omitempty"`
+	// Path is where the file is kept within its store
+	Path string `json:"path,omitempty" bson:"path,omitempty"`
+	// Size, SHA256 and ContentType describe the file's contents, for files
+	// uploaded by an Evergreen command
+	Size        int64  `json:"size,omitempty" bson:"size,omitempty"`
+	SHA256      string `json:"sha256,omitempty" bson:"sha256,omitempty"`
+	ContentType string `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	// Expired is set once the file has been removed from its store, after
+	// its project's retention period
+	Expired bool `json:"expired,omitempty" bson:"expired,omitempty"`
 }
 
 // Array turns the parameter map into an array of File structs.
@@ -42,7 +82,7 @@ type File struct {
 func (params Params) Array() []File {
 	var files []File
 	for name, link := range params {
-		files = append(files, File{name, link, ""})
+		files = append(files, File{Name: name, Link: link})
 	}
 	return files
 }
@@ -1,6 +1,8 @@
 package artifact
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/db/bsonutil"
 	"gopkg.in/mgo.v2"
@@ -9,12 +11,23 @@ import (
 
 var (
 	// BSON fields for artifact file structs
-	TaskIdKey   = bsonutil.MustHaveTag(Entry{}, "TaskId")
-	TaskNameKey = bsonutil.MustHaveTag(Entry{}, "TaskDisplayName")
-	BuildIdKey  = bsonutil.MustHaveTag(Entry{}, "BuildId")
-	FilesKey    = bsonutil.MustHaveTag(Entry{}, "Files")
-	NameKey     = bsonutil.MustHaveTag(File{}, "Name")
-	LinkKey     = bsonutil.MustHaveTag(File{}, "Link")
+	TaskIdKey      = bsonutil.MustHaveTag(Entry{}, "TaskId")
+	TaskNameKey    = bsonutil.MustHaveTag(Entry{}, "TaskDisplayName")
+	BuildIdKey     = bsonutil.MustHaveTag(Entry{}, "BuildId")
+	FilesKey       = bsonutil.MustHaveTag(Entry{}, "Files")
+	ProjectKey     = bsonutil.MustHaveTag(Entry{}, "Project")
+	VersionKey     = bsonutil.MustHaveTag(Entry{}, "Version")
+	RequesterKey   = bsonutil.MustHaveTag(Entry{}, "Requester")
+	CreateTimeKey  = bsonutil.MustHaveTag(Entry{}, "CreateTime")
+	ExpiredKey     = bsonutil.MustHaveTag(Entry{}, "Expired")
+	RetainedKey    = bsonutil.MustHaveTag(Entry{}, "Retained")
+	FailuresKey    = bsonutil.MustHaveTag(Entry{}, "ExpireFailures")
+	NameKey        = bsonutil.MustHaveTag(File{}, "Name")
+	LinkKey        = bsonutil.MustHaveTag(File{}, "Link")
+	StoreKey       = bsonutil.MustHaveTag(File{}, "Store")
+	BucketKey      = bsonutil.MustHaveTag(File{}, "Bucket")
+	PathKey        = bsonutil.MustHaveTag(File{}, "Path")
+	FileExpiredKey = bsonutil.MustHaveTag(File{}, "Expired")
 )
 
 // === Queries ===
@@ -29,23 +42,80 @@ func ByBuildId(id string) db.Q {
 	return db.Query(bson.D{{BuildIdKey, id}}).Sort([]string{TaskNameKey})
 }
 
+// ByRetentionExpired returns a query for the project's entries with the given
+// requester that were created before the cutoff, and haven't yet been
+// expired or retained or failed to expire too many times, oldest first.
+func ByRetentionExpired(project, requester string, cutoff time.Time) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:    project,
+		RequesterKey:  requester,
+		CreateTimeKey: bson.M{"$lt": cutoff},
+		ExpiredKey:    bson.M{"$ne": true},
+		RetainedKey:   bson.M{"$ne": true},
+		FailuresKey:   bson.M{"$not": bson.M{"$gte": MaxExpireFailures}},
+	}).Sort([]string{CreateTimeKey})
+}
+
+// ByLiveReferences returns a query for the entries other than e that haven't
+// been expired and have a file with the given link, or kept at the given
+// path of the store and bucket if the path isn't empty.
+func ByLiveReferences(e Entry, link, storeName, bucket, path string) db.Q {
+	return db.Query(bson.M{
+		"$or":      fileReferences(link, storeName, bucket, path),
+		"$nor":     []bson.M{e.selector()},
+		ExpiredKey: bson.M{"$ne": true},
+	})
+}
+
+// fileReferences returns the queries for entries with a file with the given
+// link, or kept at the given path of the store and bucket if the path isn't
+// empty.
+func fileReferences(link, storeName, bucket, path string) []bson.M {
+	refs := []bson.M{{FilesKey: bson.M{"$elemMatch": bson.M{LinkKey: link}}}}
+	if path != "" {
+		refs = append(refs, bson.M{FilesKey: bson.M{"$elemMatch": bson.M{
+			StoreKey:  storeName,
+			BucketKey: bucket,
+			PathKey:   path,
+		}}})
+	}
+	return refs
+}
+
+// ByMissingCreateTime returns a query for entries attached before their
+// task's details were recorded with them.
+func ByMissingCreateTime() db.Q {
+	return db.Query(bson.M{CreateTimeKey: bson.M{"$exists": false}})
+}
+
 // === DB Logic ===
 
+// selector returns the query matching the entry in the db.
+func (e Entry) selector() bson.M {
+	return bson.M{
+		TaskIdKey:   e.TaskId,
+		TaskNameKey: e.TaskDisplayName,
+		BuildIdKey:  e.BuildId,
+	}
+}
+
 // Upsert updates the files entry in the db if an entry already exists,
 // overwriting the existing file data. If no entry exists, one is created
 func (e Entry) Upsert() error {
 	for _, file := range e.Files {
 		_, err := db.Upsert(
 			Collection,
-			bson.M{
-				TaskIdKey:   e.TaskId,
-				TaskNameKey: e.TaskDisplayName,
-				BuildIdKey:  e.BuildId,
-			},
+			e.selector(),
 			bson.M{
 				"$addToSet": bson.M{
 					FilesKey: file,
 				},
+				"$setOnInsert": bson.M{
+					ProjectKey:    e.Project,
+					VersionKey:    e.Version,
+					RequesterKey:  e.Requester,
+					CreateTimeKey: e.CreateTime,
+				},
 			},
 		)
 		if err != nil {
@@ -55,6 +125,79 @@ func (e Entry) Upsert() error {
 	return nil
 }
 
+// SetTaskDetails records the details of the entry's task with it.
+func (e *Entry) SetTaskDetails(project, version, requester string, createTime time.Time) error {
+	e.Project = project
+	e.Version = version
+	e.Requester = requester
+	e.CreateTime = createTime
+	return db.Update(
+		Collection,
+		e.selector(),
+		bson.M{
+			"$set": bson.M{
+				ProjectKey:    project,
+				VersionKey:    version,
+				RequesterKey:  requester,
+				CreateTimeKey: createTime,
+			},
+		},
+	)
+}
+
+// MarkExpired saves the entry's files, with the ones removed from their
+// stores marked as expired, and marks the entry as expired.
+func (e *Entry) MarkExpired() error {
+	e.Expired = true
+	return db.Update(
+		Collection,
+		e.selector(),
+		bson.M{
+			"$set": bson.M{
+				FilesKey:   e.Files,
+				ExpiredKey: true,
+			},
+		},
+	)
+}
+
+// MarkFileExpired marks the file with the given link, or kept at the given
+// path of the store and bucket, as expired in every entry that refers to it,
+// once it has been removed from its store.
+func MarkFileExpired(link, storeName, bucket, path string) error {
+	for _, ref := range fileReferences(link, storeName, bucket, path) {
+		_, err := db.UpdateAll(
+			Collection,
+			ref,
+			bson.M{"$set": bson.M{FilesKey + ".$." + FileExpiredKey: true}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarkRetained marks the entry's files as kept forever.
+func (e *Entry) MarkRetained() error {
+	e.Retained = true
+	return db.Update(
+		Collection,
+		e.selector(),
+		bson.M{"$set": bson.M{RetainedKey: true}},
+	)
+}
+
+// RecordExpireFailure counts a failed attempt to remove the entry's files.
+func (e *Entry) RecordExpireFailure() error {
+	e.ExpireFailures++
+	return db.Update(
+		Collection,
+		e.selector(),
+		bson.M{"$inc": bson.M{FailuresKey: 1}},
+	)
+}
+
 // FindOne ets one Entry for the given query
 func FindOne(query db.Q) (*Entry, error) {
 	entry := &Entry{}
@@ -65,6 +208,11 @@ func FindOne(query db.Q) (*Entry, error) {
 	return entry, err
 }
 
+// Count returns how many entries match the given query.
+func Count(query db.Q) (int, error) {
+	return db.CountQ(Collection, query)
+}
+
 // FindAll gets every Entry for the given query
 func FindAll(query db.Q) ([]Entry, error) {
 	entries := []Entry{}
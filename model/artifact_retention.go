@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ArtifactRetention is how long a project's artifact files are kept before
+// they're removed from their stores. A period of zero days keeps files
+// forever. Files from versions that were pushed are always kept.
+type ArtifactRetention struct {
+	PatchDays    int `bson:"patch_days" json:"patch_days"`
+	MainlineDays int `bson:"mainline_days" json:"mainline_days"`
+}
+
+// IsSet returns true if any of the project's artifacts expire.
+func (r ArtifactRetention) IsSet() bool {
+	return r.PatchDays > 0 || r.MainlineDays > 0
+}
+
+// Cutoffs returns, for each requester whose artifacts expire, the time
+// before which its artifacts are expired as of now.
+func (r ArtifactRetention) Cutoffs(now time.Time) map[string]time.Time {
+	cutoffs := map[string]time.Time{}
+	if r.PatchDays > 0 {
+		cutoffs[evergreen.PatchVersionRequester] = now.AddDate(0, 0, -r.PatchDays)
+	}
+	if r.MainlineDays > 0 {
+		cutoffs[evergreen.RepotrackerVersionRequester] = now.AddDate(0, 0, -r.MainlineDays)
+	}
+	return cutoffs
+}
+
+// VersionWasPushed returns true if any of the version's tasks pushed a file
+// successfully.
+func VersionWasPushed(versionId string) (bool, error) {
+	tasks, err := task.Find(task.ByVersion(versionId).WithFields(task.IdKey))
+	if err != nil {
+		return false, err
+	}
+	if len(tasks) == 0 {
+		return false, nil
+	}
+	taskIds := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		taskIds = append(taskIds, t.Id)
+	}
+	count, err := db.Count(PushlogCollection, bson.M{
+		PushLogTaskIdKey: bson.M{"$in": taskIds},
+		PushLogStatusKey: evergreen.PushLogSuccess,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArtifactRetentionCutoffs(t *testing.T) {
+	Convey("With a project's artifact retention", t, func() {
+		now := time.Date(2017, 3, 15, 12, 0, 0, 0, time.UTC)
+
+		Convey("no periods should keep everything", func() {
+			retention := ArtifactRetention{}
+			So(retention.IsSet(), ShouldBeFalse)
+			So(retention.Cutoffs(now), ShouldBeEmpty)
+		})
+
+		Convey("each period should set a cutoff for its requester", func() {
+			retention := ArtifactRetention{PatchDays: 7, MainlineDays: 30}
+			So(retention.IsSet(), ShouldBeTrue)
+			cutoffs := retention.Cutoffs(now)
+			So(len(cutoffs), ShouldEqual, 2)
+			So(cutoffs[evergreen.PatchVersionRequester], ShouldResemble, time.Date(2017, 3, 8, 12, 0, 0, 0, time.UTC))
+			So(cutoffs[evergreen.RepotrackerVersionRequester], ShouldResemble, time.Date(2017, 2, 13, 12, 0, 0, 0, time.UTC))
+		})
+	})
+}
+
+func TestVersionWasPushed(t *testing.T) {
+	Convey("With versions whose tasks pushed files", t, func() {
+		testutil.HandleTestingErr(db.ClearCollections(task.Collection, PushlogCollection),
+			t, "error clearing collections")
+
+		tasks := []task.Task{
+			{Id: "pushed_push", Version: "pushed"},
+			{Id: "pushing_push", Version: "pushing"},
+			{Id: "other_compile", Version: "other"},
+		}
+		for _, tsk := range tasks {
+			So(tsk.Insert(), ShouldBeNil)
+		}
+		So((&PushLog{TaskId: "pushed_push", Status: evergreen.PushLogSuccess}).Insert(), ShouldBeNil)
+		So((&PushLog{TaskId: "pushing_push", Status: evergreen.PushLogPushing}).Insert(), ShouldBeNil)
+
+		Convey("only versions with a finished push should count as pushed", func() {
+			pushed, err := VersionWasPushed("pushed")
+			So(err, ShouldBeNil)
+			So(pushed, ShouldBeTrue)
+
+			pushed, err = VersionWasPushed("pushing")
+			So(err, ShouldBeNil)
+			So(pushed, ShouldBeFalse)
+
+			pushed, err = VersionWasPushed("other")
+			So(err, ShouldBeNil)
+			So(pushed, ShouldBeFalse)
+		})
+	})
+}
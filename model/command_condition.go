@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+)
+
+// Requesters a command can be restricted to.
+const (
+	PatchCondition    = "patch"
+	MainlineCondition = "mainline"
+)
+
+// CommandCondition restricts a command, or a function call, to run only when
+// all of its parts hold at the time the command is about to run. Unset parts
+// always hold.
+type CommandCondition struct {
+	// Requester is "patch" to run the command only in patches, or "mainline"
+	// to run it only in versions created by the repotracker.
+	Requester string `yaml:"requester,omitempty" bson:"requester,omitempty"`
+
+	// Expansions maps the names of expansions to the values they must have,
+	// which may themselves contain expansions. An unset expansion has the
+	// value "".
+	Expansions map[string]string `yaml:"expansions,omitempty" bson:"expansions,omitempty"`
+
+	// Status is "failed" to run the command only if an earlier command of
+	// the task failed, or "success" to run it only if none did.
+	Status string `yaml:"status,omitempty" bson:"status,omitempty"`
+
+	// FileExists is a path, relative to the task's working directory, that
+	// must exist.
+	FileExists string `yaml:"file_exists,omitempty" bson:"file_exists,omitempty"`
+}
+
+// Validate returns an error if the condition can never be evaluated.
+func (c *CommandCondition) Validate() error {
+	if c.Requester != "" && c.Requester != PatchCondition && c.Requester != MainlineCondition {
+		return fmt.Errorf("invalid requester '%v': must be '%v' or '%v'",
+			c.Requester, PatchCondition, MainlineCondition)
+	}
+	if c.Status != "" && c.Status != evergreen.TaskSucceeded && c.Status != evergreen.TaskFailed {
+		return fmt.Errorf("invalid status '%v': must be '%v' or '%v'",
+			c.Status, evergreen.TaskSucceeded, evergreen.TaskFailed)
+	}
+	return nil
+}
+
+// Check returns whether the condition holds for a command of the task with
+// the given config, where failed is whether an earlier command of the task
+// failed. If it doesn't hold, Check also returns the reason why.
+func (c *CommandCondition) Check(conf *TaskConfig, failed bool) (bool, string, error) {
+	if c == nil {
+		return true, "", nil
+	}
+	if err := c.Validate(); err != nil {
+		return false, "", err
+	}
+
+	switch c.Requester {
+	case PatchCondition:
+		if conf.Task.Requester != evergreen.PatchVersionRequester {
+			return false, "task is not a patch", nil
+		}
+	case MainlineCondition:
+		if conf.Task.Requester == evergreen.PatchVersionRequester {
+			return false, "task is a patch", nil
+		}
+	}
+
+	switch c.Status {
+	case evergreen.TaskFailed:
+		if !failed {
+			return false, "no earlier command failed", nil
+		}
+	case evergreen.TaskSucceeded:
+		if failed {
+			return false, "an earlier command failed", nil
+		}
+	}
+
+	// check the expansions in a fixed order, so the reason is stable
+	names := make([]string, 0, len(c.Expansions))
+	for name := range c.Expansions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want, err := conf.Expansions.ExpandString(c.Expansions[name])
+		if err != nil {
+			return false, "", fmt.Errorf("error expanding value of '%v': %v", name, err)
+		}
+		if conf.Expansions.Get(name) != want {
+			return false, fmt.Sprintf("expansion '%v' does not have the required value", name), nil
+		}
+	}
+
+	if c.FileExists != "" {
+		file, err := conf.Expansions.ExpandString(c.FileExists)
+		if err != nil {
+			return false, "", fmt.Errorf("error expanding file_exists: %v", err)
+		}
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(conf.WorkDir, path)
+		}
+		if _, err = os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return false, fmt.Sprintf("file '%v' does not exist", file), nil
+			}
+			return false, "", err
+		}
+	}
+
+	return true, "", nil
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model/task"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCommandConditionCheck(t *testing.T) {
+	Convey("With a task config", t, func() {
+		workDir, err := ioutil.TempDir("", "condition")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(workDir)
+
+		conf := &TaskConfig{
+			Task:       &task.Task{Requester: evergreen.PatchVersionRequester},
+			Expansions: command.NewExpansions(map[string]string{"branch": "master", "suffix": "ter"}),
+			WorkDir:    workDir,
+		}
+
+		Convey("a missing condition should hold", func() {
+			var c *CommandCondition
+			run, _, err := c.Check(conf, true)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeTrue)
+		})
+
+		Convey("the requester should be checked", func() {
+			run, _, err := (&CommandCondition{Requester: PatchCondition}).Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeTrue)
+			run, reason, err := (&CommandCondition{Requester: MainlineCondition}).Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeFalse)
+			So(reason, ShouldNotEqual, "")
+		})
+
+		Convey("the outcome of earlier commands should be checked", func() {
+			c := &CommandCondition{Status: evergreen.TaskFailed}
+			run, _, err := c.Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeFalse)
+			run, _, err = c.Check(conf, true)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeTrue)
+		})
+
+		Convey("expansions should be compared after expanding the wanted value", func() {
+			c := &CommandCondition{Expansions: map[string]string{"branch": "mas${suffix}"}}
+			run, _, err := c.Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeTrue)
+			c.Expansions["unset"] = "x"
+			run, _, err = c.Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeFalse)
+		})
+
+		Convey("files should be looked for in the working directory", func() {
+			c := &CommandCondition{FileExists: "core.${branch}"}
+			run, _, err := c.Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeFalse)
+			So(ioutil.WriteFile(filepath.Join(workDir, "core.master"), []byte("core"), 0644), ShouldBeNil)
+			run, _, err = c.Check(conf, false)
+			So(err, ShouldBeNil)
+			So(run, ShouldBeTrue)
+		})
+
+		Convey("an invalid condition should be an error", func() {
+			_, _, err := (&CommandCondition{Status: "maybe"}).Check(conf, false)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,353 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+)
+
+// Dimensions that a cost report can be grouped by
+const (
+	CostByProject   = "project"
+	CostByVariant   = "variant"
+	CostByTask      = "task"
+	CostByRequester = "requester"
+	CostByDay       = "day"
+)
+
+// Requesters as they appear in cost reports
+const (
+	CostRequesterPatch    = "patch"
+	CostRequesterMainline = "mainline"
+)
+
+// CostReportDayFormat is the format of the days in a cost report.
+const CostReportDayFormat = "2006-01-02"
+
+// CostGroupings are all of the dimensions that a cost report can be grouped
+// by, in the order they are reported in.
+var CostGroupings = []string{CostByDay, CostByProject, CostByVariant, CostByTask, CostByRequester}
+
+// HostCostFunc returns the cost of running a host between two times.
+type HostCostFunc func(h *host.Host, start, end time.Time) (float64, error)
+
+// CostRollup is the total cost of the tasks that share the same values of the
+// dimensions that a report is grouped by, or, if Idle is set, of the time a
+// distro's hosts spent running no task. Dimensions that the report is not
+// grouped by, or that do not apply to idle time, are left empty.
+type CostRollup struct {
+	Day          string  `json:"day,omitempty" csv:"day"`
+	Project      string  `json:"project,omitempty" csv:"project"`
+	BuildVariant string  `json:"build_variant,omitempty" csv:"build_variant"`
+	TaskName     string  `json:"task_name,omitempty" csv:"task_name"`
+	Requester    string  `json:"requester,omitempty" csv:"requester"`
+	Distro       string  `json:"distro,omitempty" csv:"distro"`
+	Idle         bool    `json:"idle" csv:"idle"`
+	Count        int     `json:"count" csv:"count"`
+	Hours        float64 `json:"hours" csv:"hours"`
+	Cost         float64 `json:"cost" csv:"cost"`
+}
+
+// CostReport holds the rollups of the cost of the tasks that finished, and
+// of the idle time of the hosts that were up, between two times.
+type CostReport struct {
+	StartTime time.Time    `json:"start_time"`
+	EndTime   time.Time    `json:"end_time"`
+	GroupBy   []string     `json:"group_by"`
+	Rollups   []CostRollup `json:"rollups"`
+	TaskCost  float64      `json:"task_cost"`
+	IdleCost  float64      `json:"idle_cost"`
+	TotalCost float64      `json:"total_cost"`
+	// Errors are problems costing individual hosts, which are left out of
+	// the idle cost
+	Errors []string `json:"errors,omitempty"`
+}
+
+// costRequester maps a task's requester to its name in cost reports.
+func costRequester(requester string) string {
+	if requester == evergreen.PatchVersionRequester {
+		return CostRequesterPatch
+	}
+	return CostRequesterMainline
+}
+
+// costDay returns the UTC day containing t.
+func costDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// overlap returns how long the interval [start, end) overlaps [from, to).
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// ValidateCostGroupings returns an error if any of the groupings is not one
+// of CostGroupings.
+func ValidateCostGroupings(groupBy []string) error {
+	for _, g := range groupBy {
+		found := false
+		for _, valid := range CostGroupings {
+			if g == valid {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid cost grouping '%v', must be one of %v", g, CostGroupings)
+		}
+	}
+	return nil
+}
+
+// CreateCostReport rolls up the cost of the tasks that finished between start
+// and end, grouped by the given dimensions, optionally only for one project.
+// Unless the report is limited to a project, it also rolls up the cost of the
+// time that hosts were up but running no task, by distro and, if grouped by
+// day, by day. The cost of a host's idle time is its share of the host's cost
+// as returned by hostCost.
+func CreateCostReport(start, end time.Time, projectId string, groupBy []string,
+	hostCost HostCostFunc) (*CostReport, error) {
+	if len(groupBy) == 0 {
+		groupBy = CostGroupings
+	}
+	if err := ValidateCostGroupings(groupBy); err != nil {
+		return nil, err
+	}
+	grouped := map[string]bool{}
+	for _, g := range groupBy {
+		grouped[g] = true
+	}
+
+	tasks, err := task.Find(task.ByTimeRun(start, end))
+	if err != nil {
+		return nil, fmt.Errorf("error finding tasks: %v", err)
+	}
+	oldTasks, err := task.FindOld(task.ByTimeRun(start, end))
+	if err != nil {
+		return nil, fmt.Errorf("error finding old tasks: %v", err)
+	}
+	tasks = append(tasks, oldTasks...)
+
+	report := &CostReport{
+		StartTime: start,
+		EndTime:   end,
+		GroupBy:   groupBy,
+		Rollups:   taskCostRollups(tasks, start, end, projectId, grouped),
+	}
+	for _, r := range report.Rollups {
+		report.TaskCost += r.Cost
+	}
+
+	if projectId == "" {
+		dynamicHosts, err := host.Find(host.ByDynamicWithinTime(start, end))
+		if err != nil {
+			return nil, fmt.Errorf("error finding hosts: %v", err)
+		}
+		staticHosts, err := host.Find(host.AllStatic)
+		if err != nil {
+			return nil, fmt.Errorf("error finding static hosts: %v", err)
+		}
+		idle, errs := idleCostRollups(append(dynamicHosts, staticHosts...), tasks, start, end,
+			grouped[CostByDay], hostCost)
+		for _, err := range errs {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		for _, r := range idle {
+			report.IdleCost += r.Cost
+		}
+		report.Rollups = append(report.Rollups, idle...)
+	}
+	report.TotalCost = report.TaskCost + report.IdleCost
+
+	sort.Sort(costRollupsByKey(report.Rollups))
+	return report, nil
+}
+
+// taskCostRollups rolls up the cost of the tasks that finished between start
+// and end by the grouped dimensions.
+func taskCostRollups(tasks []task.Task, start, end time.Time, projectId string,
+	grouped map[string]bool) []CostRollup {
+	rollups := map[CostRollup]*CostRollup{}
+	for _, t := range tasks {
+		if t.FinishTime.Before(start) || t.FinishTime.After(end) {
+			continue
+		}
+		if projectId != "" && t.Project != projectId {
+			continue
+		}
+
+		key := CostRollup{}
+		if grouped[CostByDay] {
+			key.Day = costDay(t.FinishTime).Format(CostReportDayFormat)
+		}
+		if grouped[CostByProject] {
+			key.Project = t.Project
+		}
+		if grouped[CostByVariant] {
+			key.BuildVariant = t.BuildVariant
+		}
+		if grouped[CostByTask] {
+			key.TaskName = t.DisplayName
+		}
+		if grouped[CostByRequester] {
+			key.Requester = costRequester(t.Requester)
+		}
+
+		rollup, ok := rollups[key]
+		if !ok {
+			rollup = &CostRollup{}
+			*rollup = key
+			rollups[key] = rollup
+		}
+		timeTaken := t.TimeTaken
+		if timeTaken == 0 {
+			timeTaken = t.FinishTime.Sub(t.StartTime)
+		}
+		rollup.Count++
+		rollup.Hours += timeTaken.Hours()
+		rollup.Cost += t.Cost
+	}
+
+	out := make([]CostRollup, 0, len(rollups))
+	for _, r := range rollups {
+		out = append(out, *r)
+	}
+	return out
+}
+
+// idleCostRollups rolls up the time between start and end that the hosts
+// were up but not running any of the tasks, by distro and optionally by day.
+// Each host's cost is found once for all of its uptime. Errors costing a host
+// are returned and leave the host's idle cost out.
+func idleCostRollups(hosts []host.Host, tasks []task.Task, start, end time.Time, byDay bool,
+	hostCost HostCostFunc) ([]CostRollup, []error) {
+	tasksByHost := map[string][]task.Task{}
+	for _, t := range tasks {
+		tasksByHost[t.HostId] = append(tasksByHost[t.HostId], t)
+	}
+
+	rollups := map[CostRollup]*CostRollup{}
+	errs := []error{}
+	for i := range hosts {
+		h := &hosts[i]
+
+		// static hosts are counted as up for the whole time, as they are
+		// for host utilization
+		upStart, upEnd := start, end
+		if h.Provider != evergreen.HostTypeStatic {
+			if h.CreationTime.After(upStart) {
+				upStart = h.CreationTime
+			}
+			if !h.TerminationTime.IsZero() && h.TerminationTime.Before(upEnd) {
+				upEnd = h.TerminationTime
+			}
+		}
+		if !upEnd.After(upStart) {
+			continue
+		}
+
+		// the host is costed once for its whole uptime, and its idle time
+		// in each period is charged at the average rate
+		var costPerHour float64
+		if hostCost != nil {
+			cost, err := hostCost(h, upStart, upEnd)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error calculating cost of host %v: %v", h.Id, err))
+			} else {
+				costPerHour = cost / upEnd.Sub(upStart).Hours()
+			}
+		}
+
+		// split the host's uptime into days if the report is grouped by day
+		periods := [][2]time.Time{{upStart, upEnd}}
+		if byDay {
+			periods = nil
+			for day := costDay(upStart); day.Before(upEnd); day = day.AddDate(0, 0, 1) {
+				from, to := day, day.AddDate(0, 0, 1)
+				if from.Before(upStart) {
+					from = upStart
+				}
+				if to.After(upEnd) {
+					to = upEnd
+				}
+				periods = append(periods, [2]time.Time{from, to})
+			}
+		}
+
+		for _, period := range periods {
+			from, to := period[0], period[1]
+			uptime := to.Sub(from)
+			busy := time.Duration(0)
+			for _, t := range tasksByHost[h.Id] {
+				busy += overlap(t.StartTime, t.FinishTime, from, to)
+			}
+			idle := uptime - busy
+			if idle <= 0 {
+				continue
+			}
+
+			key := CostRollup{Distro: h.Distro.Id, Idle: true}
+			if byDay {
+				key.Day = costDay(from).Format(CostReportDayFormat)
+			}
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &CostRollup{}
+				*rollup = key
+				rollups[key] = rollup
+			}
+			rollup.Count++
+			rollup.Hours += idle.Hours()
+			rollup.Cost += costPerHour * idle.Hours()
+		}
+	}
+
+	out := make([]CostRollup, 0, len(rollups))
+	for _, r := range rollups {
+		out = append(out, *r)
+	}
+	return out, errs
+}
+
+// costRollupsByKey sorts rollups by day, then with task rollups before idle
+// rollups, then by the rest of their dimensions.
+type costRollupsByKey []CostRollup
+
+func (c costRollupsByKey) Len() int      { return len(c) }
+func (c costRollupsByKey) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c costRollupsByKey) Less(i, j int) bool {
+	a, b := c[i], c[j]
+	if a.Day != b.Day {
+		return a.Day < b.Day
+	}
+	if a.Idle != b.Idle {
+		return !a.Idle
+	}
+	if a.Project != b.Project {
+		return a.Project < b.Project
+	}
+	if a.BuildVariant != b.BuildVariant {
+		return a.BuildVariant < b.BuildVariant
+	}
+	if a.TaskName != b.TaskName {
+		return a.TaskName < b.TaskName
+	}
+	if a.Requester != b.Requester {
+		return a.Requester < b.Requester
+	}
+	return a.Distro < b.Distro
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var costDay1 = time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func costTestTasks() []task.Task {
+	return []task.Task{
+		{Id: "t1", Project: "p1", BuildVariant: "linux", DisplayName: "compile", Requester: evergreen.RepotrackerVersionRequester,
+			HostId: "h1", StartTime: costDay1.Add(time.Hour), FinishTime: costDay1.Add(2 * time.Hour), Cost: 1},
+		{Id: "t2", Project: "p1", BuildVariant: "linux", DisplayName: "compile", Requester: evergreen.PatchVersionRequester,
+			HostId: "h1", StartTime: costDay1.Add(3 * time.Hour), FinishTime: costDay1.Add(4 * time.Hour), Cost: 2},
+		{Id: "t3", Project: "p2", BuildVariant: "osx", DisplayName: "test", Requester: evergreen.RepotrackerVersionRequester,
+			HostId: "h2", StartTime: costDay1.Add(23 * time.Hour), FinishTime: costDay1.Add(25 * time.Hour), Cost: 4},
+	}
+}
+
+func TestTaskCostRollups(t *testing.T) {
+	Convey("With tasks in two projects finishing on two days", t, func() {
+		tasks := costTestTasks()
+		start, end := costDay1, costDay1.Add(48*time.Hour)
+
+		Convey("grouping by project should sum the tasks of each project", func() {
+			rollups := taskCostRollups(tasks, start, end, "", map[string]bool{CostByProject: true})
+			So(len(rollups), ShouldEqual, 2)
+			for _, r := range rollups {
+				switch r.Project {
+				case "p1":
+					So(r.Count, ShouldEqual, 2)
+					So(r.Cost, ShouldEqual, 3)
+					So(r.Hours, ShouldEqual, 2)
+				case "p2":
+					So(r.Count, ShouldEqual, 1)
+					So(r.Cost, ShouldEqual, 4)
+				}
+				So(r.Day, ShouldEqual, "")
+				So(r.BuildVariant, ShouldEqual, "")
+			}
+		})
+
+		Convey("grouping by requester and day should split patches from mainline and days by finish time", func() {
+			rollups := taskCostRollups(tasks, start, end, "",
+				map[string]bool{CostByRequester: true, CostByDay: true})
+			So(len(rollups), ShouldEqual, 3)
+			for _, r := range rollups {
+				if r.Requester == CostRequesterPatch {
+					So(r.Day, ShouldEqual, "2017-03-01")
+					So(r.Cost, ShouldEqual, 2)
+				} else if r.Day == "2017-03-02" {
+					So(r.Cost, ShouldEqual, 4)
+				}
+			}
+		})
+
+		Convey("limiting to a project or a time range should leave other tasks out", func() {
+			rollups := taskCostRollups(tasks, start, end, "p2", map[string]bool{CostByProject: true})
+			So(len(rollups), ShouldEqual, 1)
+			So(rollups[0].Project, ShouldEqual, "p2")
+
+			rollups = taskCostRollups(tasks, start, costDay1.Add(24*time.Hour), "", map[string]bool{CostByProject: true})
+			So(len(rollups), ShouldEqual, 1)
+			So(rollups[0].Project, ShouldEqual, "p1")
+		})
+	})
+}
+
+func TestIdleCostRollups(t *testing.T) {
+	Convey("With a host that is up for a day and a half and runs two tasks", t, func() {
+		tasks := costTestTasks()
+		hosts := []host.Host{{
+			Id:              "h1",
+			Distro:          distro.Distro{Id: "d1"},
+			CreationTime:    costDay1,
+			TerminationTime: costDay1.Add(36 * time.Hour),
+		}}
+		start, end := costDay1, costDay1.Add(48*time.Hour)
+		hourly := func(h *host.Host, start, end time.Time) (float64, error) {
+			return end.Sub(start).Hours(), nil
+		}
+
+		Convey("its idle time should be its uptime minus the time spent running tasks", func() {
+			rollups, errs := idleCostRollups(hosts, tasks, start, end, false, hourly)
+			So(errs, ShouldBeEmpty)
+			So(len(rollups), ShouldEqual, 1)
+			So(rollups[0].Idle, ShouldBeTrue)
+			So(rollups[0].Distro, ShouldEqual, "d1")
+			So(rollups[0].Hours, ShouldEqual, 34)
+			So(rollups[0].Cost, ShouldEqual, 34)
+		})
+
+		Convey("grouping by day should split its idle time at midnight", func() {
+			calls := 0
+			counted := func(h *host.Host, start, end time.Time) (float64, error) {
+				calls++
+				return hourly(h, start, end)
+			}
+			rollups, errs := idleCostRollups(hosts, tasks, start, end, true, counted)
+			So(errs, ShouldBeEmpty)
+			So(len(rollups), ShouldEqual, 2)
+			for _, r := range rollups {
+				switch r.Day {
+				case "2017-03-01":
+					So(r.Hours, ShouldEqual, 22)
+					So(r.Cost, ShouldAlmostEqual, 22)
+				case "2017-03-02":
+					So(r.Hours, ShouldEqual, 12)
+					So(r.Cost, ShouldAlmostEqual, 12)
+				}
+			}
+
+			Convey("and cost the host only once", func() {
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("a host that can't be costed should still have its idle time reported", func() {
+			failing := func(h *host.Host, start, end time.Time) (float64, error) {
+				return 0, errors.New("no prices")
+			}
+			rollups, errs := idleCostRollups(hosts, tasks, start, end, false, failing)
+			So(len(errs), ShouldEqual, 1)
+			So(rollups[0].Hours, ShouldEqual, 34)
+			So(rollups[0].Cost, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestCreateCostReport(t *testing.T) {
+	Convey("With finished tasks and a host in the database", t, func() {
+		testutil.HandleTestingErr(db.ClearCollections(task.Collection, task.OldCollection, host.Collection),
+			t, "error clearing collections")
+		for _, tsk := range costTestTasks() {
+			tsk.Status = evergreen.TaskSucceeded
+			So(tsk.Insert(), ShouldBeNil)
+		}
+		h := &host.Host{
+			Id:              "h1",
+			Distro:          distro.Distro{Id: "d1"},
+			Provider:        "ec2",
+			Status:          evergreen.HostTerminated,
+			CreationTime:    costDay1,
+			TerminationTime: costDay1.Add(36 * time.Hour),
+		}
+		So(h.Insert(), ShouldBeNil)
+		start, end := costDay1, costDay1.Add(48*time.Hour)
+
+		Convey("the report should total the task and idle costs", func() {
+			report, err := CreateCostReport(start, end, "", []string{CostByProject}, nil)
+			So(err, ShouldBeNil)
+			So(report.TaskCost, ShouldEqual, 7)
+			So(len(report.Rollups), ShouldEqual, 3)
+			So(report.Rollups[2].Idle, ShouldBeTrue)
+		})
+
+		Convey("a report for a project should leave out idle time", func() {
+			report, err := CreateCostReport(start, end, "p1", nil, nil)
+			So(err, ShouldBeNil)
+			So(report.TaskCost, ShouldEqual, 3)
+			So(report.IdleCost, ShouldEqual, 0)
+			for _, r := range report.Rollups {
+				So(r.Idle, ShouldBeFalse)
+			}
+		})
+
+		Convey("an unknown grouping should be an error", func() {
+			_, err := CreateCostReport(start, end, "", []string{"nope"}, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package distro
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// Bootstrap step types
+const (
+	BootstrapStepPackages = "packages"
+	BootstrapStepFetch    = "fetch"
+	BootstrapStepScript   = "script"
+)
+
+const (
+	DefaultBootstrapStepTimeout = 10 * time.Minute
+	DefaultBootstrapRetryWait   = 10 * time.Second
+)
+
+// BootstrapStep is a single named step run, in order, while provisioning a
+// host of the distro.
+type BootstrapStep struct {
+	Name string `bson:"name" json:"name" mapstructure:"name"`
+	Type string `bson:"type" json:"type" mapstructure:"type"`
+
+	// Packages are installed with whichever of apt-get, yum, zypper or brew
+	// is available on the host. Used by "packages" steps.
+	Packages []string `bson:"packages,omitempty" json:"packages,omitempty" mapstructure:"packages,omitempty"`
+
+	// URL is downloaded to Path, and verified against SHA256 if it is set.
+	// Used by "fetch" steps.
+	URL    string `bson:"url,omitempty" json:"url,omitempty" mapstructure:"url,omitempty"`
+	Path   string `bson:"path,omitempty" json:"path,omitempty" mapstructure:"path,omitempty"`
+	SHA256 string `bson:"sha256,omitempty" json:"sha256,omitempty" mapstructure:"sha256,omitempty"`
+
+	// Script is run with sh. Used by "script" steps.
+	Script string `bson:"script,omitempty" json:"script,omitempty" mapstructure:"script,omitempty"`
+
+	// Check is a shell snippet that exits zero if the step's work is already
+	// done. The step is skipped if it passes beforehand, and fails if it does
+	// not pass afterwards.
+	Check string `bson:"check,omitempty" json:"check,omitempty" mapstructure:"check,omitempty"`
+
+	TimeoutSecs   int `bson:"timeout_secs,omitempty" json:"timeout_secs,omitempty" mapstructure:"timeout_secs,omitempty"`
+	Retries       int `bson:"retries,omitempty" json:"retries,omitempty" mapstructure:"retries,omitempty"`
+	RetryWaitSecs int `bson:"retry_wait_secs,omitempty" json:"retry_wait_secs,omitempty" mapstructure:"retry_wait_secs,omitempty"`
+}
+
+// Timeout returns how long a single attempt at the step may take.
+func (s *BootstrapStep) Timeout() time.Duration {
+	if s.TimeoutSecs > 0 {
+		return time.Duration(s.TimeoutSecs) * time.Second
+	}
+	return DefaultBootstrapStepTimeout
+}
+
+// RetryWait returns how long to wait between attempts at the step.
+func (s *BootstrapStep) RetryWait() time.Duration {
+	if s.RetryWaitSecs > 0 {
+		return time.Duration(s.RetryWaitSecs) * time.Second
+	}
+	return DefaultBootstrapRetryWait
+}
+
+// CheckScript returns the script that checks whether the step is already
+// done, or an empty string if the step has no check. Fetch steps with a
+// checksum are done if the file already matches it.
+func (s *BootstrapStep) CheckScript() string {
+	if s.Check != "" {
+		return s.Check
+	}
+	if s.Type == BootstrapStepFetch && s.SHA256 != "" {
+		return sha256CheckScript(s.Path, s.SHA256)
+	}
+	return ""
+}
+
+// RunScript returns the shell script that performs the step.
+func (s *BootstrapStep) RunScript() (string, error) {
+	switch s.Type {
+	case BootstrapStepPackages:
+		if len(s.Packages) == 0 {
+			return "", fmt.Errorf("bootstrap step '%v' has no packages", s.Name)
+		}
+		quoted := make([]string, 0, len(s.Packages))
+		for _, pkg := range s.Packages {
+			quoted = append(quoted, util.ShellQuote(pkg))
+		}
+		pkgs := strings.Join(quoted, " ")
+		return strings.Join([]string{
+			"set -o errexit",
+			"if command -v apt-get >/dev/null 2>&1; then",
+			"  DEBIAN_FRONTEND=noninteractive apt-get install -y " + pkgs,
+			"elif command -v yum >/dev/null 2>&1; then",
+			"  yum install -y " + pkgs,
+			"elif command -v zypper >/dev/null 2>&1; then",
+			"  zypper --non-interactive install " + pkgs,
+			"elif command -v brew >/dev/null 2>&1; then",
+			"  brew install " + pkgs,
+			"else",
+			"  echo 'no supported package manager found' >&2",
+			"  exit 1",
+			"fi",
+		}, "\n"), nil
+	case BootstrapStepFetch:
+		if s.URL == "" || s.Path == "" {
+			return "", fmt.Errorf("bootstrap step '%v' must have a url and a path", s.Name)
+		}
+		lines := []string{
+			"set -o errexit",
+			fmt.Sprintf("mkdir -p \"$(dirname %v)\"", util.ShellQuote(s.Path)),
+			fmt.Sprintf("curl --fail --silent --show-error --location --output %v %v",
+				util.ShellQuote(s.Path), util.ShellQuote(s.URL)),
+		}
+		if s.SHA256 != "" {
+			lines = append(lines, sha256CheckScript(s.Path, s.SHA256))
+		}
+		return strings.Join(lines, "\n"), nil
+	case BootstrapStepScript:
+		if s.Script == "" {
+			return "", fmt.Errorf("bootstrap step '%v' has no script", s.Name)
+		}
+		return s.Script, nil
+	default:
+		return "", fmt.Errorf("bootstrap step '%v' has unknown type '%v'", s.Name, s.Type)
+	}
+}
+
+// sha256CheckScript returns a script that exits zero if the file at path has
+// the given SHA-256 checksum.
+func sha256CheckScript(path, sum string) string {
+	return fmt.Sprintf("echo %v | (sha256sum -c - || shasum -a 256 -c -) >/dev/null 2>&1",
+		util.ShellQuote(strings.ToLower(sum)+"  "+path))
+}
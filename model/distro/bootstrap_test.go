@@ -0,0 +1,64 @@
+package distro
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBootstrapScriptQuoting(t *testing.T) {
+	Convey("With bootstrap steps whose values hold quotes and spaces", t, func() {
+		dir, err := ioutil.TempDir("", "bootstrap test")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		contents := []byte("tool contents")
+		src := filepath.Join(dir, "it's a source")
+		So(ioutil.WriteFile(src, contents, 0644), ShouldBeNil)
+		digest := sha256.Sum256(contents)
+		dest := filepath.Join(dir, "don't split", "$(touch injected) tool")
+
+		step := BootstrapStep{
+			Name:   "fetch",
+			Type:   BootstrapStepFetch,
+			URL:    (&url.URL{Scheme: "file", Path: src}).String(),
+			Path:   dest,
+			SHA256: hex.EncodeToString(digest[:]),
+		}
+
+		Convey("a fetch step should download to the path as given", func() {
+			script, err := step.RunScript()
+			So(err, ShouldBeNil)
+			out, err := exec.Command("sh", "-c", script).CombinedOutput()
+			So(err, ShouldBeNil)
+			So(string(out), ShouldEqual, "")
+
+			fetched, err := ioutil.ReadFile(dest)
+			So(err, ShouldBeNil)
+			So(fetched, ShouldResemble, contents)
+			_, err = os.Stat("injected")
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			Convey("and its checksum check should pass", func() {
+				So(exec.Command("sh", "-c", step.CheckScript()).Run(), ShouldBeNil)
+			})
+		})
+
+		Convey("each package should be passed to the package manager as one word", func() {
+			step := BootstrapStep{Name: "packages", Type: BootstrapStepPackages,
+				Packages: []string{"git", "it's; rm -rf /"}}
+			script, err := step.RunScript()
+			So(err, ShouldBeNil)
+			So(script, ShouldContainSubstring, `apt-get install -y 'git' 'it'\''s; rm -rf /'`)
+			So(strings.Count(script, "rm -rf /"), ShouldEqual, 4)
+		})
+	})
+}
@@ -22,6 +22,9 @@ var (
 
 	UserDataKey = bsonutil.MustHaveTag(Distro{}, "UserData")
 
+	AliasesKey = bsonutil.MustHaveTag(Distro{}, "Aliases")
+	TagsKey    = bsonutil.MustHaveTag(Distro{}, "Tags")
+
 	SpawnAllowedKey = bsonutil.MustHaveTag(Distro{}, "SpawnAllowed")
 	ExpansionsKey   = bsonutil.MustHaveTag(Distro{}, "Expansions")
 
@@ -23,6 +23,23 @@ type Distro struct {
 	SSHOptions  []string `bson:"ssh_options,omitempty" json:"ssh_options,omitempty" mapstructure:"ssh_options,omitempty"`
 	UserData    UserData `bson:"user_data,omitempty" json:"user_data,omitempty" mapstructure:"user_data,omitempty"`
 
+	// BootstrapSteps are run in order before the setup script.
+	BootstrapSteps []BootstrapStep `bson:"bootstrap_steps,omitempty" json:"bootstrap_steps,omitempty" mapstructure:"bootstrap_steps,omitempty"`
+
+	// PullAgent, if set, starts a long-running agent on each host that polls
+	// the API server for its tasks and updates itself, instead of starting a
+	// new agent over SSH for every task.
+	PullAgent bool `bson:"pull_agent,omitempty" json:"pull_agent,omitempty" mapstructure:"pull_agent,omitempty"`
+
+	// Aliases are other names that projects can use to run tasks on the
+	// distro, such as a name it used to have, or one it shares with distros
+	// that were split from it.
+	Aliases []string `bson:"aliases,omitempty" json:"aliases,omitempty" mapstructure:"aliases,omitempty"`
+
+	// Tags describe the capabilities of the distro's hosts, e.g. "linux" or
+	// "large-disk", so that projects can select distros by what they need.
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty" mapstructure:"tags,omitempty"`
+
 	SpawnAllowed bool        `bson:"spawn_allowed" json:"spawn_allowed,omitempty" mapstructure:"spawn_allowed,omitempty"`
 	Expansions   []Expansion `bson:"expansions,omitempty" json:"expansions,omitempty" mapstructure:"expansions,omitempty"`
 }
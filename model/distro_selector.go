@@ -0,0 +1,144 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// Distro selectors let a build variant's run_on, or a task's distros, refer
+// to distros by alias or capability tag instead of by id, using the same
+// syntax as task and variant selectors. Aliases are selected like names. For
+// example:
+//   "rhel62" runs on the distro with the id or alias "rhel62"
+//   ".linux .large-disk" runs on any distro tagged both "linux" and "large-disk"
+//   ".linux !.gpu" runs on any distro tagged "linux" but not "gpu"
+
+// selectableDistro lets distros be evaluated by the tagSelectorEvaluator.
+type selectableDistro struct {
+	*distro.Distro
+}
+
+func (d selectableDistro) name() string   { return d.Id }
+func (d selectableDistro) tags() []string { return d.Tags }
+
+// IsDistroSelector returns true if a run_on entry selects distros by tag, or
+// with more than one criterion, rather than naming a single distro or alias.
+func IsDistroSelector(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) != 1 {
+		return true
+	}
+	return strings.IndexAny(fields[0], InvalidCriterionRunes) == 0
+}
+
+// DistroSelectorEvaluator resolves the run_on entries of a project into the
+// ids of the distros they refer to.
+type DistroSelectorEvaluator struct {
+	tagEval *tagSelectorEvaluator
+	distros map[string]*distro.Distro
+	aliases map[string][]string
+}
+
+// NewDistroSelectorEvaluator returns a DistroSelectorEvaluator for the given
+// distros.
+func NewDistroSelectorEvaluator(distros []distro.Distro) *DistroSelectorEvaluator {
+	var selectees []tagged
+	byId := map[string]*distro.Distro{}
+	aliases := map[string][]string{}
+	for i := range distros {
+		d := &distros[i]
+		selectees = append(selectees, selectableDistro{d})
+		byId[d.Id] = d
+		for _, alias := range d.Aliases {
+			aliases[alias] = append(aliases[alias], d.Id)
+		}
+	}
+	return &DistroSelectorEvaluator{
+		tagEval: newTagSelectorEvaluator(selectees),
+		distros: byId,
+		aliases: aliases,
+	}
+}
+
+// Resolve returns the ids of the distros that a run_on entry refers to, best
+// match first. A distro id resolves to itself, an alias to the distros that
+// have it, and a selector to the distros that satisfy it. Plain names that
+// match no distro are returned as they are, so that tasks naming a distro
+// that doesn't exist yet keep their place in its queue.
+func (e *DistroSelectorEvaluator) Resolve(entry string) ([]string, error) {
+	if !IsDistroSelector(entry) {
+		name := strings.TrimSpace(entry)
+		if _, ok := e.distros[name]; ok {
+			return []string{name}, nil
+		}
+		if ids, ok := e.aliases[name]; ok {
+			return e.byPreference(ids), nil
+		}
+		return []string{name}, nil
+	}
+
+	s := ParseSelector(entry)
+	results := []string{}
+	for i, sc := range s {
+		names, err := e.evalCriterion(sc)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", s, err)
+		}
+		if i == 0 {
+			results = names
+		} else {
+			results = util.StringSliceIntersection(results, names)
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no distro satisfies selector '%v'", s)
+	}
+	return e.byPreference(results), nil
+}
+
+// evalCriterion returns the ids of the distros that fulfill a single
+// criterion, treating names that are aliases as the distros that have them.
+func (e *DistroSelectorEvaluator) evalCriterion(sc selectCriterion) ([]string, error) {
+	ids, isAlias := e.aliases[sc.name]
+	if sc.tagged || e.distros[sc.name] != nil || !isAlias {
+		return e.tagEval.evalCriterion(sc)
+	}
+	if !sc.negated {
+		return ids, nil
+	}
+	names := []string{}
+	for _, item := range e.tagEval.items {
+		if !util.SliceContains(ids, item.name()) {
+			names = append(names, item.name())
+		}
+	}
+	return names, nil
+}
+
+// byPreference sorts distro ids so that the distros with the fewest
+// capability tags come first, leaving more capable distros free for the
+// tasks that need them.
+func (e *DistroSelectorEvaluator) byPreference(ids []string) []string {
+	sorted := distrosByPreference{ids: util.UniqueStrings(ids), distros: e.distros}
+	sort.Sort(sorted)
+	return sorted.ids
+}
+
+type distrosByPreference struct {
+	ids     []string
+	distros map[string]*distro.Distro
+}
+
+func (d distrosByPreference) Len() int      { return len(d.ids) }
+func (d distrosByPreference) Swap(i, j int) { d.ids[i], d.ids[j] = d.ids[j], d.ids[i] }
+func (d distrosByPreference) Less(i, j int) bool {
+	a, b := len(d.distros[d.ids[i]].Tags), len(d.distros[d.ids[j]].Tags)
+	if a != b {
+		return a < b
+	}
+	return d.ids[i] < d.ids[j]
+}
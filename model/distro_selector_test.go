@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDistroSelectorEvaluator(t *testing.T) {
+	Convey("With a set of distros with aliases and capability tags", t, func() {
+		distros := []distro.Distro{
+			{Id: "rhel62-small", Aliases: []string{"rhel62"}, Tags: []string{"linux"}},
+			{Id: "rhel62-large", Aliases: []string{"rhel62"}, Tags: []string{"linux", "large-disk"}},
+			{Id: "ubuntu-gpu", Tags: []string{"linux", "gpu"}},
+			{Id: "windows", Aliases: []string{"win"}},
+		}
+		eval := NewDistroSelectorEvaluator(distros)
+
+		Convey("entries should be recognized as plain names or selectors", func() {
+			So(IsDistroSelector("rhel62"), ShouldBeFalse)
+			So(IsDistroSelector(".linux"), ShouldBeTrue)
+			So(IsDistroSelector("!windows"), ShouldBeTrue)
+			So(IsDistroSelector("rhel62 win"), ShouldBeTrue)
+		})
+
+		Convey("a distro id should resolve to itself", func() {
+			ids, err := eval.Resolve("ubuntu-gpu")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"ubuntu-gpu"})
+		})
+
+		Convey("an alias should resolve to its distros, least capable first", func() {
+			ids, err := eval.Resolve("rhel62")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"rhel62-small", "rhel62-large"})
+		})
+
+		Convey("an unknown name should be passed through", func() {
+			ids, err := eval.Resolve("solaris")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"solaris"})
+		})
+
+		Convey("a tag selector should resolve to the distros that satisfy it", func() {
+			ids, err := eval.Resolve(".linux")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"rhel62-small", "rhel62-large", "ubuntu-gpu"})
+
+			ids, err = eval.Resolve(".linux !.gpu")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"rhel62-small", "rhel62-large"})
+
+			ids, err = eval.Resolve(".linux .large-disk")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"rhel62-large"})
+		})
+
+		Convey("a negated alias should exclude the alias's distros", func() {
+			ids, err := eval.Resolve("!rhel62")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"windows", "ubuntu-gpu"})
+		})
+
+		Convey("a selector that matches nothing should error", func() {
+			_, err := eval.Resolve(".linux .windows")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -31,6 +31,16 @@ func HostEventsInOrder(id string) db.Q {
 	return HostEventsForId(id).Sort([]string{TimestampKey})
 }
 
+// HostStatusEventsInOrder returns the creation and status change events of a
+// host, oldest first.
+func HostStatusEventsInOrder(id string) db.Q {
+	return db.Query(bson.M{
+		DataKey + "." + ResourceTypeKey: ResourceTypeHost,
+		ResourceIdKey:                   id,
+		TypeKey:                         bson.M{"$in": []string{EventHostCreated, EventHostStatusChanged}},
+	}).Sort([]string{TimestampKey})
+}
+
 // Task Events
 func TaskEventsForId(id string) db.Q {
 	return db.Query(bson.D{
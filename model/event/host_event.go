@@ -23,6 +23,17 @@ const (
 	EventHostMonitorFlag        = "HOST_MONITOR_FLAG"
 	EventTaskFinished           = "HOST_TASK_FINISHED"
 	EventHostTeardown           = "HOST_TEARDOWN"
+	EventHostStopped            = "HOST_STOPPED"
+	EventHostStarted            = "HOST_STARTED"
+	EventHostExpirationExtended = "HOST_EXPIRATION_EXTENDED"
+	EventHostBootstrapStep      = "HOST_BOOTSTRAP_STEP"
+	EventHostQuarantined        = "HOST_QUARANTINED"
+	EventHostHealthCheck        = "HOST_HEALTH_CHECK"
+
+	// bootstrap step statuses
+	BootstrapStepSucceeded = "succeeded"
+	BootstrapStepFailed    = "failed"
+	BootstrapStepSkipped   = "skipped"
 )
 
 // implements EventData
@@ -40,6 +51,11 @@ type HostEventData struct {
 	MonitorOp  string        `bson:"monitor_op,omitempty" json:"monitor,omitempty"`
 	Successful bool          `bson:"successful,omitempty" json:"successful"`
 	Duration   time.Duration `bson:"duration,omitempty" json:"duration"`
+	User       string        `bson:"usr,omitempty" json:"user,omitempty"`
+	Expiration time.Time     `bson:"exp,omitempty" json:"expiration,omitempty"`
+	Step       string        `bson:"step,omitempty" json:"step,omitempty"`
+	StepStatus string        `bson:"step_st,omitempty" json:"step_status,omitempty"`
+	Attempts   int           `bson:"att,omitempty" json:"attempts,omitempty"`
 }
 
 func (self HostEventData) IsValid() bool {
@@ -108,3 +124,36 @@ func LogHostTeardown(hostId, teardownLogs string, success bool, duration time.Du
 func LogMonitorOperation(hostId string, op string) {
 	LogHostEvent(hostId, EventHostMonitorFlag, HostEventData{MonitorOp: op})
 }
+
+func LogHostStopped(hostId, user string) {
+	LogHostEvent(hostId, EventHostStopped, HostEventData{User: user})
+}
+
+func LogHostStarted(hostId, user string) {
+	LogHostEvent(hostId, EventHostStarted, HostEventData{User: user})
+}
+
+func LogHostExpirationExtended(hostId, user string, expiration time.Time) {
+	LogHostEvent(hostId, EventHostExpirationExtended,
+		HostEventData{User: user, Expiration: expiration})
+}
+
+func LogHostBootstrapStep(hostId, step, status, logs string, attempts int, duration time.Duration) {
+	LogHostEvent(hostId, EventHostBootstrapStep, HostEventData{
+		Step:       step,
+		StepStatus: status,
+		Logs:       logs,
+		Attempts:   attempts,
+		Duration:   duration,
+		Successful: status != BootstrapStepFailed,
+	})
+}
+
+func LogHostQuarantined(hostId, reason string) {
+	LogHostEvent(hostId, EventHostQuarantined, HostEventData{Logs: reason})
+}
+
+func LogHostHealthCheck(hostId, user, logs string, success bool, duration time.Duration) {
+	LogHostEvent(hostId, EventHostHealthCheck,
+		HostEventData{User: user, Logs: logs, Successful: success, Duration: duration})
+}
@@ -12,16 +12,17 @@ const (
 	ResourceTypeTask = "TASK"
 
 	// event types
-	TaskCreated      = "TASK_CREATED"
-	TaskDispatched   = "TASK_DISPATCHED"
-	TaskUndispatched = "TASK_UNDISPATCHED"
-	TaskStarted      = "TASK_STARTED"
-	TaskFinished     = "TASK_FINISHED"
-	TaskRestarted    = "TASK_RESTARTED"
-	TaskActivated    = "TASK_ACTIVATED"
-	TaskDeactivated  = "TASK_DEACTIVATED"
-	TaskAbortRequest = "TASK_ABORT_REQUEST"
-	TaskScheduled    = "TASK_SCHEDULED"
+	TaskCreated       = "TASK_CREATED"
+	TaskDispatched    = "TASK_DISPATCHED"
+	TaskUndispatched  = "TASK_UNDISPATCHED"
+	TaskStarted       = "TASK_STARTED"
+	TaskFinished      = "TASK_FINISHED"
+	TaskRestarted     = "TASK_RESTARTED"
+	TaskActivated     = "TASK_ACTIVATED"
+	TaskDeactivated   = "TASK_DEACTIVATED"
+	TaskAbortRequest  = "TASK_ABORT_REQUEST"
+	TaskScheduled     = "TASK_SCHEDULED"
+	TaskUnschedulable = "TASK_UNSCHEDULABLE"
 )
 
 // implements Data
@@ -32,6 +33,7 @@ type TaskEventData struct {
 	UserId       string    `bson:"u_id,omitempty" json:"user_id,omitempty"`
 	Status       string    `bson:"s,omitempty" json:"status,omitempty"`
 	Timestamp    time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
+	Message      string    `bson:"m,omitempty" json:"message,omitempty"`
 }
 
 func (self TaskEventData) IsValid() bool {
@@ -95,3 +97,9 @@ func LogTaskScheduled(taskId string, scheduledTime time.Time) {
 	LogTaskEvent(taskId, TaskScheduled,
 		TaskEventData{Timestamp: scheduledTime})
 }
+
+// LogTaskUnschedulable records that the scheduler can't find a distro for
+// the task to run on, and why.
+func LogTaskUnschedulable(taskId string, reason string) {
+	LogTaskEvent(taskId, TaskUnschedulable, TaskEventData{Message: reason})
+}
@@ -41,12 +41,15 @@ var (
 	LTCKey                   = bsonutil.MustHaveTag(Host{}, "LastTaskCompleted")
 	StatusKey                = bsonutil.MustHaveTag(Host{}, "Status")
 	AgentRevisionKey         = bsonutil.MustHaveTag(Host{}, "AgentRevision")
+	SecretKey                = bsonutil.MustHaveTag(Host{}, "Secret")
+	LastAgentContactKey      = bsonutil.MustHaveTag(Host{}, "LastAgentContact")
 	StartedByKey             = bsonutil.MustHaveTag(Host{}, "StartedBy")
 	InstanceTypeKey          = bsonutil.MustHaveTag(Host{}, "InstanceType")
 	NotificationsKey         = bsonutil.MustHaveTag(Host{}, "Notifications")
 	UserDataKey              = bsonutil.MustHaveTag(Host{}, "UserData")
 	LastReachabilityCheckKey = bsonutil.MustHaveTag(Host{}, "LastReachabilityCheck")
 	UnreachableSinceKey      = bsonutil.MustHaveTag(Host{}, "UnreachableSince")
+	ConsecutiveFailuresKey   = bsonutil.MustHaveTag(Host{}, "ConsecutiveFailures")
 )
 
 // === Queries ===
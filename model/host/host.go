@@ -9,7 +9,6 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/util"
-	"github.com/tychoish/grip/slogger"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -46,6 +45,10 @@ type Host struct {
 	// True if this host was created manually by a user (i.e. with spawnhost)
 	UserHost      bool   `bson:"user_host" json:"user_host"`
 	AgentRevision string `bson:"agent_revision" json:"agent_revision"`
+	// authenticates a long-running agent that polls for tasks on the host
+	Secret string `bson:"secret,omitempty" json:"-"`
+	// the last time that a long-running agent on the host contacted the API server
+	LastAgentContact time.Time `bson:"last_agent_contact,omitempty" json:"last_agent_contact"`
 	// for ec2 dynamic hosts, the instance type requested
 	InstanceType string `bson:"instance_type" json:"instance_type,omitempty"`
 	// stores information on expiration notifications for spawn hosts
@@ -59,6 +62,10 @@ type Host struct {
 
 	// if set, the time at which the host first became unreachable
 	UnreachableSince time.Time `bson:"unreachable_since,omitempty" json:"unreachable_since"`
+
+	// the number of system failures and failed reachability checks in a row
+	// on the host, used to quarantine unhealthy static hosts
+	ConsecutiveFailures int `bson:"consecutive_failures,omitempty" json:"consecutive_failures"`
 }
 
 // ProvisionOptions is struct containing options about how a new host should be set up.
@@ -67,7 +74,8 @@ type ProvisionOptions struct {
 	// be placed onto the host after startup.
 	LoadCLI bool `bson:"load_cli" json:"load_cli"`
 
-	// TaskId if non-empty will trigger the CLI tool to fetch source and artifacts for the given task.
+	// TaskId if non-empty will trigger the CLI tool to fetch source and artifacts for the given task,
+	// and to set up its working directory with its expansions and a script of its commands.
 	// Ignored if LoadCLI is false.
 	TaskId string `bson:"task_id" json:"task_id"`
 
@@ -94,43 +102,17 @@ func (self *Host) IdleTime() time.Duration {
 	return time.Now().Sub(self.CreationTime)
 }
 
+// SetStatus moves the host to the given status, returning a
+// *StatusTransitionError if its current status doesn't allow it.
 func (self *Host) SetStatus(status string) error {
-	if self.Status == evergreen.HostTerminated {
-		msg := fmt.Sprintf("Refusing to mark host %v as"+
-			" %v because it is already terminated", self.Id, status)
-		evergreen.Logger.Logf(slogger.WARN, msg)
-		return fmt.Errorf(msg)
-	}
-
-	event.LogHostStatusChanged(self.Id, self.Status, status)
-
-	self.Status = status
-	return UpdateOne(
-		bson.M{
-			IdKey: self.Id,
-		},
-		bson.M{
-			"$set": bson.M{
-				StatusKey: status,
-			},
-		},
-	)
+	return self.setStatus(status, nil, nil, nil)
 }
 
 // SetInitializing marks the host as initializing. Only allow this
 // if the host is uninitialized.
 func (self *Host) SetInitializing() error {
-	return UpdateOne(
-		bson.M{
-			IdKey:     self.Id,
-			StatusKey: evergreen.HostUninitialized,
-		},
-		bson.M{
-			"$set": bson.M{
-				StatusKey: evergreen.HostInitializing,
-			},
-		},
-	)
+	return self.setStatus(evergreen.HostInitializing,
+		[]string{evergreen.HostUninitialized}, nil, nil)
 }
 
 func (self *Host) SetDecommissioned() error {
@@ -153,24 +135,68 @@ func (self *Host) SetUnreachable() error {
 	return self.SetStatus(evergreen.HostUnreachable)
 }
 
+func (self *Host) SetStopped() error {
+	return self.SetStatus(evergreen.HostStopped)
+}
+
 func (self *Host) SetUnprovisioned() error {
-	return UpdateOne(
-		bson.M{
-			IdKey:     self.Id,
-			StatusKey: evergreen.HostInitializing,
-		},
-		bson.M{
-			"$set": bson.M{
-				StatusKey: evergreen.HostProvisionFailed,
-			},
-		},
-	)
+	return self.setStatus(evergreen.HostProvisionFailed,
+		[]string{evergreen.HostInitializing}, nil, nil)
 }
 
 func (self *Host) SetQuarantined(status string) error {
 	return self.SetStatus(evergreen.HostQuarantined)
 }
 
+// Quarantine takes the host out of service, so that no more tasks are
+// dispatched to it until it is returned to service.
+func (h *Host) Quarantine(reason string) error {
+	if err := h.SetStatus(evergreen.HostQuarantined); err != nil {
+		return err
+	}
+	event.LogHostQuarantined(h.Id, reason)
+	return nil
+}
+
+// IncConsecutiveFailures records a system failure or failed reachability
+// check on the host, returning how many have happened in a row. The count
+// comes from the updated document, since other processes may be counting
+// failures on the same host.
+func (h *Host) IncConsecutiveFailures() (int, error) {
+	updated := &Host{}
+	_, err := db.FindAndModify(
+		Collection,
+		bson.M{IdKey: h.Id},
+		nil,
+		mgo.Change{
+			Update:    bson.M{"$inc": bson.M{ConsecutiveFailuresKey: 1}},
+			ReturnNew: true,
+		},
+		updated,
+	)
+	if err != nil {
+		return h.ConsecutiveFailures, err
+	}
+	h.ConsecutiveFailures = updated.ConsecutiveFailures
+	return h.ConsecutiveFailures, nil
+}
+
+// ResetConsecutiveFailures clears the host's run of failures.
+func (h *Host) ResetConsecutiveFailures() error {
+	if h.ConsecutiveFailures == 0 {
+		return nil
+	}
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{"$unset": bson.M{ConsecutiveFailuresKey: 1}},
+	)
+	if err != nil {
+		return err
+	}
+	h.ConsecutiveFailures = 0
+	return nil
+}
+
 func (self *Host) Terminate() error {
 	err := self.SetTerminated()
 	if err != nil {
@@ -212,22 +238,38 @@ func (self *Host) SetDNSName(dnsName string) error {
 	return err
 }
 
-func (self *Host) MarkAsProvisioned() error {
-	event.LogHostProvisioned(self.Id)
-
-	self.Status = evergreen.HostRunning
-	self.Provisioned = true
-	return UpdateOne(
+// UpdateDNSName overwrites the DNS name of a host, for providers that assign
+// a new one when a stopped host is started again.
+func (self *Host) UpdateDNSName(dnsName string) error {
+	if self.Host == dnsName {
+		return nil
+	}
+	err := UpdateOne(
 		bson.M{
 			IdKey: self.Id,
 		},
 		bson.M{
 			"$set": bson.M{
-				StatusKey:      evergreen.HostRunning,
-				ProvisionedKey: true,
+				DNSKey: dnsName,
 			},
 		},
 	)
+	if err != nil {
+		return err
+	}
+	self.Host = dnsName
+	event.LogHostDNSNameSet(self.Id, dnsName)
+	return nil
+}
+
+func (self *Host) MarkAsProvisioned() error {
+	err := self.setStatus(evergreen.HostRunning, nil, bson.M{ProvisionedKey: true}, nil)
+	if err != nil {
+		return err
+	}
+	event.LogHostProvisioned(self.Id)
+	self.Provisioned = true
+	return nil
 }
 
 // UpdateRunningTask takes two id strings - an old task and a new one - finds
@@ -278,6 +320,50 @@ func (self *Host) SetRunningTask(taskId, agentRevision string,
 	)
 }
 
+// SetSecret generates a new secret for the long-running agent on the host.
+func (self *Host) SetSecret() error {
+	secret := util.RandomString()
+	err := UpdateOne(
+		bson.M{
+			IdKey: self.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				SecretKey: secret,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	self.Secret = secret
+	return nil
+}
+
+// SetAgentContact records that the host's long-running agent, running the
+// given revision, contacted the API server at the given time.
+func (self *Host) SetAgentContact(agentRevision string, contactTime time.Time) error {
+	self.AgentRevision = agentRevision
+	self.LastAgentContact = contactTime
+	return UpdateOne(
+		bson.M{
+			IdKey: self.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				AgentRevisionKey:    agentRevision,
+				LastAgentContactKey: contactTime,
+			},
+		},
+	)
+}
+
+// HasLiveAgent returns true if the host's long-running agent has contacted the
+// API server within the given window.
+func (self *Host) HasLiveAgent(window time.Duration) bool {
+	return !self.LastAgentContact.IsZero() && time.Since(self.LastAgentContact) < window
+}
+
 // SetExpirationTime updates the expiration time of a spawn host
 func (self *Host) SetExpirationTime(expirationTime time.Time) error {
 	// update the in-memory host, then the database
@@ -369,18 +455,18 @@ func (self *Host) SetTaskPid(pid string) error {
 // UpdateReachability sets a host as either running or unreachable,
 // and updates the timestamp of the host's last reachability check.
 // If the host is being set to unreachable, the "unreachable since" field
-// is also set to the current time if it is unset.
+// is also set to the current time if it is unset. Hosts that are no longer
+// running or unreachable, e.g. because they were quarantined or
+// decommissioned since they were checked, are left alone.
 func (h *Host) UpdateReachability(reachable bool) error {
 	status := evergreen.HostRunning
 	setUpdate := bson.M{
-		StatusKey:                status,
 		LastReachabilityCheckKey: time.Now(),
 	}
 
-	update := bson.M{}
+	unsetUpdate := bson.M{}
 	if !reachable {
 		status = evergreen.HostUnreachable
-		setUpdate[StatusKey] = status
 
 		// If the host is being switched to unreachable for the first time, then
 		// "unreachable since" will be unset, so we set it to the current time.
@@ -391,16 +477,16 @@ func (h *Host) UpdateReachability(reachable bool) error {
 		}
 	} else {
 		// host is reachable, so unset the unreachable_since field
-		update["$unset"] = bson.M{UnreachableSinceKey: 1}
+		unsetUpdate[UnreachableSinceKey] = 1
 		h.UnreachableSince = util.ZeroTime
 	}
-	update["$set"] = setUpdate
-
-	event.LogHostStatusChanged(h.Id, h.Status, status)
-
-	h.Status = status
 
-	return UpdateOne(bson.M{IdKey: h.Id}, update)
+	err := h.setStatus(status, []string{evergreen.HostRunning, evergreen.HostUnreachable},
+		setUpdate, unsetUpdate)
+	if IsStatusTransitionError(err) {
+		return nil
+	}
+	return err
 }
 
 func (self *Host) Upsert() (*mgo.ChangeInfo, error) {
@@ -441,13 +527,28 @@ func (self *Host) Remove() error {
 }
 
 func DecommissionHostsWithDistroId(distroId string) error {
-	err := UpdateAll(
-		ByDistroId(distroId),
-		bson.M{
-			"$set": bson.M{
-				StatusKey: evergreen.HostDecommissioned,
-			},
-		},
-	)
-	return err
+	hosts, err := Find(ByDistroId(distroId))
+	if err != nil {
+		return err
+	}
+	return decommissionAll(hosts)
+}
+
+// decommissionAll decommissions each of the hosts whose status allows it.
+// Hosts whose status changed since they were found so that they can no
+// longer be decommissioned are skipped.
+func decommissionAll(hosts []Host) error {
+	for i := range hosts {
+		if !CanTransition(hosts[i].Status, evergreen.HostDecommissioned) {
+			continue
+		}
+		err := hosts[i].SetDecommissioned()
+		if IsStatusTransitionError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error decommissioning host %v: %v", hosts[i].Id, err)
+		}
+	}
+	return nil
 }
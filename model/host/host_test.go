@@ -241,6 +241,34 @@ func TestHostSetDNSName(t *testing.T) {
 	})
 }
 
+func TestHostUpdateDNSName(t *testing.T) {
+
+	Convey("With a host that already has a hostname", t, func() {
+
+		testutil.HandleTestingErr(db.Clear(Collection), t, "Error"+
+			" clearing '%v' collection", Collection)
+
+		host := &Host{
+			Id:   "hostOne",
+			Host: "hostname",
+		}
+		So(host.Insert(), ShouldBeNil)
+
+		Convey("updating the hostname should overwrite both the in-memory"+
+			" and database copies of the host", func() {
+
+			So(host.UpdateDNSName("hostname2"), ShouldBeNil)
+			So(host.Host, ShouldEqual, "hostname2")
+
+			host, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(host.Host, ShouldEqual, "hostname2")
+
+		})
+
+	})
+}
+
 func TestMarkAsProvisioned(t *testing.T) {
 
 	Convey("With a host", t, func() {
@@ -490,6 +518,109 @@ func TestHostClearRunningTask(t *testing.T) {
 	})
 }
 
+func TestHostAgentContact(t *testing.T) {
+
+	Convey("With a host", t, func() {
+
+		testutil.HandleTestingErr(db.Clear(Collection), t, "Error"+
+			" clearing '%v' collection", Collection)
+
+		host := &Host{
+			Id:            "hostOne",
+			AgentRevision: "abc",
+		}
+		So(host.Insert(), ShouldBeNil)
+
+		Convey("a host whose agent has never made contact should not have a live agent", func() {
+			So(host.HasLiveAgent(time.Minute), ShouldBeFalse)
+		})
+
+		Convey("recording contact should update the revision and poll time of both"+
+			" the in-memory and database copies of the host", func() {
+
+			So(host.SetAgentContact("def", time.Now()), ShouldBeNil)
+			So(host.AgentRevision, ShouldEqual, "def")
+			So(host.HasLiveAgent(time.Minute), ShouldBeTrue)
+
+			host, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(host.AgentRevision, ShouldEqual, "def")
+			So(host.HasLiveAgent(time.Minute), ShouldBeTrue)
+		})
+
+		Convey("contact outside of the window should not count as a live agent", func() {
+			So(host.SetAgentContact("def", time.Now().Add(-time.Hour)), ShouldBeNil)
+			So(host.HasLiveAgent(time.Minute), ShouldBeFalse)
+		})
+
+		Convey("setting the secret should store a new secret in the database", func() {
+			So(host.SetSecret(), ShouldBeNil)
+			So(host.Secret, ShouldNotEqual, "")
+
+			dbHost, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(dbHost.Secret, ShouldEqual, host.Secret)
+		})
+
+	})
+}
+
+func TestHostConsecutiveFailures(t *testing.T) {
+
+	Convey("With a host", t, func() {
+
+		testutil.HandleTestingErr(db.Clear(Collection), t, "Error"+
+			" clearing '%v' collection", Collection)
+
+		host := &Host{
+			Id:     "hostOne",
+			Status: evergreen.HostRunning,
+		}
+		So(host.Insert(), ShouldBeNil)
+
+		Convey("failures should be counted in memory and in the database", func() {
+			failures, err := host.IncConsecutiveFailures()
+			So(err, ShouldBeNil)
+			So(failures, ShouldEqual, 1)
+			failures, err = host.IncConsecutiveFailures()
+			So(err, ShouldBeNil)
+			So(failures, ShouldEqual, 2)
+
+			dbHost, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(dbHost.ConsecutiveFailures, ShouldEqual, 2)
+
+			Convey("including failures counted through another copy of the host", func() {
+				failures, err := dbHost.IncConsecutiveFailures()
+				So(err, ShouldBeNil)
+				So(failures, ShouldEqual, 3)
+				failures, err = host.IncConsecutiveFailures()
+				So(err, ShouldBeNil)
+				So(failures, ShouldEqual, 4)
+			})
+
+			Convey("and resetting should clear them", func() {
+				So(host.ResetConsecutiveFailures(), ShouldBeNil)
+				So(host.ConsecutiveFailures, ShouldEqual, 0)
+
+				dbHost, err := FindOne(ById(host.Id))
+				So(err, ShouldBeNil)
+				So(dbHost.ConsecutiveFailures, ShouldEqual, 0)
+			})
+		})
+
+		Convey("quarantining the host should take it out of service", func() {
+			So(host.Quarantine("too many failures"), ShouldBeNil)
+			So(host.Status, ShouldEqual, evergreen.HostQuarantined)
+
+			dbHost, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(dbHost.Status, ShouldEqual, evergreen.HostQuarantined)
+		})
+
+	})
+}
+
 func TestUpsert(t *testing.T) {
 
 	Convey("With a host", t, func() {
@@ -2,7 +2,7 @@ package host
 
 import (
 	"github.com/evergreen-ci/evergreen"
-	"gopkg.in/mgo.v2"
+	"github.com/evergreen-ci/evergreen/db"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -13,21 +13,14 @@ func DecommissionInactiveStaticHosts(activeStaticHosts []string) error {
 	if activeStaticHosts == nil {
 		return nil
 	}
-	err := UpdateAll(
-		bson.M{
-			IdKey: bson.M{
-				"$nin": activeStaticHosts,
-			},
-			ProviderKey: evergreen.HostTypeStatic,
+	hosts, err := Find(db.Query(bson.M{
+		IdKey: bson.M{
+			"$nin": activeStaticHosts,
 		},
-		bson.M{
-			"$set": bson.M{
-				StatusKey: evergreen.HostDecommissioned,
-			},
-		},
-	)
-	if err == mgo.ErrNotFound {
-		return nil
+		ProviderKey: evergreen.HostTypeStatic,
+	}))
+	if err != nil {
+		return err
 	}
-	return err
+	return decommissionAll(hosts)
 }
@@ -0,0 +1,218 @@
+package host
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/tychoish/grip/slogger"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// statusTransitions maps each host status to the statuses a host may move to
+// from it. Every status may also be set again, except for terminated, which
+// is final. A host whose status has never been set may be given any status.
+var statusTransitions = map[string][]string{
+	evergreen.HostUninitialized: {
+		evergreen.HostInitializing,
+		evergreen.HostRunning,
+		evergreen.HostProvisionFailed,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostInitializing: {
+		evergreen.HostRunning,
+		evergreen.HostProvisionFailed,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostProvisionFailed: {
+		evergreen.HostUninitialized,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostRunning: {
+		evergreen.HostUnreachable,
+		evergreen.HostQuarantined,
+		evergreen.HostDecommissioned,
+		evergreen.HostStopped,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostUnreachable: {
+		evergreen.HostRunning,
+		evergreen.HostQuarantined,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostQuarantined: {
+		evergreen.HostRunning,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostDecommissioned: {
+		evergreen.HostRunning,
+		evergreen.HostQuarantined,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostStopped: {
+		evergreen.HostRunning,
+		evergreen.HostDecommissioned,
+		evergreen.HostTerminated,
+	},
+	evergreen.HostTerminated: {},
+}
+
+// StatusTransitionError is returned when a host can not move from its
+// current status to the one requested.
+type StatusTransitionError struct {
+	HostId string
+	From   string
+	To     string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("host %v can not go from status '%v' to '%v'", e.HostId, e.From, e.To)
+}
+
+// IsStatusTransitionError returns true if the error is a *StatusTransitionError.
+func IsStatusTransitionError(err error) bool {
+	_, ok := err.(*StatusTransitionError)
+	return ok
+}
+
+// CanTransition returns true if a host may move from one status to another.
+func CanTransition(from, to string) bool {
+	if from == "" {
+		return true
+	}
+	if from == to {
+		return from != evergreen.HostTerminated
+	}
+	return util.SliceContains(statusTransitions[from], to)
+}
+
+// statusesTransitioningTo returns the statuses a host may move to the given
+// status from.
+func statusesTransitioningTo(to string) []string {
+	from := []string{""}
+	for status := range statusTransitions {
+		if CanTransition(status, to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
+
+// setStatus moves the host to the given status along with any other updates,
+// provided that its current status in the database is one of the given ones,
+// or any status that may move to the new one if none are given. The change is
+// logged as a host event. Returns a *StatusTransitionError if the host's
+// status doesn't allow it.
+func (h *Host) setStatus(status string, from []string, set, unset bson.M) error {
+	if from == nil {
+		from = statusesTransitioningTo(status)
+	}
+	if set == nil {
+		set = bson.M{}
+	}
+	set[StatusKey] = status
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	// the status the update replaced is logged, since the host may have
+	// changed since it was read
+	old := &Host{}
+	_, err := db.FindAndModify(Collection, bson.M{IdKey: h.Id, StatusKey: bson.M{"$in": from}}, nil,
+		mgo.Change{Update: update}, old)
+	if err == mgo.ErrNotFound {
+		current, findErr := FindOne(ById(h.Id))
+		if findErr != nil {
+			return findErr
+		}
+		if current == nil {
+			return err
+		}
+		transitionErr := &StatusTransitionError{HostId: h.Id, From: current.Status, To: status}
+		evergreen.Logger.Logf(slogger.WARN, "Refusing to update status: %v", transitionErr)
+		return transitionErr
+	}
+	if err != nil {
+		return err
+	}
+
+	event.LogHostStatusChanged(h.Id, old.Status, status)
+	h.Status = status
+	return nil
+}
+
+// StatusPeriod is a span of time that a host spent in one status. A period
+// that hasn't ended has a zero End, and lasts until now.
+type StatusPeriod struct {
+	Status   string        `json:"status"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+}
+
+// StatusHistory returns the statuses the host has been in, in order, as
+// recorded by its status change events.
+func StatusHistory(hostId string, now time.Time) ([]StatusPeriod, error) {
+	events, err := event.Find(event.HostStatusEventsInOrder(hostId))
+	if err != nil {
+		return nil, fmt.Errorf("error finding status changes for host %v: %v", hostId, err)
+	}
+	return statusPeriods(events, now), nil
+}
+
+// statusPeriods builds a host's status history from its creation and status
+// change events, sorted by time. The host's first status is the one its
+// first status change moved it from, starting when it was created.
+func statusPeriods(events []event.Event, now time.Time) []StatusPeriod {
+	created := time.Time{}
+	for _, e := range events {
+		if e.EventType == event.EventHostCreated {
+			created = e.Timestamp
+		}
+	}
+
+	periods := []StatusPeriod{}
+	for _, e := range events {
+		data, ok := e.Data.Data.(*event.HostEventData)
+		if !ok || e.EventType != event.EventHostStatusChanged {
+			continue
+		}
+		if len(periods) == 0 && data.OldStatus != "" && !created.IsZero() {
+			periods = append(periods, StatusPeriod{Status: data.OldStatus, Start: created})
+		}
+		if len(periods) > 0 {
+			last := &periods[len(periods)-1]
+			last.End = e.Timestamp
+			last.Duration = last.End.Sub(last.Start)
+		}
+		periods = append(periods, StatusPeriod{Status: data.NewStatus, Start: e.Timestamp})
+	}
+
+	// the current status lasts until now, unless the host is gone
+	if len(periods) > 0 {
+		last := &periods[len(periods)-1]
+		if last.Status != evergreen.HostTerminated && now.After(last.Start) {
+			last.Duration = now.Sub(last.Start)
+		}
+	}
+	return periods
+}
+
+// TimeInStatuses totals how long a host spent in each status.
+func TimeInStatuses(periods []StatusPeriod) map[string]time.Duration {
+	totals := map[string]time.Duration{}
+	for _, p := range periods {
+		totals[p.Status] += p.Duration
+	}
+	return totals
+}
@@ -0,0 +1,179 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHostStatusTransitions(t *testing.T) {
+
+	Convey("The allowed host status transitions", t, func() {
+
+		Convey("should let a host go from running to unreachable and back", func() {
+			So(CanTransition(evergreen.HostRunning, evergreen.HostUnreachable), ShouldBeTrue)
+			So(CanTransition(evergreen.HostUnreachable, evergreen.HostRunning), ShouldBeTrue)
+		})
+
+		Convey("should let a status be set again", func() {
+			So(CanTransition(evergreen.HostRunning, evergreen.HostRunning), ShouldBeTrue)
+		})
+
+		Convey("should never let a terminated host change", func() {
+			So(CanTransition(evergreen.HostTerminated, evergreen.HostRunning), ShouldBeFalse)
+			So(CanTransition(evergreen.HostTerminated, evergreen.HostTerminated), ShouldBeFalse)
+		})
+
+		Convey("should not let a host skip provisioning into being stopped", func() {
+			So(CanTransition(evergreen.HostUninitialized, evergreen.HostStopped), ShouldBeFalse)
+		})
+
+	})
+
+	Convey("With a quarantined host", t, func() {
+
+		testutil.HandleTestingErr(db.ClearCollections(Collection, event.Collection), t,
+			"Error clearing collections")
+
+		host := &Host{
+			Id:     "hostOne",
+			Status: evergreen.HostQuarantined,
+		}
+		So(host.Insert(), ShouldBeNil)
+
+		Convey("an illegal transition should fail with a typed error and leave"+
+			" the host alone", func() {
+
+			err := host.SetStatus(evergreen.HostUnreachable)
+			So(IsStatusTransitionError(err), ShouldBeTrue)
+			transitionErr := err.(*StatusTransitionError)
+			So(transitionErr.From, ShouldEqual, evergreen.HostQuarantined)
+			So(transitionErr.To, ShouldEqual, evergreen.HostUnreachable)
+			So(host.Status, ShouldEqual, evergreen.HostQuarantined)
+
+			host, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(host.Status, ShouldEqual, evergreen.HostQuarantined)
+		})
+
+		Convey("the host's status in the database should be checked, even if"+
+			" the in-memory copy is stale", func() {
+
+			stale := *host
+			So(host.Terminate(), ShouldBeNil)
+			err := stale.SetStatus(evergreen.HostRunning)
+			So(IsStatusTransitionError(err), ShouldBeTrue)
+		})
+
+		Convey("a legal transition should log the change", func() {
+			So(host.SetStatus(evergreen.HostRunning), ShouldBeNil)
+
+			history, err := StatusHistory(host.Id, time.Now())
+			So(err, ShouldBeNil)
+			So(len(history), ShouldEqual, 2)
+			So(history[0].Status, ShouldEqual, evergreen.HostQuarantined)
+			So(history[1].Status, ShouldEqual, evergreen.HostRunning)
+		})
+
+		Convey("a change from a stale copy should log the status in the"+
+			" database", func() {
+
+			stale := *host
+			So(host.SetStatus(evergreen.HostRunning), ShouldBeNil)
+			So(stale.SetDecommissioned(), ShouldBeNil)
+
+			events, err := event.Find(event.HostStatusEventsInOrder(host.Id))
+			So(err, ShouldBeNil)
+			last := events[len(events)-1].Data.Data.(*event.HostEventData)
+			So(last.OldStatus, ShouldEqual, evergreen.HostRunning)
+			So(last.NewStatus, ShouldEqual, evergreen.HostDecommissioned)
+		})
+
+		Convey("a reachability check should leave the host quarantined", func() {
+			stale := *host
+			stale.Status = evergreen.HostUnreachable
+			So(stale.UpdateReachability(true), ShouldBeNil)
+
+			host, err := FindOne(ById(host.Id))
+			So(err, ShouldBeNil)
+			So(host.Status, ShouldEqual, evergreen.HostQuarantined)
+		})
+
+		Convey("decommissioning hosts should skip those that changed since"+
+			" they were found", func() {
+
+			other := &Host{Id: "hostTwo", Status: evergreen.HostRunning}
+			So(other.Insert(), ShouldBeNil)
+			hosts := []Host{*host, *other}
+			So(host.Terminate(), ShouldBeNil)
+
+			So(decommissionAll(hosts), ShouldBeNil)
+			other, err := FindOne(ById(other.Id))
+			So(err, ShouldBeNil)
+			So(other.Status, ShouldEqual, evergreen.HostDecommissioned)
+		})
+
+	})
+}
+
+func TestStatusPeriods(t *testing.T) {
+
+	Convey("With a host's creation and status change events", t, func() {
+
+		created := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+		statusChange := func(offset time.Duration, from, to string) event.Event {
+			return event.Event{
+				Timestamp: created.Add(offset),
+				EventType: event.EventHostStatusChanged,
+				Data: event.DataWrapper{Data: &event.HostEventData{
+					ResourceType: event.ResourceTypeHost,
+					OldStatus:    from,
+					NewStatus:    to,
+				}},
+			}
+		}
+		events := []event.Event{
+			{Timestamp: created, EventType: event.EventHostCreated},
+			statusChange(time.Minute, evergreen.HostUninitialized, evergreen.HostInitializing),
+			statusChange(3*time.Minute, evergreen.HostInitializing, evergreen.HostRunning),
+			statusChange(time.Hour, evergreen.HostRunning, evergreen.HostUnreachable),
+			statusChange(2*time.Hour, evergreen.HostUnreachable, evergreen.HostRunning),
+		}
+		now := created.Add(3 * time.Hour)
+
+		Convey("each status should last until the next change", func() {
+			periods := statusPeriods(events, now)
+			So(len(periods), ShouldEqual, 5)
+			So(periods[0].Status, ShouldEqual, evergreen.HostUninitialized)
+			So(periods[0].Duration, ShouldEqual, time.Minute)
+			So(periods[1].Duration, ShouldEqual, 2*time.Minute)
+			So(periods[2].Duration, ShouldEqual, 57*time.Minute)
+			So(periods[3].Duration, ShouldEqual, time.Hour)
+
+			Convey("and the current status should last until now", func() {
+				So(periods[4].End.IsZero(), ShouldBeTrue)
+				So(periods[4].Duration, ShouldEqual, time.Hour)
+			})
+
+			Convey("and the time in each status should add up", func() {
+				totals := TimeInStatuses(periods)
+				So(totals[evergreen.HostRunning], ShouldEqual, 57*time.Minute+time.Hour)
+				So(totals[evergreen.HostUnreachable], ShouldEqual, time.Hour)
+			})
+		})
+
+		Convey("a terminated host's last status should have no duration", func() {
+			events = append(events, statusChange(150*time.Minute, evergreen.HostRunning,
+				evergreen.HostTerminated))
+			periods := statusPeriods(events, now)
+			So(periods[len(periods)-1].Status, ShouldEqual, evergreen.HostTerminated)
+			So(periods[len(periods)-1].Duration, ShouldEqual, time.Duration(0))
+		})
+
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -215,6 +216,9 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 		if err := t.Archive(); err != nil {
 			return fmt.Errorf("failed to archive task: %v", err)
 		}
+		if err := annotations.CarryForward(t.Id, t.Execution, t.Execution+1); err != nil {
+			return fmt.Errorf("failed to carry forward annotations for task: %v", err)
+		}
 	}
 
 	// Set all the task fields to indicate restarted
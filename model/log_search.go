@@ -0,0 +1,225 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+const (
+	// sources a log search match can come from
+	LogSearchSourceTaskLog = "task_log"
+	LogSearchSourceTestLog = "test_log"
+
+	// DefaultLogSearchLimit caps the number of matches returned by a single search
+	DefaultLogSearchLimit = 1000
+	// MaxLogSearchLimit is the most matches a single search may ask for
+	MaxLogSearchLimit = 10000
+	// MaxLogSearchContext caps the number of context lines on either side of a match
+	MaxLogSearchContext = 20
+)
+
+// LogSearchOptions controls which logs are scanned and how matches are reported.
+type LogSearchOptions struct {
+	// Pattern is the regular expression each log line is matched against
+	Pattern *regexp.Regexp
+	// Context is the number of lines of surrounding context to include
+	// before and after each match
+	Context int
+	// Types restricts the task log message types searched (task/agent/system).
+	// An empty slice searches all types.
+	Types []string
+	// Severities restricts the task log severities searched. An empty slice
+	// searches all severities.
+	Severities []string
+	// IncludeTestLogs also searches the test logs attached to the task
+	IncludeTestLogs bool
+	// Limit caps the number of matches returned, defaults to DefaultLogSearchLimit
+	Limit int
+}
+
+// LogSearchMatch is a single line that matched a log search.
+type LogSearchMatch struct {
+	TaskId    string    `json:"task_id"`
+	Execution int       `json:"execution"`
+	Source    string    `json:"source"`
+	TestName  string    `json:"test_name,omitempty"`
+	LogType   string    `json:"log_type,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	LineNum   int       `json:"line_num"`
+	Line      string    `json:"line"`
+	Before    []string  `json:"before,omitempty"`
+	After     []string  `json:"after,omitempty"`
+}
+
+// searchableLine is a single line of a log, along with the metadata needed
+// to describe a match on it.
+type searchableLine struct {
+	text      string
+	logType   string
+	severity  string
+	timestamp time.Time
+}
+
+// ParseLogSearchPattern compiles a user-supplied search pattern, returning a
+// descriptive error if it is empty or invalid.
+func ParseLogSearchPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("search pattern cannot be empty")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern '%v': %v", pattern, err)
+	}
+	return re, nil
+}
+
+// LogTypeName returns the human readable name for a log message type prefix,
+// translating the older long-form names as well.
+func LogTypeName(msgType string) string {
+	switch msgType {
+	case TaskLogPrefix, "task":
+		return "task"
+	case AgentLogPrefix, "agent":
+		return "agent"
+	case SystemLogPrefix, "system":
+		return "system"
+	default:
+		return msgType
+	}
+}
+
+// SearchTaskLogs scans the task log messages (and, if requested, the test logs)
+// of a single task execution for lines matching the options' pattern.
+func SearchTaskLogs(taskId string, execution int, opts LogSearchOptions) ([]LogSearchMatch, error) {
+	if opts.Pattern == nil {
+		return nil, fmt.Errorf("no search pattern specified")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLogSearchLimit
+	}
+
+	channel, err := GetRawTaskLogChannel(taskId, execution, opts.Severities, opts.Types)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logs for task %v: %v", taskId, err)
+	}
+	lines := []searchableLine{}
+	for msg := range channel {
+		for _, text := range strings.Split(strings.TrimRight(msg.Message, "\n"), "\n") {
+			lines = append(lines, searchableLine{
+				text:      text,
+				logType:   LogTypeName(msg.Type),
+				severity:  msg.Severity,
+				timestamp: msg.Timestamp,
+			})
+		}
+	}
+
+	matches := searchLines(lines, opts.Pattern, opts.Context, limit)
+	for i := range matches {
+		matches[i].TaskId = taskId
+		matches[i].Execution = execution
+		matches[i].Source = LogSearchSourceTaskLog
+	}
+	if !opts.IncludeTestLogs || len(matches) >= limit {
+		return matches, nil
+	}
+
+	testLogs, err := FindAllTestLogs(taskId, execution)
+	if err != nil {
+		return nil, fmt.Errorf("error finding test logs for task %v: %v", taskId, err)
+	}
+	for _, testLog := range testLogs {
+		lines := make([]searchableLine, 0, len(testLog.Lines))
+		for _, text := range testLog.Lines {
+			lines = append(lines, searchableLine{text: text})
+		}
+		testMatches := searchLines(lines, opts.Pattern, opts.Context, limit-len(matches))
+		for i := range testMatches {
+			testMatches[i].TaskId = taskId
+			testMatches[i].Execution = execution
+			testMatches[i].Source = LogSearchSourceTestLog
+			testMatches[i].TestName = testLog.Name
+		}
+		matches = append(matches, testMatches...)
+		if len(matches) >= limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// SearchVersionLogs runs a log search across the latest execution of every
+// task in a version that has started running, returning up to opts.Limit
+// matches in total.
+func SearchVersionLogs(versionId string, opts LogSearchOptions) ([]LogSearchMatch, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLogSearchLimit
+	}
+	tasks, err := task.Find(task.ByVersion(versionId).
+		WithFields(task.IdKey, task.ExecutionKey, task.StartTimeKey).
+		Sort([]string{task.DisplayNameKey}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding tasks for version %v: %v", versionId, err)
+	}
+	matches := []LogSearchMatch{}
+	for _, t := range tasks {
+		if util.IsZeroTime(t.StartTime) {
+			continue
+		}
+		taskOpts := opts
+		taskOpts.Limit = opts.Limit - len(matches)
+		taskMatches, err := SearchTaskLogs(t.Id, t.Execution, taskOpts)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, taskMatches...)
+		if len(matches) >= opts.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// searchLines returns up to limit matches of the pattern in the given lines,
+// numbering lines from 1 and attaching up to context lines on either side.
+func searchLines(lines []searchableLine, pattern *regexp.Regexp, context, limit int) []LogSearchMatch {
+	if context < 0 {
+		context = 0
+	}
+	context = util.Min(context, MaxLogSearchContext)
+	matches := []LogSearchMatch{}
+	for idx, line := range lines {
+		if len(matches) >= limit {
+			break
+		}
+		if !pattern.MatchString(line.text) {
+			continue
+		}
+		match := LogSearchMatch{
+			LogType:   line.logType,
+			Severity:  line.severity,
+			Timestamp: line.timestamp,
+			LineNum:   idx + 1,
+			Line:      line.text,
+		}
+		start := idx - context
+		if start < 0 {
+			start = 0
+		}
+		for _, before := range lines[start:idx] {
+			match.Before = append(match.Before, before.text)
+		}
+		for _, after := range lines[idx+1 : util.Min(idx+1+context, len(lines))] {
+			match.After = append(match.After, after.text)
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchLines(t *testing.T) {
+	Convey("With a set of log lines", t, func() {
+		lines := []searchableLine{}
+		for _, text := range []string{"one", "two", "error: three", "four", "five", "error: six"} {
+			lines = append(lines, searchableLine{text: text, logType: "task", severity: LogInfoPrefix})
+		}
+		pattern := regexp.MustCompile("^error")
+
+		Convey("matches should be numbered from one and carry metadata", func() {
+			matches := searchLines(lines, pattern, 0, 10)
+			So(len(matches), ShouldEqual, 2)
+			So(matches[0].LineNum, ShouldEqual, 3)
+			So(matches[0].Line, ShouldEqual, "error: three")
+			So(matches[0].LogType, ShouldEqual, "task")
+			So(matches[0].Severity, ShouldEqual, LogInfoPrefix)
+			So(matches[0].Before, ShouldBeEmpty)
+			So(matches[0].After, ShouldBeEmpty)
+			So(matches[1].LineNum, ShouldEqual, 6)
+		})
+
+		Convey("context should be clipped at the edges of the log", func() {
+			matches := searchLines(lines, pattern, 2, 10)
+			So(len(matches), ShouldEqual, 2)
+			So(matches[0].Before, ShouldResemble, []string{"one", "two"})
+			So(matches[0].After, ShouldResemble, []string{"four", "five"})
+			So(matches[1].Before, ShouldResemble, []string{"four", "five"})
+			So(matches[1].After, ShouldBeEmpty)
+		})
+
+		Convey("no more than the limit should be returned", func() {
+			matches := searchLines(lines, pattern, 0, 1)
+			So(len(matches), ShouldEqual, 1)
+			So(matches[0].LineNum, ShouldEqual, 3)
+		})
+
+		Convey("negative context should be treated as none", func() {
+			matches := searchLines(lines, pattern, -5, 10)
+			So(matches[0].Before, ShouldBeEmpty)
+			So(matches[0].After, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestParseLogSearchPattern(t *testing.T) {
+	Convey("When parsing a search pattern", t, func() {
+		Convey("an empty pattern should be rejected", func() {
+			_, err := ParseLogSearchPattern("")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("an invalid pattern should be rejected", func() {
+			_, err := ParseLogSearchPattern("a(b")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "a(b")
+		})
+		Convey("a valid pattern should compile", func() {
+			re, err := ParseLogSearchPattern("fail(ed|ure)")
+			So(err, ShouldBeNil)
+			So(re.MatchString("test failure"), ShouldBeTrue)
+		})
+	})
+}
+
+func TestSearchTaskLogs(t *testing.T) {
+	Convey("With task and test logs in the database", t, func() {
+		testutil.HandleTestingErr(cleanUpLogDB(), t, "Error cleaning up task log database")
+		testutil.HandleTestingErr(db.Clear(TestLogCollection), t, "Error clearing test logs")
+
+		msgs := []LogMessage{
+			{Type: TaskLogPrefix, Severity: LogInfoPrefix, Message: "starting\nsegfault in task"},
+			{Type: AgentLogPrefix, Severity: LogErrorPrefix, Message: "agent saw segfault"},
+			{Type: SystemLogPrefix, Severity: LogInfoPrefix, Message: "system ok"},
+		}
+		for i, msg := range msgs {
+			msg.Timestamp = time.Now().Add(time.Duration(i) * time.Second)
+			So(msg.Insert("search_task", 0), ShouldBeNil)
+		}
+		testLog := &TestLog{
+			Name:          "test1",
+			Task:          "search_task",
+			TaskExecution: 0,
+			Lines:         []string{"setup", "segfault in test", "teardown"},
+		}
+		So(testLog.Insert(), ShouldBeNil)
+
+		Convey("all log types and test logs should be searched", func() {
+			matches, err := SearchTaskLogs("search_task", 0, LogSearchOptions{
+				Pattern:         regexp.MustCompile("segfault"),
+				Context:         1,
+				IncludeTestLogs: true,
+			})
+			So(err, ShouldBeNil)
+			So(len(matches), ShouldEqual, 3)
+
+			So(matches[0].Source, ShouldEqual, LogSearchSourceTaskLog)
+			So(matches[0].LogType, ShouldEqual, "task")
+			So(matches[0].LineNum, ShouldEqual, 2)
+			So(matches[0].Before, ShouldResemble, []string{"starting"})
+
+			So(matches[1].LogType, ShouldEqual, "agent")
+			So(matches[1].Severity, ShouldEqual, LogErrorPrefix)
+
+			So(matches[2].Source, ShouldEqual, LogSearchSourceTestLog)
+			So(matches[2].TestName, ShouldEqual, "test1")
+			So(matches[2].LineNum, ShouldEqual, 2)
+			So(matches[2].After, ShouldResemble, []string{"teardown"})
+		})
+
+		Convey("type filters should exclude other log types", func() {
+			matches, err := SearchTaskLogs("search_task", 0, LogSearchOptions{
+				Pattern: regexp.MustCompile("segfault"),
+				Types:   []string{TaskLogPrefix},
+			})
+			So(err, ShouldBeNil)
+			So(len(matches), ShouldEqual, 1)
+			So(matches[0].LogType, ShouldEqual, "task")
+		})
+	})
+}
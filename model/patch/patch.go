@@ -222,10 +222,17 @@ func (p *Patch) Insert() error {
 // passed in remotePath is in the the name of the changed files that are part
 // of the patch
 func (p *Patch) ConfigChanged(remotePath string) bool {
+	return p.FileChanged("", remotePath)
+}
+
+// FileChanged returns true if the patch changes the file at the given path
+// in the module, where the module name is "" for the project's own
+// repository.
+func (p *Patch) FileChanged(moduleName, path string) bool {
 	for _, patchPart := range p.Patches {
-		if patchPart.ModuleName == "" {
+		if patchPart.ModuleName == moduleName {
 			for _, summary := range patchPart.PatchSet.Summary {
-				if summary.Name == remotePath {
+				if summary.Name == path {
 					return true
 				}
 			}
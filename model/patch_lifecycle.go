@@ -173,12 +173,62 @@ func IncludePatchDependencies(project *Project, tvpairs []TVPair) []TVPair {
 
 // MakePatchedConfig takes in the path to a remote configuration a stringified version
 // of the current project and returns an unmarshalled version of the project
-// with the patch applied
-func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
-	*Project, error) {
+// with the patch applied. Files the configuration includes are read with
+// fetch, and patched too if the patch changes them.
+func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string,
+	fetch IncludeFetcher) (*Project, error) {
+	data, err := patchConfigFile(p, "", remoteConfigPath, projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	project := &Project{}
+	if err = LoadProjectWithIncludes(data, p.Project, project, PatchedIncludeFetcher(p, fetch)); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// PatchedIncludeFetcher returns an IncludeFetcher that reads files with fetch
+// and applies the patch's changes to them. Files in a module the patch
+// includes are read at the revision the module's changes were made against,
+// rather than at the head of the module's branch.
+func PatchedIncludeFetcher(p *patch.Patch, fetch IncludeFetcher) IncludeFetcher {
+	if fetch == nil {
+		return nil
+	}
+	return func(module *Module, path string) ([]byte, error) {
+		moduleName := ""
+		if module != nil {
+			moduleName = module.Name
+			for _, modulePatch := range p.Patches {
+				if modulePatch.ModuleName == moduleName && modulePatch.Githash != "" {
+					atRevision := *module
+					atRevision.Branch = modulePatch.Githash
+					module = &atRevision
+					break
+				}
+			}
+		}
+		data, err := fetch(module, path)
+		if !p.FileChanged(moduleName, path) {
+			return data, err
+		}
+		// the patch may add the file
+		if err != nil && !thirdparty.IsFileNotFound(err) {
+			return nil, err
+		}
+		return patchConfigFile(p, moduleName, path, string(data))
+	}
+}
+
+// patchConfigFile applies the patch's changes to a file in the module, where
+// the module name is "" for the project's own repository, and returns the
+// patched file.
+func patchConfigFile(p *patch.Patch, moduleName, remoteConfigPath, projectConfig string) (
+	[]byte, error) {
 	for _, patchPart := range p.Patches {
-		// we only need to patch the main project and not any other modules
-		if patchPart.ModuleName != "" {
+		// only the part for the file's repository applies
+		if patchPart.ModuleName != moduleName {
 			continue
 		}
 		// write patch file
@@ -246,11 +296,10 @@ func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
 			return nil, fmt.Errorf("could not read patched config file: %v",
 				err)
 		}
-		project := &Project{}
-		if err = LoadProjectInto(data, p.Project, project); err != nil {
-			return nil, err
-		}
-		return project, nil
+		return data, nil
+	}
+	if moduleName != "" {
+		return nil, fmt.Errorf("no patch on module '%v'", moduleName)
 	}
 	return nil, fmt.Errorf("no patch on project")
 }
@@ -39,7 +39,7 @@ func TestMakePatchedConfig(t *testing.T) {
 			}
 			projectBytes, err := ioutil.ReadFile(filepath.Join(cwd, "testdata", "project.config"))
 			So(err, ShouldBeNil)
-			project, err := MakePatchedConfig(p, remoteConfigPath, string(projectBytes))
+			project, err := MakePatchedConfig(p, remoteConfigPath, string(projectBytes), nil)
 			So(err, ShouldBeNil)
 			So(project, ShouldNotBeNil)
 			So(len(project.Tasks), ShouldEqual, 2)
@@ -58,7 +58,7 @@ func TestMakePatchedConfig(t *testing.T) {
 				}},
 			}
 
-			project, err := MakePatchedConfig(p, remoteConfigPath, "")
+			project, err := MakePatchedConfig(p, remoteConfigPath, "", nil)
 			So(err, ShouldBeNil)
 			So(project, ShouldNotBeNil)
 			So(len(project.Tasks), ShouldEqual, 1)
@@ -71,6 +71,40 @@ func TestMakePatchedConfig(t *testing.T) {
 	})
 }
 
+func TestPatchedIncludeFetcher(t *testing.T) {
+	Convey("With a patch that includes changes to a module", t, func() {
+		p := &patch.Patch{
+			Patches: []patch.ModulePatch{
+				{Githash: "revision"},
+				{ModuleName: "tools", Githash: "module_revision"},
+			},
+		}
+		refs := []string{}
+		fetch := PatchedIncludeFetcher(p, func(module *Module, path string) ([]byte, error) {
+			ref := ""
+			if module != nil {
+				ref = module.Name + "@" + module.Branch
+			}
+			refs = append(refs, ref)
+			return []byte{}, nil
+		})
+
+		Convey("the module's files should be read at the revision its changes are against", func() {
+			module := &Module{Name: "tools", Branch: "master"}
+			_, err := fetch(module, "functions.yml")
+			So(err, ShouldBeNil)
+			So(refs, ShouldResemble, []string{"tools@module_revision"})
+			So(module.Branch, ShouldEqual, "master")
+		})
+
+		Convey("other modules' files should be read at their branch", func() {
+			_, err := fetch(&Module{Name: "other", Branch: "master"}, "functions.yml")
+			So(err, ShouldBeNil)
+			So(refs, ShouldResemble, []string{"other@master"})
+		})
+	})
+}
+
 // shouldContainPair returns a blank string if its arguments resemble each other, and returns a
 // list of pretty-printed diffs between the objects if they do not match.
 func shouldContainPair(actual interface{}, expected ...interface{}) string {
@@ -2,7 +2,9 @@ package model
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/command"
@@ -189,6 +191,46 @@ type PluginCommandConf struct {
 
 	// Vars defines variables that can be used within commands.
 	Vars map[string]string `yaml:"vars,omitempty" bson:"vars"`
+
+	// Condition, if set, is checked when the command is about to run, and
+	// the command is skipped unless it holds.
+	Condition *CommandCondition `yaml:"if,omitempty" bson:"if,omitempty"`
+
+	// Retry, if set, reruns the command when it fails. On a function call,
+	// it applies to each of the function's commands that has none of its own.
+	Retry *CommandRetry `yaml:"retry,omitempty" bson:"retry,omitempty"`
+
+	// ContinueOnError lets the commands after this one run even if it fails.
+	ContinueOnError bool `yaml:"continue_on_err,omitempty" bson:"continue_on_err,omitempty"`
+
+	// line is where the command is defined in the project file, if known.
+	line int
+	// file is the included file the command is defined in, if it isn't
+	// defined in the main project file.
+	file string
+}
+
+// CommandRetry describes how a failed command is rerun.
+type CommandRetry struct {
+	// Attempts is the most times the command is run, including the first.
+	Attempts int `yaml:"attempts" bson:"attempts"`
+
+	// BackoffSecs is how long to wait before the first rerun. Each later
+	// rerun waits that much longer than the one before it.
+	BackoffSecs int `yaml:"backoff,omitempty" bson:"backoff,omitempty"`
+}
+
+// MaxCommandAttempts is the most times a command can be run.
+const MaxCommandAttempts = 10
+
+// DefaultCommandTimeoutSecs is how long a command may run without output
+// before it's timed out, if it doesn't set timeout_secs.
+const DefaultCommandTimeoutSecs = 2 * 60 * 60
+
+// Backoff returns how long to wait after the given failed attempt, counting
+// from 1, before the next one.
+func (r *CommandRetry) Backoff(attempt int) time.Duration {
+	return time.Duration(r.BackoffSecs*attempt) * time.Second
 }
 
 type ArtifactInstructions struct {
@@ -199,6 +241,31 @@ type ArtifactInstructions struct {
 type YAMLCommandSet struct {
 	SingleCommand *PluginCommandConf
 	MultiCommand  []PluginCommandConf
+
+	// Parameters declares the arguments a function takes. Calls to functions
+	// that declare none aren't checked.
+	Parameters []FunctionParameter `bson:"parameters,omitempty"`
+
+	// line is where the command set is defined in the project file, if known.
+	line int
+	// file is the included file the command set is defined in, if it isn't
+	// defined in the main project file.
+	file string
+}
+
+// FunctionParameter declares an argument of a function, which calls pass in
+// their vars.
+type FunctionParameter struct {
+	Name        string `yaml:"name" bson:"name"`
+	Required    bool   `yaml:"required,omitempty" bson:"required,omitempty"`
+	Default     string `yaml:"default,omitempty" bson:"default,omitempty"`
+	Description string `yaml:"description,omitempty" bson:"description,omitempty"`
+}
+
+// functionDefinition is the form of a function that declares its parameters.
+type functionDefinition struct {
+	Parameters []FunctionParameter `yaml:"parameters"`
+	Commands   []PluginCommandConf `yaml:"commands"`
 }
 
 func (c *YAMLCommandSet) List() []PluginCommandConf {
@@ -215,10 +282,33 @@ func (c *YAMLCommandSet) MarshalYAML() (interface{}, error) {
 	if c == nil {
 		return nil, nil
 	}
+	if len(c.Parameters) > 0 {
+		return functionDefinition{Parameters: c.Parameters, Commands: c.List()}, nil
+	}
 	return c.List(), nil
 }
 
 func (c *YAMLCommandSet) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	c.line = yamlLine(unmarshal)
+
+	// a function that declares its parameters lists its commands separately
+	def := functionDefinition{}
+	if err := unmarshal(&def); len(def.Commands) > 0 {
+		if err != nil {
+			return err
+		}
+		c.Parameters = def.Parameters
+		c.MultiCommand = def.Commands
+		return nil
+	}
+	// otherwise the parameters would be dropped, leaving calls unchecked
+	fields := map[string]interface{}{}
+	if err := unmarshal(&fields); err == nil {
+		if _, ok := fields["parameters"]; ok {
+			return fmt.Errorf("%vfunctions that declare parameters must list their "+
+				"commands under 'commands'", linePrefix("", c.line))
+		}
+	}
 	err1 := unmarshal(&(c.MultiCommand))
 	err2 := unmarshal(&(c.SingleCommand))
 	if err1 == nil || err2 == nil {
@@ -227,6 +317,58 @@ func (c *YAMLCommandSet) UnmarshalYAML(unmarshal func(interface{}) error) error
 	return err1
 }
 
+// UnmarshalYAML reads a command, recording where it's defined.
+func (p *PluginCommandConf) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plainConf PluginCommandConf
+	if err := unmarshal((*plainConf)(p)); err != nil {
+		return err
+	}
+	p.line = yamlLine(unmarshal)
+	return nil
+}
+
+// CheckArgs returns an error for each required parameter of the function
+// that the call's vars are missing, and for each var that isn't a parameter.
+func (c *YAMLCommandSet) CheckArgs(vars map[string]string) []error {
+	if len(c.Parameters) == 0 {
+		return nil
+	}
+	errs := []error{}
+	declared := map[string]bool{}
+	for _, param := range c.Parameters {
+		declared[param.Name] = true
+		if _, ok := vars[param.Name]; !ok && param.Required {
+			errs = append(errs, fmt.Errorf("missing required argument '%v'", param.Name))
+		}
+	}
+	names := []string{}
+	for name := range vars {
+		if !declared[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("unknown argument '%v'", name))
+	}
+	return errs
+}
+
+// ArgsWithDefaults returns the call's vars along with the defaults of the
+// function's parameters that the call doesn't set.
+func (c *YAMLCommandSet) ArgsWithDefaults(vars map[string]string) map[string]string {
+	args := map[string]string{}
+	for _, param := range c.Parameters {
+		if param.Default != "" {
+			args[param.Name] = param.Default
+		}
+	}
+	for name, value := range vars {
+		args[name] = value
+	}
+	return args
+}
+
 // TaskDependency holds configuration information about a task that must finish before
 // the task that contains the dependency can run.
 type TaskDependency struct {
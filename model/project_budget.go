@@ -0,0 +1,183 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Periods that a project's budgets cover
+const (
+	BudgetDaily   = "daily"
+	BudgetMonthly = "monthly"
+)
+
+// ProjectBudget limits how much a project's tasks may cost over a period.
+// Crossing the soft limit fires an alert; crossing the hard limit holds the
+// project's patch tasks and puts its mainline tasks at the back of the task
+// queues until the period rolls over. A limit of zero is no limit.
+type ProjectBudget struct {
+	SoftLimit float64 `bson:"soft_limit" json:"soft_limit"`
+	HardLimit float64 `bson:"hard_limit" json:"hard_limit"`
+}
+
+// IsSet returns true if the budget has any limit.
+func (b ProjectBudget) IsSet() bool {
+	return b.SoftLimit > 0 || b.HardLimit > 0
+}
+
+// BudgetStatus is how much of one of its budgets a project has spent in the
+// current period.
+type BudgetStatus struct {
+	ProjectId     string    `json:"project_id"`
+	Period        string    `json:"period"`
+	PeriodStart   time.Time `json:"period_start"`
+	PeriodEnd     time.Time `json:"period_end"`
+	SoftLimit     float64   `json:"soft_limit"`
+	HardLimit     float64   `json:"hard_limit"`
+	Spent         float64   `json:"spent"`
+	OverSoftLimit bool      `json:"over_soft_limit"`
+	OverHardLimit bool      `json:"over_hard_limit"`
+	// Overridden is set if the project is over the hard limit but an admin
+	// has lifted it, in which case OverHardLimit is false
+	Overridden bool `json:"overridden"`
+}
+
+// PeriodKey identifies the budget's period, e.g. "daily-2017-03-01".
+func (s BudgetStatus) PeriodKey() string {
+	return fmt.Sprintf("%v-%v", s.Period, s.PeriodStart.Format(CostReportDayFormat))
+}
+
+// BudgetPeriod returns the start and end of the UTC day or month that
+// contains t.
+func BudgetPeriod(period string, t time.Time) (time.Time, time.Time, error) {
+	t = t.UTC()
+	switch period {
+	case BudgetDaily:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1), nil
+	case BudgetMonthly:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid budget period '%v'", period)
+	}
+}
+
+// ProjectCost returns the total cost of the project's tasks that finished
+// between start and end.
+func ProjectCost(projectId string, start, end time.Time) (float64, error) {
+	q := task.ByProjectFinishedBetween(projectId, start, end).WithFields(task.CostKey)
+	tasks, err := task.Find(q)
+	if err != nil {
+		return 0, fmt.Errorf("error finding tasks: %v", err)
+	}
+	oldTasks, err := task.FindOld(q)
+	if err != nil {
+		return 0, fmt.Errorf("error finding old tasks: %v", err)
+	}
+	cost := 0.0
+	for _, t := range append(tasks, oldTasks...) {
+		cost += t.Cost
+	}
+	return cost, nil
+}
+
+// Budgets returns the project's budgets that have a limit, by period.
+func (p *ProjectRef) Budgets() map[string]ProjectBudget {
+	budgets := map[string]ProjectBudget{}
+	if p.DailyBudget.IsSet() {
+		budgets[BudgetDaily] = p.DailyBudget
+	}
+	if p.MonthlyBudget.IsSet() {
+		budgets[BudgetMonthly] = p.MonthlyBudget
+	}
+	return budgets
+}
+
+// BudgetStatuses returns how much of each of its budgets the project has
+// spent in the periods containing now.
+func (p *ProjectRef) BudgetStatuses(now time.Time) ([]BudgetStatus, error) {
+	statuses := []BudgetStatus{}
+	for _, period := range []string{BudgetDaily, BudgetMonthly} {
+		budget, ok := p.Budgets()[period]
+		if !ok {
+			continue
+		}
+		start, end, err := BudgetPeriod(period, now)
+		if err != nil {
+			return nil, err
+		}
+		spent, err := ProjectCost(p.Identifier, start, end)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, budgetStatus(p, period, budget, start, end, spent, now))
+	}
+	return statuses, nil
+}
+
+// budgetStatus compares what the project has spent in a period to the
+// period's budget.
+func budgetStatus(p *ProjectRef, period string, budget ProjectBudget, start, end time.Time,
+	spent float64, now time.Time) BudgetStatus {
+	status := BudgetStatus{
+		ProjectId:     p.Identifier,
+		Period:        period,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		SoftLimit:     budget.SoftLimit,
+		HardLimit:     budget.HardLimit,
+		Spent:         spent,
+		OverSoftLimit: budget.SoftLimit > 0 && spent >= budget.SoftLimit,
+		OverHardLimit: budget.HardLimit > 0 && spent >= budget.HardLimit,
+	}
+	if status.OverHardLimit && now.Before(p.BudgetOverrideUntil) {
+		status.OverHardLimit = false
+		status.Overridden = true
+	}
+	return status
+}
+
+// OverBudget returns true if the project is over the hard limit of any of
+// its budgets, and hasn't been overridden.
+func OverBudget(statuses []BudgetStatus) bool {
+	for _, s := range statuses {
+		if s.OverHardLimit {
+			return true
+		}
+	}
+	return false
+}
+
+// OverrideBudget lifts the hard limits of the budgets that the project is
+// over until the end of their periods.
+func (p *ProjectRef) OverrideBudget(now time.Time) error {
+	statuses, err := p.BudgetStatuses(now)
+	if err != nil {
+		return err
+	}
+	until := time.Time{}
+	for _, s := range statuses {
+		if (s.OverHardLimit || s.Overridden) && s.PeriodEnd.After(until) {
+			until = s.PeriodEnd
+		}
+	}
+	if until.IsZero() {
+		return fmt.Errorf("project %v is not over budget", p.Identifier)
+	}
+
+	err = db.Update(
+		ProjectRefCollection,
+		bson.M{ProjectRefIdentifierKey: p.Identifier},
+		bson.M{"$set": bson.M{ProjectRefBudgetOverrideKey: until}},
+	)
+	if err != nil {
+		return err
+	}
+	p.BudgetOverrideUntil = until
+	return nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBudgetPeriod(t *testing.T) {
+	Convey("With a time in the middle of a month", t, func() {
+		now := time.Date(2017, 3, 15, 13, 30, 0, 0, time.UTC)
+
+		Convey("the daily period should be the UTC day", func() {
+			start, end, err := BudgetPeriod(BudgetDaily, now)
+			So(err, ShouldBeNil)
+			So(start, ShouldResemble, time.Date(2017, 3, 15, 0, 0, 0, 0, time.UTC))
+			So(end, ShouldResemble, time.Date(2017, 3, 16, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("the monthly period should be the UTC month", func() {
+			start, end, err := BudgetPeriod(BudgetMonthly, now)
+			So(err, ShouldBeNil)
+			So(start, ShouldResemble, time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC))
+			So(end, ShouldResemble, time.Date(2017, 4, 1, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("an unknown period should be an error", func() {
+			_, _, err := BudgetPeriod("weekly", now)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestBudgetStatuses(t *testing.T) {
+	Convey("With a project with budgets and tasks that cost money", t, func() {
+		testutil.HandleTestingErr(db.ClearCollections(task.Collection, task.OldCollection, ProjectRefCollection),
+			t, "error clearing collections")
+		now := time.Date(2017, 3, 15, 12, 0, 0, 0, time.UTC)
+		projectRef := &ProjectRef{
+			Identifier:    "p1",
+			DailyBudget:   ProjectBudget{SoftLimit: 5, HardLimit: 10},
+			MonthlyBudget: ProjectBudget{SoftLimit: 100},
+		}
+		So(projectRef.Insert(), ShouldBeNil)
+
+		tasks := []task.Task{
+			{Id: "today", Project: "p1", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-time.Hour), Cost: 6},
+			{Id: "earlier", Project: "p1", Status: evergreen.TaskFailed, FinishTime: now.AddDate(0, 0, -3), Cost: 20},
+			{Id: "running", Project: "p1", Status: evergreen.TaskStarted, Cost: 50},
+			{Id: "other", Project: "p2", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-time.Hour), Cost: 100},
+		}
+		for _, tsk := range tasks {
+			So(tsk.Insert(), ShouldBeNil)
+		}
+
+		Convey("spending should be summed over each period", func() {
+			statuses, err := projectRef.BudgetStatuses(now)
+			So(err, ShouldBeNil)
+			So(len(statuses), ShouldEqual, 2)
+			So(statuses[0].Period, ShouldEqual, BudgetDaily)
+			So(statuses[0].Spent, ShouldEqual, 6)
+			So(statuses[0].OverSoftLimit, ShouldBeTrue)
+			So(statuses[0].OverHardLimit, ShouldBeFalse)
+			So(statuses[1].Period, ShouldEqual, BudgetMonthly)
+			So(statuses[1].Spent, ShouldEqual, 26)
+			So(statuses[1].OverSoftLimit, ShouldBeFalse)
+			So(OverBudget(statuses), ShouldBeFalse)
+		})
+
+		Convey("a project past a hard limit should be over budget until overridden", func() {
+			more := task.Task{Id: "more", Project: "p1", Status: evergreen.TaskSucceeded,
+				FinishTime: now.Add(-time.Minute), Cost: 5}
+			So(more.Insert(), ShouldBeNil)
+			statuses, err := projectRef.BudgetStatuses(now)
+			So(err, ShouldBeNil)
+			So(statuses[0].OverHardLimit, ShouldBeTrue)
+			So(OverBudget(statuses), ShouldBeTrue)
+
+			So(projectRef.OverrideBudget(now), ShouldBeNil)
+			So(projectRef.BudgetOverrideUntil, ShouldResemble, time.Date(2017, 3, 16, 0, 0, 0, 0, time.UTC))
+			dbRef, err := FindOneProjectRef("p1")
+			So(err, ShouldBeNil)
+			So(dbRef.BudgetOverrideUntil.Equal(projectRef.BudgetOverrideUntil), ShouldBeTrue)
+
+			statuses, err = dbRef.BudgetStatuses(now)
+			So(err, ShouldBeNil)
+			So(statuses[0].Overridden, ShouldBeTrue)
+			So(OverBudget(statuses), ShouldBeFalse)
+
+			Convey("and over budget again once the period rolls over", func() {
+				tomorrow := now.AddDate(0, 0, 1)
+				late := task.Task{Id: "late", Project: "p1", Status: evergreen.TaskSucceeded,
+					FinishTime: tomorrow.Add(-time.Minute), Cost: 11}
+				So(late.Insert(), ShouldBeNil)
+				statuses, err = dbRef.BudgetStatuses(tomorrow)
+				So(err, ShouldBeNil)
+				So(OverBudget(statuses), ShouldBeTrue)
+			})
+		})
+
+		Convey("overriding a project under budget should be an error", func() {
+			So(projectRef.OverrideBudget(now), ShouldNotBeNil)
+		})
+	})
+}
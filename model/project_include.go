@@ -0,0 +1,304 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"gopkg.in/yaml.v2"
+)
+
+// A project file can split its configuration across several files with
+//   include:
+//     - evergreen/tasks.yml
+//     - "tools:evergreen/functions.yml"
+// Each entry is a path relative to the root of the project's repository, or
+// "module:path" for a file in one of the modules that the main file declares.
+// Included files may only define tasks, variants, functions, axes, modules,
+// and the pre, post and timeout sections; these are merged into the main file
+// before it is evaluated, and nothing may be defined twice.
+
+// mainProjectFile names the file that does the including, in errors.
+const mainProjectFile = "the main project file"
+
+// includableSections are the top-level keys an included file may set.
+var includableSections = map[string]bool{
+	"tasks":         true,
+	"buildvariants": true,
+	"functions":     true,
+	"axes":          true,
+	"modules":       true,
+	"pre":           true,
+	"post":          true,
+	"timeout":       true,
+}
+
+// IncludeFetcher returns the contents of a file included by a project
+// configuration. The module is nil for files in the project's own repository.
+type IncludeFetcher func(module *Module, path string) ([]byte, error)
+
+// IncludeFetchError is returned when an included file can't be read. Err is
+// the fetcher's error, kept with its type so that failures to reach where
+// the file is kept can be told apart from problems with the project file.
+type IncludeFetchError struct {
+	Include string
+	Err     error
+}
+
+func (e IncludeFetchError) Error() string {
+	return fmt.Sprintf("error reading included file '%v': %v", e.Include, e.Err)
+}
+
+// parserInclude holds the parts of an included file that are merged into the
+// project.
+type parserInclude struct {
+	Pre           *YAMLCommandSet            `yaml:"pre"`
+	Post          *YAMLCommandSet            `yaml:"post"`
+	Timeout       *YAMLCommandSet            `yaml:"timeout"`
+	Modules       []Module                   `yaml:"modules"`
+	BuildVariants []parserBV                 `yaml:"buildvariants"`
+	Functions     map[string]*YAMLCommandSet `yaml:"functions"`
+	Tasks         []parserTask               `yaml:"tasks"`
+	Axes          []matrixAxis               `yaml:"axes"`
+}
+
+// MergeIncludes returns the project file with the files it includes, read
+// with fetch, merged in, for passing it somewhere that can't read them.
+// Files without includes are returned as they are.
+func MergeIncludes(data []byte, fetch IncludeFetcher) ([]byte, error) {
+	pp, errs := createIntermediateProject(data)
+	if len(errs) > 0 || len(pp.Include) == 0 {
+		// leave any errors for whoever reads the file
+		return data, nil
+	}
+	project := &Project{}
+	if err := LoadProjectWithIncludes(data, "", project, fetch); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(project)
+}
+
+// mergeIncludes reads the files the project includes and merges them into
+// it. Errors name the file they came from. A file that can't be read stops
+// the merge, and is returned on its own as an IncludeFetchError.
+func mergeIncludes(pp *parserProject, fetch IncludeFetcher) ([]error, error) {
+	if len(pp.Include) == 0 {
+		return nil, nil
+	}
+	if fetch == nil {
+		return []error{fmt.Errorf("project includes %v, but included files can't be read here",
+			strings.Join(pp.Include, ", "))}, nil
+	}
+
+	// where each definition came from, to name both files in errors
+	origins := map[string]string{}
+	define := func(kind, name, file string) error {
+		key := kind + " '" + name + "'"
+		if origin, ok := origins[key]; ok {
+			return fmt.Errorf("%v: %v is already defined in %v", file, key, origin)
+		}
+		origins[key] = file
+		return nil
+	}
+	errs := []error{}
+	addDefinitions(pp.Tasks, pp.BuildVariants, pp.Functions, pp.Axes, pp.Modules, mainProjectFile, define)
+	for name, section := range map[string]*YAMLCommandSet{"pre": pp.Pre, "post": pp.Post, "timeout": pp.Timeout} {
+		if section != nil {
+			origins["section '"+name+"'"] = mainProjectFile
+		}
+	}
+
+	// modules are looked up in the main file, so the order of the includes
+	// doesn't matter
+	modules := map[string]*Module{}
+	for i := range pp.Modules {
+		modules[pp.Modules[i].Name] = &pp.Modules[i]
+	}
+
+	seen := map[string]bool{}
+	for _, include := range pp.Include {
+		file := fmt.Sprintf("included file '%v'", include)
+		if seen[include] {
+			errs = append(errs, fmt.Errorf("%v is included more than once", file))
+			continue
+		}
+		seen[include] = true
+
+		var module *Module
+		path := include
+		if i := strings.Index(include, ":"); i >= 0 {
+			module = modules[include[:i]]
+			if module == nil {
+				errs = append(errs, fmt.Errorf("%v: module '%v' is not defined in %v",
+					file, include[:i], mainProjectFile))
+				continue
+			}
+			path = include[i+1:]
+		}
+		path = strings.TrimPrefix(path, "/")
+		if path == "" {
+			errs = append(errs, fmt.Errorf("%v: missing path", file))
+			continue
+		}
+
+		data, err := fetch(module, path)
+		if err != nil {
+			return nil, IncludeFetchError{Include: include, Err: err}
+		}
+		pi, err := parseInclude(data, file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %v", file, err))
+			continue
+		}
+
+		definitionErrs := addDefinitions(pi.Tasks, pi.BuildVariants, pi.Functions, pi.Axes, pi.Modules, file, define)
+		for _, section := range []struct {
+			name    string
+			from    *YAMLCommandSet
+			project **YAMLCommandSet
+		}{{"pre", pi.Pre, &pp.Pre}, {"post", pi.Post, &pp.Post}, {"timeout", pi.Timeout, &pp.Timeout}} {
+			if section.from == nil {
+				continue
+			}
+			if err := define("section", section.name, file); err != nil {
+				definitionErrs = append(definitionErrs, err)
+				continue
+			}
+			*section.project = section.from
+		}
+		if len(definitionErrs) > 0 {
+			errs = append(errs, definitionErrs...)
+			continue
+		}
+
+		pp.Tasks = append(pp.Tasks, pi.Tasks...)
+		pp.BuildVariants = append(pp.BuildVariants, pi.BuildVariants...)
+		pp.Axes = append(pp.Axes, pi.Axes...)
+		pp.Modules = append(pp.Modules, pi.Modules...)
+		if len(pi.Functions) > 0 && pp.Functions == nil {
+			pp.Functions = map[string]*YAMLCommandSet{}
+		}
+		for name, fn := range pi.Functions {
+			pp.Functions[name] = fn
+		}
+	}
+	return errs, nil
+}
+
+// parseInclude reads an included file, rejecting any top-level keys that
+// only the main project file can set. Its commands are marked as coming from
+// the file, for errors.
+func parseInclude(data []byte, file string) (*parserInclude, error) {
+	keys := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	invalid := []string{}
+	for key := range keys {
+		if !includableSections[key] {
+			invalid = append(invalid, key)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf("'%v' can only be set in %v",
+			strings.Join(invalid, "', '"), mainProjectFile)
+	}
+	pi := &parserInclude{}
+	if err := yaml.Unmarshal(data, pi); err != nil {
+		return nil, err
+	}
+
+	sets := []*YAMLCommandSet{pi.Pre, pi.Post, pi.Timeout}
+	for _, fn := range pi.Functions {
+		sets = append(sets, fn)
+	}
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		set.file = file
+		if set.SingleCommand != nil {
+			set.SingleCommand.file = file
+		}
+		for i := range set.MultiCommand {
+			set.MultiCommand[i].file = file
+		}
+	}
+	for i := range pi.Tasks {
+		for j := range pi.Tasks[i].Commands {
+			pi.Tasks[i].Commands[j].file = file
+		}
+	}
+	return pi, nil
+}
+
+// addDefinitions records the names of everything a file defines, returning
+// an error for each one already defined by another file.
+func addDefinitions(tasks []parserTask, variants []parserBV, functions map[string]*YAMLCommandSet,
+	axes []matrixAxis, modules []Module, file string, define func(kind, name, file string) error) []error {
+	errs := []error{}
+	add := func(kind, name string) {
+		if name == "" {
+			return
+		}
+		if err := define(kind, name, file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, t := range tasks {
+		add("task", t.Name)
+	}
+	for _, bv := range variants {
+		if bv.matrix != nil {
+			add("matrix", bv.matrix.Id)
+			continue
+		}
+		add("variant", bv.Name)
+	}
+	names := []string{}
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add("function", name)
+	}
+	for _, a := range axes {
+		add("axis", a.Id)
+	}
+	for _, m := range modules {
+		add("module", m.Name)
+	}
+	return errs
+}
+
+// GithubIncludeFetcher returns an IncludeFetcher that reads included files
+// from GitHub: files in the project's repository at the given revision, and
+// files in a module at the head of the module's branch. A revision may be
+// given as the module's branch to read its files at that revision instead.
+func GithubIncludeFetcher(oauthToken string, projectRef *ProjectRef, revision string) IncludeFetcher {
+	return func(module *Module, path string) ([]byte, error) {
+		owner, repo, ref := projectRef.Owner, projectRef.Repo, revision
+		if module != nil {
+			owner, repo = module.GetRepoOwnerAndName()
+			if owner == "" || repo == "" {
+				return nil, thirdparty.YAMLFormatError{
+					Message: fmt.Sprintf("can't find the GitHub repository of module '%v'", module.Name)}
+			}
+			ref = module.Branch
+		}
+		fileURL := thirdparty.GetGithubFileURL(owner, repo, path, ref)
+		githubFile, err := thirdparty.GetGithubFile(oauthToken, fileURL)
+		if err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(githubFile.Content)
+		if err != nil {
+			return nil, thirdparty.FileDecodeError{Message: err.Error()}
+		}
+		return data, nil
+	}
+}
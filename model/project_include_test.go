@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeIncludes returns an IncludeFetcher that reads files from a map, keyed
+// by "module:path", or by the path alone for the project's own files.
+func fakeIncludes(files map[string]string) IncludeFetcher {
+	return func(module *Module, path string) ([]byte, error) {
+		if module != nil {
+			path = module.Name + ":" + path
+		}
+		data, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("no such file")
+		}
+		return []byte(data), nil
+	}
+}
+
+func TestProjectIncludes(t *testing.T) {
+	Convey("With a project file that includes other files", t, func() {
+		main := `
+owner: evergreen-ci
+include:
+  - evergreen/tasks.yml
+  - "tools:functions.yml"
+modules:
+  - name: tools
+    repo: git@github.com:evergreen-ci/tools.git
+    branch: master
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: test
+`
+		files := map[string]string{
+			"evergreen/tasks.yml": `
+tasks:
+- name: compile
+  commands:
+  - func: build
+- name: test
+  commands:
+  - command: shell.exec
+pre:
+  - command: shell.exec
+`,
+			"tools:functions.yml": `
+functions:
+  build:
+    parameters:
+    - name: target
+      default: all
+    commands:
+    - command: shell.exec
+`,
+		}
+
+		Convey("the files should be merged before the project is evaluated", func() {
+			p := &Project{}
+			So(LoadProjectWithIncludes([]byte(main), "mci", p, fakeIncludes(files)), ShouldBeNil)
+			So(p.Owner, ShouldEqual, "evergreen-ci")
+			So(len(p.Tasks), ShouldEqual, 2)
+			So(p.Tasks[0].Commands[0].Vars["target"], ShouldEqual, "all")
+			So(p.Functions["build"], ShouldNotBeNil)
+			So(p.Pre, ShouldNotBeNil)
+			So(len(p.BuildVariants), ShouldEqual, 1)
+			So(len(p.BuildVariants[0].Tasks), ShouldEqual, 2)
+		})
+
+		Convey("the merged project should load without reading the files again", func() {
+			merged, err := MergeIncludes([]byte(main), fakeIncludes(files))
+			So(err, ShouldBeNil)
+			p := &Project{}
+			So(LoadProjectInto(merged, "mci", p), ShouldBeNil)
+			So(len(p.Tasks), ShouldEqual, 2)
+			So(len(p.BuildVariants), ShouldEqual, 1)
+		})
+
+		Convey("the files should not be read without a fetcher", func() {
+			err := LoadProjectInto([]byte(main), "mci", &Project{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "evergreen/tasks.yml")
+		})
+
+		Convey("definitions in more than one file should name both files", func() {
+			files["tools:functions.yml"] += `
+tasks:
+- name: test
+`
+			err := LoadProjectWithIncludes([]byte(main), "mci", &Project{}, fakeIncludes(files))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring,
+				"included file 'tools:functions.yml': task 'test' is already defined in "+
+					"included file 'evergreen/tasks.yml'")
+		})
+
+		Convey("sections only the main file can set should be errors", func() {
+			files["evergreen/tasks.yml"] += "owner: someone-else\ninclude: other.yml\n"
+			err := LoadProjectWithIncludes([]byte(main), "mci", &Project{}, fakeIncludes(files))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring,
+				"included file 'evergreen/tasks.yml': 'include', 'owner' can only be set in the main project file")
+		})
+
+		Convey("files that can't be read should be named", func() {
+			delete(files, "tools:functions.yml")
+			err := LoadProjectWithIncludes([]byte(main), "mci", &Project{}, fakeIncludes(files))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "error reading included file 'tools:functions.yml'")
+		})
+
+		Convey("errors reading files should be returned apart from errors in the project", func() {
+			readErr := fmt.Errorf("connection refused")
+			err := LoadProjectWithIncludes([]byte(main), "mci", &Project{},
+				func(module *Module, path string) ([]byte, error) { return nil, readErr })
+			fetchErr, ok := err.(IncludeFetchError)
+			So(ok, ShouldBeTrue)
+			So(fetchErr.Include, ShouldEqual, "evergreen/tasks.yml")
+			So(fetchErr.Err, ShouldEqual, readErr)
+		})
+
+		Convey("bad function calls should name the file they're in", func() {
+			files["evergreen/tasks.yml"] = `
+tasks:
+- name: compile
+  commands:
+  - func: build
+    vars:
+      trget: x
+- name: test
+`
+			err := LoadProjectWithIncludes([]byte(main), "mci", &Project{}, fakeIncludes(files))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "included file 'evergreen/tasks.yml', line 5:")
+			So(err.Error(), ShouldContainSubstring, "unknown argument 'trget'")
+		})
+	})
+}
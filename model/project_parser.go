@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/util"
@@ -58,6 +59,7 @@ type parserProject struct {
 	Functions       map[string]*YAMLCommandSet `yaml:"functions"`
 	Tasks           []parserTask               `yaml:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs"`
+	Include         parserStringSlice          `yaml:"include"`
 
 	// Matrix code
 	Axes     []matrixAxis `yaml:"axes"`
@@ -353,7 +355,17 @@ func (pss *parserStringSlice) UnmarshalYAML(unmarshal func(interface{}) error) e
 // LoadProjectInto loads the raw data from the config file into project
 // and sets the project's identifier field to identifier. Tags are evaluateed.
 func LoadProjectInto(data []byte, identifier string, project *Project) error {
-	p, errs := projectFromYAML(data) // ignore warnings, for now (TODO)
+	return LoadProjectWithIncludes(data, identifier, project, nil)
+}
+
+// LoadProjectWithIncludes is LoadProjectInto for project files that include
+// other files, which are read with fetch. An included file that can't be
+// read is returned as an IncludeFetchError.
+func LoadProjectWithIncludes(data []byte, identifier string, project *Project, fetch IncludeFetcher) error {
+	p, errs, err := projectFromYAMLWithIncludes(data, fetch) // ignore warnings, for now (TODO)
+	if err != nil {
+		return err
+	}
 	if len(errs) > 0 {
 		// create a human-readable error list
 		buf := bytes.Buffer{}
@@ -376,12 +388,28 @@ func LoadProjectInto(data []byte, identifier string, project *Project) error {
 // projectFromYAML reads and evaluates project YAML, returning a project and warnings and
 // errors encountered during parsing or evaluation.
 func projectFromYAML(yml []byte) (*Project, []error) {
+	p, errs, _ := projectFromYAMLWithIncludes(yml, nil)
+	return p, errs
+}
+
+// projectFromYAMLWithIncludes is projectFromYAML for project files that
+// include other files, which are read with fetch. An included file that
+// can't be read is returned as an IncludeFetchError, apart from the errors
+// in the project.
+func projectFromYAMLWithIncludes(yml []byte, fetch IncludeFetcher) (*Project, []error, error) {
 	pp, errs := createIntermediateProject(yml)
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, errs, nil
+	}
+	errs, err := mergeIncludes(pp, fetch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errs, nil
 	}
 	p, errs := translateProject(pp)
-	return p, errs
+	return p, errs, nil
 }
 
 // createIntermediateProject marshals the supplied YAML into our
@@ -428,6 +456,7 @@ func translateProject(pp *parserProject) (*Project, []error) {
 	ase := NewAxisSelectorEvaluator(pp.Axes)
 	regularBVs, matrices := sieveMatrixVariants(pp.BuildVariants)
 	var evalErrs, errs []error
+	evalErrs = append(evalErrs, evaluateFunctionCalls(pp)...)
 	matrixVariants, errs := buildMatrixVariants(pp.Axes, ase, matrices)
 	evalErrs = append(evalErrs, errs...)
 	pp.BuildVariants = append(regularBVs, matrixVariants...)
@@ -439,6 +468,106 @@ func translateProject(pp *parserProject) (*Project, []error) {
 	return proj, evalErrs
 }
 
+// evaluateFunctionCalls checks the parameters each function declares, and
+// the arguments of each call to a function against them. The defaults of the
+// parameters a call doesn't set are added to its vars.
+func evaluateFunctionCalls(pp *parserProject) []error {
+	errs := []error{}
+
+	names := []string{}
+	for name := range pp.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if pp.Functions[name] == nil {
+			continue
+		}
+		fn := pp.Functions[name]
+		prefix := linePrefix(fn.file, fn.line)
+		seen := map[string]bool{}
+		for _, param := range fn.Parameters {
+			switch {
+			case param.Name == "":
+				errs = append(errs, fmt.Errorf("%vfunction '%v' has a parameter without a name",
+					prefix, name))
+			case seen[param.Name]:
+				errs = append(errs, fmt.Errorf("%vfunction '%v' declares parameter '%v' more than once",
+					prefix, name, param.Name))
+			case param.Required && param.Default != "":
+				errs = append(errs, fmt.Errorf("%vrequired parameter '%v' of function '%v' cannot have a default",
+					prefix, param.Name, name))
+			}
+			seen[param.Name] = true
+		}
+	}
+
+	check := func(where string, cmd *PluginCommandConf) {
+		if cmd.Function == "" {
+			return
+		}
+		// calls to undefined functions are reported by the validator
+		fn := pp.Functions[cmd.Function]
+		if fn == nil || len(fn.Parameters) == 0 {
+			return
+		}
+		for _, err := range fn.CheckArgs(cmd.Vars) {
+			errs = append(errs, fmt.Errorf("%vcall to function '%v' in %v: %v",
+				linePrefix(cmd.file, cmd.line), cmd.Function, where, err))
+		}
+		cmd.Vars = fn.ArgsWithDefaults(cmd.Vars)
+	}
+	for _, section := range []struct {
+		name     string
+		commands *YAMLCommandSet
+	}{{"pre", pp.Pre}, {"post", pp.Post}, {"timeout", pp.Timeout}} {
+		if section.commands == nil {
+			continue
+		}
+		if section.commands.SingleCommand != nil {
+			check(section.name, section.commands.SingleCommand)
+		}
+		for i := range section.commands.MultiCommand {
+			check(section.name, &section.commands.MultiCommand[i])
+		}
+	}
+	for i := range pp.Tasks {
+		for j := range pp.Tasks[i].Commands {
+			check(fmt.Sprintf("task '%v'", pp.Tasks[i].Name), &pp.Tasks[i].Commands[j])
+		}
+	}
+	return errs
+}
+
+// linePrefix formats where something is defined to start an error message
+// with, if the line is known. An empty file is the main project file.
+func linePrefix(file string, line int) string {
+	if line == 0 {
+		return ""
+	}
+	if file != "" {
+		return fmt.Sprintf("%v, line %v: ", file, line)
+	}
+	return fmt.Sprintf("line %v: ", line)
+}
+
+// yamlLine returns the line of the YAML node being unmarshalled, or 0 if it
+// can't be found. yaml.v2 doesn't tell unmarshalers where they are, but it
+// does put the line in type errors, so the node is decoded into a type it
+// can't be to get one.
+func yamlLine(unmarshal func(interface{}) error) int {
+	var probe int
+	typeErr, ok := unmarshal(&probe).(*yaml.TypeError)
+	if !ok || len(typeErr.Errors) == 0 {
+		return 0
+	}
+	var line int
+	if _, err := fmt.Sscanf(typeErr.Errors[0], "line %d:", &line); err != nil {
+		return 0
+	}
+	return line
+}
+
 // sieveMatrixVariants takes a set of parserBVs and groups them into regular
 // buildvariant matrix definitions and matrix definitions.
 func sieveMatrixVariants(bvs []parserBV) (regular []parserBV, matrices []matrix) {
@@ -539,3 +539,103 @@ func TestParserTaskSelectorEvaluation(t *testing.T) {
 		})
 	})
 }
+
+func TestTranslateFunctionCalls(t *testing.T) {
+	Convey("With a project whose functions declare parameters", t, func() {
+		yml := `
+functions:
+  "fetch source":
+    parameters:
+      - name: branch
+        required: true
+        description: the branch to check out
+      - name: depth
+        default: "1"
+    commands:
+      - command: shell.exec
+        params:
+          script: git clone --depth ${depth} -b ${branch} repo
+  "plain":
+    command: shell.exec
+    params:
+      script: echo
+pre:
+  - func: "fetch source"
+    vars:
+      branch: master
+tasks:
+- name: compile
+  commands:
+    - func: plain
+      vars:
+        anything: goes
+    - func: "fetch source"
+      vars:
+        brnach: master
+`
+		Convey("functions should parse in both forms", func() {
+			pp, errs := createIntermediateProject([]byte(yml))
+			So(len(errs), ShouldEqual, 0)
+			So(len(pp.Functions["fetch source"].Parameters), ShouldEqual, 2)
+			So(len(pp.Functions["fetch source"].List()), ShouldEqual, 1)
+			So(len(pp.Functions["plain"].List()), ShouldEqual, 1)
+		})
+		Convey("calls with missing or unknown arguments should error with "+
+			"their line numbers", func() {
+			pp, errs := createIntermediateProject([]byte(yml))
+			So(len(errs), ShouldEqual, 0)
+			p, errs := translateProject(pp)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldStartWith, "line 28:")
+			So(errs[0].Error(), ShouldContainSubstring, "missing required argument 'branch'")
+			So(errs[1].Error(), ShouldContainSubstring, "unknown argument 'brnach'")
+			Convey("and defaults should be filled into valid calls", func() {
+				So(p.Pre.List()[0].Vars, ShouldResemble,
+					map[string]string{"branch": "master", "depth": "1"})
+			})
+		})
+	})
+}
+
+func TestFunctionParameterErrors(t *testing.T) {
+	Convey("With functions that declare parameters", t, func() {
+		Convey("parameters without a list of commands should not parse", func() {
+			for _, yml := range []string{`
+functions:
+  "fetch source":
+    parameters:
+      - name: branch
+    command: shell.exec
+    params:
+      script: git clone repo
+`, `
+functions:
+  "fetch source":
+    parameters:
+      - name: branch
+    commands: []
+`} {
+				_, errs := createIntermediateProject([]byte(yml))
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Error(), ShouldContainSubstring, "must list their commands")
+			}
+		})
+		Convey("invalid declarations should error with their line numbers", func() {
+			yml := `
+functions:
+  "fetch source":
+    parameters:
+      - name: branch
+      - name: branch
+    commands:
+      - command: shell.exec
+`
+			pp, errs := createIntermediateProject([]byte(yml))
+			So(len(errs), ShouldEqual, 0)
+			_, errs = translateProject(pp)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Error(), ShouldStartWith, "line 4:")
+			So(errs[0].Error(), ShouldContainSubstring, "declares parameter 'branch' more than once")
+		})
+	})
+}
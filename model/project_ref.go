@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/db/bsonutil"
@@ -37,6 +38,16 @@ type ProjectRef struct {
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
+
+	// The budgets limit how much the project's tasks may cost each day and
+	// each month. BudgetOverrideUntil lifts the hard limits until the given
+	// time, set by an admin for the rest of a period.
+	DailyBudget         ProjectBudget `bson:"daily_budget" json:"daily_budget"`
+	MonthlyBudget       ProjectBudget `bson:"monthly_budget" json:"monthly_budget"`
+	BudgetOverrideUntil time.Time     `bson:"budget_override_until,omitempty" json:"budget_override_until"`
+
+	// ArtifactRetention is how long the project's artifact files are kept.
+	ArtifactRetention ArtifactRetention `bson:"artifact_retention" json:"artifact_retention"`
 }
 
 // RepositoryErrorDetails indicates whether or not there is an invalid revision and if there is one,
@@ -77,6 +88,10 @@ var (
 	ProjectRefAlertsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Alerts")
 	ProjectRefRepotrackerError      = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
 	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
+	ProjectRefDailyBudgetKey        = bsonutil.MustHaveTag(ProjectRef{}, "DailyBudget")
+	ProjectRefMonthlyBudgetKey      = bsonutil.MustHaveTag(ProjectRef{}, "MonthlyBudget")
+	ProjectRefBudgetOverrideKey     = bsonutil.MustHaveTag(ProjectRef{}, "BudgetOverrideUntil")
+	ProjectRefArtifactRetentionKey  = bsonutil.MustHaveTag(ProjectRef{}, "ArtifactRetention")
 )
 
 const (
@@ -179,6 +194,9 @@ func (projectRef *ProjectRef) Upsert() error {
 				ProjectRefAlertsKey:             projectRef.Alerts,
 				ProjectRefRepotrackerError:      projectRef.RepotrackerError,
 				ProjectRefAdminsKey:             projectRef.Admins,
+				ProjectRefDailyBudgetKey:        projectRef.DailyBudget,
+				ProjectRefMonthlyBudgetKey:      projectRef.MonthlyBudget,
+				ProjectRefArtifactRetentionKey:  projectRef.ArtifactRetention,
 			},
 		},
 	)
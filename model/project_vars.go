@@ -8,8 +8,9 @@ import (
 )
 
 var (
-	ProjectVarIdKey   = bsonutil.MustHaveTag(ProjectVars{}, "Id")
-	ProjectVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "Vars")
+	ProjectVarIdKey          = bsonutil.MustHaveTag(ProjectVars{}, "Id")
+	ProjectVarsMapKey        = bsonutil.MustHaveTag(ProjectVars{}, "Vars")
+	ProjectPrivateVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "PrivateVars")
 )
 
 const (
@@ -27,6 +28,10 @@ type ProjectVars struct {
 
 	//The actual mapping of variables for this project
 	Vars map[string]string `bson:"vars" json:"vars"`
+
+	//PrivateVars keeps track of which variables are private and should never
+	//be handed out anywhere other than to the agent running a task
+	PrivateVars map[string]bool `bson:"private_vars" json:"private_vars"`
 }
 
 func FindOneProjectVars(projectId string) (*ProjectVars, error) {
@@ -57,8 +62,20 @@ func (projectVars *ProjectVars) Upsert() (*mgo.ChangeInfo, error) {
 		},
 		bson.M{
 			"$set": bson.M{
-				ProjectVarsMapKey: projectVars.Vars,
+				ProjectVarsMapKey:        projectVars.Vars,
+				ProjectPrivateVarsMapKey: projectVars.PrivateVars,
 			},
 		},
 	)
 }
+
+// PublicVars returns the project's variables with the private ones removed.
+func (projectVars *ProjectVars) PublicVars() map[string]string {
+	public := map[string]string{}
+	for key, val := range projectVars.Vars {
+		if !projectVars.PrivateVars[key] {
+			public[key] = val
+		}
+	}
+	return public
+}
@@ -32,3 +32,17 @@ func TestFindOneProjectVar(t *testing.T) {
 		})
 	})
 }
+
+func TestProjectVarsPublicVars(t *testing.T) {
+	Convey("With project vars where some are private", t, func() {
+		projectVars := ProjectVars{
+			Id:          "mongodb",
+			Vars:        map[string]string{"a": "b", "secret": "hunter2"},
+			PrivateVars: map[string]bool{"secret": true},
+		}
+
+		Convey("only the public vars should be returned", func() {
+			So(projectVars.PublicVars(), ShouldResemble, map[string]string{"a": "b"})
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/db/bsonutil"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	SystemStatsCollection = "task_system_stats"
+)
+
+// SystemStats is one sample of a host's resource usage, taken by the agent
+// while it runs a task. Memory and disk figures are in bytes; network figures
+// are the bytes moved since the previous sample.
+type SystemStats struct {
+	Id        bson.ObjectId `bson:"_id,omitempty" json:"-"`
+	TaskId    string        `bson:"task_id" json:"task_id"`
+	Execution int           `bson:"execution" json:"execution"`
+	HostId    string        `bson:"host_id" json:"host_id"`
+	Timestamp time.Time     `bson:"ts" json:"ts"`
+
+	CPUPercent float64 `bson:"cpu_pct" json:"cpu_pct"`
+	MemTotal   int64   `bson:"mem_total" json:"mem_total"`
+	MemUsed    int64   `bson:"mem_used" json:"mem_used"`
+	DiskTotal  int64   `bson:"disk_total" json:"disk_total"`
+	DiskUsed   int64   `bson:"disk_used" json:"disk_used"`
+	NetRecv    int64   `bson:"net_recv" json:"net_recv"`
+	NetSent    int64   `bson:"net_sent" json:"net_sent"`
+}
+
+var (
+	SystemStatsTaskIdKey    = bsonutil.MustHaveTag(SystemStats{}, "TaskId")
+	SystemStatsExecutionKey = bsonutil.MustHaveTag(SystemStats{}, "Execution")
+	SystemStatsTimestampKey = bsonutil.MustHaveTag(SystemStats{}, "Timestamp")
+)
+
+// MemPercent returns the percentage of memory in use.
+func (s *SystemStats) MemPercent() float64 {
+	return percentOf(s.MemUsed, s.MemTotal)
+}
+
+// DiskPercent returns the percentage of the task directory's disk in use.
+func (s *SystemStats) DiskPercent() float64 {
+	return percentOf(s.DiskUsed, s.DiskTotal)
+}
+
+func percentOf(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * float64(used) / float64(total)
+}
+
+// InsertSystemStats stores a batch of samples taken while running the given
+// execution of a task on a host.
+func InsertSystemStats(taskId string, execution int, hostId string, samples []SystemStats) error {
+	docs := make([]interface{}, 0, len(samples))
+	for _, s := range samples {
+		s.Id = ""
+		s.TaskId = taskId
+		s.Execution = execution
+		s.HostId = hostId
+		docs = append(docs, s)
+	}
+	return db.InsertMany(SystemStatsCollection, docs...)
+}
+
+// FindSystemStats returns the samples taken during an execution of a task,
+// oldest first.
+func FindSystemStats(taskId string, execution int) ([]SystemStats, error) {
+	stats := []SystemStats{}
+	err := db.FindAll(
+		SystemStatsCollection,
+		bson.M{
+			SystemStatsTaskIdKey:    taskId,
+			SystemStatsExecutionKey: execution,
+		},
+		db.NoProjection,
+		[]string{SystemStatsTimestampKey},
+		db.NoSkip,
+		db.NoLimit,
+		&stats,
+	)
+	return stats, err
+}
+
+// SystemStatsSummary holds the peak resource usage of a task, which is
+// usually enough to tell whether it ran out of memory or disk.
+type SystemStatsSummary struct {
+	Samples        int     `json:"samples"`
+	MaxCPUPercent  float64 `json:"max_cpu_pct"`
+	MaxMemPercent  float64 `json:"max_mem_pct"`
+	MaxDiskPercent float64 `json:"max_disk_pct"`
+	MaxMemUsed     int64   `json:"max_mem_used"`
+	MaxDiskUsed    int64   `json:"max_disk_used"`
+	TotalNetRecv   int64   `json:"total_net_recv"`
+	TotalNetSent   int64   `json:"total_net_sent"`
+}
+
+// SummarizeSystemStats returns the peak usage across the given samples.
+func SummarizeSystemStats(stats []SystemStats) SystemStatsSummary {
+	summary := SystemStatsSummary{Samples: len(stats)}
+	for i := range stats {
+		s := &stats[i]
+		if s.CPUPercent > summary.MaxCPUPercent {
+			summary.MaxCPUPercent = s.CPUPercent
+		}
+		if p := s.MemPercent(); p > summary.MaxMemPercent {
+			summary.MaxMemPercent = p
+		}
+		if p := s.DiskPercent(); p > summary.MaxDiskPercent {
+			summary.MaxDiskPercent = p
+		}
+		if s.MemUsed > summary.MaxMemUsed {
+			summary.MaxMemUsed = s.MemUsed
+		}
+		if s.DiskUsed > summary.MaxDiskUsed {
+			summary.MaxDiskUsed = s.DiskUsed
+		}
+		summary.TotalNetRecv += s.NetRecv
+		summary.TotalNetSent += s.NetSent
+	}
+	return summary
+}
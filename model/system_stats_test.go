@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSystemStats(t *testing.T) {
+	Convey("With system stats sent for a task", t, func() {
+		testutil.HandleTestingErr(db.Clear(SystemStatsCollection), t,
+			"Error clearing '%v' collection", SystemStatsCollection)
+
+		start := time.Now().Round(time.Second)
+		samples := []SystemStats{
+			{Timestamp: start.Add(time.Minute), CPUPercent: 50, MemTotal: 100, MemUsed: 90,
+				DiskTotal: 1000, DiskUsed: 100, NetRecv: 10, NetSent: 1},
+			{Timestamp: start, CPUPercent: 80, MemTotal: 100, MemUsed: 40,
+				DiskTotal: 1000, DiskUsed: 500, NetRecv: 5, NetSent: 2},
+		}
+		So(InsertSystemStats("t1", 0, "h1", samples), ShouldBeNil)
+		So(InsertSystemStats("t1", 1, "h1", samples[:1]), ShouldBeNil)
+
+		Convey("the samples for an execution should be found in order", func() {
+			stats, err := FindSystemStats("t1", 0)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 2)
+			So(stats[0].CPUPercent, ShouldEqual, 80)
+			So(stats[0].TaskId, ShouldEqual, "t1")
+			So(stats[0].HostId, ShouldEqual, "h1")
+
+			Convey("and their peaks summarized", func() {
+				summary := SummarizeSystemStats(stats)
+				So(summary.Samples, ShouldEqual, 2)
+				So(summary.MaxCPUPercent, ShouldEqual, 80)
+				So(summary.MaxMemPercent, ShouldEqual, 90)
+				So(summary.MaxDiskPercent, ShouldEqual, 50)
+				So(summary.TotalNetRecv, ShouldEqual, 15)
+				So(summary.TotalNetSent, ShouldEqual, 3)
+			})
+		})
+	})
+}
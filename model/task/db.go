@@ -275,6 +275,16 @@ func ByTimeRun(startTime, endTime time.Time) db.Q {
 			}})
 }
 
+// ByProjectFinishedBetween returns all tasks of a project that finished
+// between two given times.
+func ByProjectFinishedBetween(project string, startTime, endTime time.Time) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:    project,
+		FinishTimeKey: bson.M{"$gte": startTime, "$lt": endTime},
+		StatusKey:     bson.M{"$in": evergreen.CompletedStatuses},
+	})
+}
+
 func ByStatuses(statuses []string, buildVariant, displayName, project, requester string) db.Q {
 	return db.Query(bson.M{
 		BuildVariantKey: buildVariant,
@@ -86,8 +86,8 @@ type Task struct {
 	// how long we expect the task to take from start to finish
 	ExpectedDuration time.Duration `bson:"expected_duration,omitempty" json:"expected_duration,omitempty"`
 
-	// an estimate of what the task cost to run, hidden from JSON views for now
-	Cost float64 `bson:"cost,omitempty" json:"-"`
+	// an estimate of what the task cost to run
+	Cost float64 `bson:"cost,omitempty" json:"cost,omitempty"`
 
 	// test results captured and sent back by agent
 	TestResults []TestResult `bson:"test_results" json:"test_results"`
@@ -7,6 +7,7 @@ import (
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/annotations"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
@@ -96,6 +97,11 @@ func resetTask(taskId string) error {
 		return fmt.Errorf("Can't restart task because it can't be archived: %v", err)
 	}
 
+	// keep what is known about the failure on the new execution
+	if err := annotations.CarryForward(t.Id, t.Execution, t.Execution+1); err != nil {
+		return fmt.Errorf("Can't carry forward annotations for task: %v", err)
+	}
+
 	if err = t.Reset(); err != nil {
 		return err
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
@@ -405,6 +406,26 @@ func TestTryResetTask(t *testing.T) {
 				So(oldTask.Details, ShouldResemble, *detail)
 				So(oldTask.FinishTime, ShouldNotResemble, util.ZeroTime)
 			})
+			Convey("should carry the task's annotations forward to the new execution", func() {
+				testutil.HandleTestingErr(db.Clear(annotations.Collection), t, "Error clearing annotations")
+				annotation := &annotations.Annotation{
+					TaskId:         testTask.Id,
+					TaskExecution:  1,
+					IssueKey:       "EVG-1",
+					Classification: annotations.KnownFailure,
+					Author:         userName,
+				}
+				So(annotation.Insert(), ShouldBeNil)
+				So(TryResetTask(testTask.Id, userName, "", p, detail), ShouldBeNil)
+				carried, err := annotations.Find(annotations.ByTaskExecution(testTask.Id, 2))
+				So(err, ShouldBeNil)
+				So(len(carried), ShouldEqual, 1)
+				So(carried[0].IssueKey, ShouldEqual, "EVG-1")
+				So(*carried[0].CarriedFrom, ShouldEqual, 1)
+				original, err := annotations.Find(annotations.ByTaskExecution(testTask.Id, 1))
+				So(err, ShouldBeNil)
+				So(len(original), ShouldEqual, 1)
+			})
 
 		})
 		Convey("resetting a task with a max number of excutions", func() {
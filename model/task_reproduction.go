@@ -0,0 +1,264 @@
+package model
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// TaskScriptFileName is the name of the script, written into a reproduced
+	// task's directory, that runs the task's commands.
+	TaskScriptFileName = "task.sh"
+
+	// TaskExpansionsFileName is the name of the YAML file, written into a
+	// reproduced task's directory, holding the task's expansions.
+	TaskExpansionsFileName = "expansions.yml"
+
+	shellExecCommandName     = "shell.exec"
+	gitGetProjectCommandName = "git.get_project"
+
+	scriptHeredocDelimiter = "EVERGREEN_SCRIPT_EOF"
+)
+
+// TaskDirectoryName returns the name of the directory, within the distro's
+// working directory, that a task is reproduced in. It is derived the same way
+// as the agent's task directory, minus the agent's pid.
+func TaskDirectoryName(taskId string, execution int) string {
+	h := md5.New()
+	h.Write([]byte(fmt.Sprintf("%s_%d", taskId, execution)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FindTaskExpansions returns the expansions the agent would have used to run
+// the given task, including all of the project's variables that are not
+// marked private. The "workdir" expansion is left as the distro's working
+// directory; callers reproducing the task should point it at the task's own
+// directory.
+func FindTaskExpansions(t *task.Task) (*command.Expansions, error) {
+	d, err := distro.FindOne(distro.ById(t.DistroId))
+	if err != nil {
+		return nil, fmt.Errorf("error finding distro '%v': %v", t.DistroId, err)
+	}
+	if d == nil {
+		return nil, fmt.Errorf("distro '%v' not found", t.DistroId)
+	}
+
+	v, err := version.FindOne(version.ById(t.Version))
+	if err != nil {
+		return nil, fmt.Errorf("error finding version '%v': %v", t.Version, err)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("version '%v' not found", t.Version)
+	}
+
+	project := &Project{}
+	if err = LoadProjectInto([]byte(v.Config), v.Identifier, project); err != nil {
+		return nil, fmt.Errorf("error loading project for version '%v': %v", v.Id, err)
+	}
+	bv := project.FindBuildVariant(t.BuildVariant)
+	if bv == nil {
+		return nil, fmt.Errorf("couldn't find buildvariant: '%v'", t.BuildVariant)
+	}
+
+	expansions := populateExpansions(d, v, bv, t)
+
+	projectVars, err := FindOneProjectVars(t.Project)
+	if err != nil {
+		return nil, fmt.Errorf("error finding variables for project '%v': %v", t.Project, err)
+	}
+	if projectVars != nil {
+		expansions.Update(projectVars.PublicVars())
+	}
+	return expansions, nil
+}
+
+// TaskSourceDirectory returns the directory, relative to the task's working
+// directory, that the task's git.get_project command clones the source into.
+// It returns an empty string if the task never fetches its source.
+func (p *Project) TaskSourceDirectory(variant, taskName string) string {
+	for _, cmd := range p.taskCommands(variant, taskName, true) {
+		if cmd.conf.Command != gitGetProjectCommandName {
+			continue
+		}
+		if dir, ok := cmd.conf.Params["directory"].(string); ok {
+			return dir
+		}
+	}
+	return ""
+}
+
+// TaskScript returns a bash script that runs the pre-task, task and post-task
+// commands of the given task in order, with the given expansions applied.
+// Shell commands are inlined; commands that only the agent can run are listed
+// as comments so the user can see what was skipped.
+func (p *Project) TaskScript(variant, taskName string, expansions *command.Expansions) (string, error) {
+	if p.FindProjectTask(taskName) == nil {
+		return "", fmt.Errorf("task '%v' not found in project", taskName)
+	}
+	if p.FindBuildVariant(variant) == nil {
+		return "", fmt.Errorf("couldn't find buildvariant: '%v'", variant)
+	}
+
+	// function variables modify the expansions, so work on a copy
+	exp := command.NewExpansions(*expansions)
+	workDir := exp.Get("workdir")
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "#!/bin/bash")
+	fmt.Fprintf(buf, "# Runs the commands of task '%v' on variant '%v' in the order the agent does.\n",
+		taskName, variant)
+	fmt.Fprintf(buf, "# Expansions are in %v; private project variables are not included.\n",
+		TaskExpansionsFileName)
+	fmt.Fprintf(buf, "cd %v || exit 1\n", util.ShellQuote(workDir))
+
+	for _, cmd := range p.taskCommands(variant, taskName, true) {
+		for key, val := range cmd.vars {
+			newVal, err := exp.ExpandString(val)
+			if err != nil {
+				return "", fmt.Errorf("can't expand '%v': %v", val, err)
+			}
+			exp.Put(key, newVal)
+		}
+
+		fmt.Fprintf(buf, "\n# %v\n", cmd.description)
+		switch cmd.conf.Command {
+		case shellExecCommandName:
+		case gitGetProjectCommandName:
+			fmt.Fprintf(buf, "# skipped '%v': the source is cloned by 'evergreen fetch --source'\n", cmd.conf.Command)
+			continue
+		default:
+			fmt.Fprintf(buf, "# skipped '%v': only the agent can run this command\n", cmd.conf.Command)
+			continue
+		}
+
+		params := struct {
+			Script          string `mapstructure:"script"`
+			Shell           string `mapstructure:"shell"`
+			WorkingDir      string `mapstructure:"working_dir"`
+			Background      bool   `mapstructure:"background"`
+			ContinueOnError bool   `mapstructure:"continue_on_err"`
+		}{}
+		if err := mapstructure.Decode(cmd.conf.Params, &params); err != nil {
+			return "", fmt.Errorf("error decoding %v params: %v", shellExecCommandName, err)
+		}
+		script, err := exp.ExpandString(params.Script)
+		if err != nil {
+			return "", fmt.Errorf("failed to apply expansions: %v", err)
+		}
+		shell := params.Shell
+		if shell == "" {
+			shell = "sh"
+		}
+		dir := workDir
+		if params.WorkingDir != "" {
+			dir = filepath.Join(workDir, params.WorkingDir)
+		}
+
+		fmt.Fprintln(buf, "(")
+		fmt.Fprintf(buf, "cd %v || exit 1\n", util.ShellQuote(dir))
+		fmt.Fprintf(buf, "%v <<'%v'", shell, scriptHeredocDelimiter)
+		if params.Background {
+			fmt.Fprint(buf, " &")
+		}
+		fmt.Fprintf(buf, "\n%v\n%v\n)", strings.TrimRight(script, "\n"), scriptHeredocDelimiter)
+		// only failures in the task's own commands fail the task
+		if cmd.block == "task" && !params.ContinueOnError {
+			fmt.Fprint(buf, " || exit $?")
+		}
+		fmt.Fprintln(buf)
+	}
+	return buf.String(), nil
+}
+
+// TaskExpansionsYAML returns the expansions as a YAML document, sorted by
+// key, suitable for writing to TaskExpansionsFileName.
+func TaskExpansionsYAML(expansions *command.Expansions) ([]byte, error) {
+	keys := make([]string, 0, len(*expansions))
+	for key := range *expansions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ordered := yaml.MapSlice{}
+	for _, key := range keys {
+		ordered = append(ordered, yaml.MapItem{Key: key, Value: expansions.Get(key)})
+	}
+	return yaml.Marshal(ordered)
+}
+
+// taskCommand is a single command of a task, with functions flattened out.
+type taskCommand struct {
+	conf        PluginCommandConf
+	vars        map[string]string
+	block       string
+	description string
+}
+
+// commandBlock is one of the pre, task or post command lists.
+type commandBlock struct {
+	name     string
+	commands []PluginCommandConf
+}
+
+// taskCommands flattens the pre, task and post commands of the given task
+// into the sequence the agent runs, skipping commands that do not run on the
+// variant. Only the task's own commands are returned unless includeAll is set.
+func (p *Project) taskCommands(variant, taskName string, includeAll bool) []taskCommand {
+	blocks := []commandBlock{}
+	if includeAll && p.Pre != nil {
+		blocks = append(blocks, commandBlock{"pre", p.Pre.List()})
+	}
+	if pt := p.FindProjectTask(taskName); pt != nil {
+		blocks = append(blocks, commandBlock{"task", pt.Commands})
+	}
+	if includeAll && p.Post != nil {
+		blocks = append(blocks, commandBlock{"post", p.Post.List()})
+	}
+
+	out := []taskCommand{}
+	for _, block := range blocks {
+		for i, cmd := range block.commands {
+			if !cmd.RunOnVariant(variant) {
+				continue
+			}
+			step := fmt.Sprintf("%v: step %v of %v", block.name, i+1, len(block.commands))
+			if cmd.Function == "" {
+				out = append(out, taskCommand{
+					conf:        cmd,
+					block:       block.name,
+					description: fmt.Sprintf("%v: '%v'", step, cmd.GetDisplayName()),
+				})
+				continue
+			}
+			fn, ok := p.Functions[cmd.Function]
+			if !ok {
+				continue
+			}
+			for _, fnCmd := range fn.List() {
+				if !fnCmd.RunOnVariant(variant) {
+					continue
+				}
+				out = append(out, taskCommand{
+					conf:        fnCmd,
+					vars:        cmd.Vars,
+					block:       block.name,
+					description: fmt.Sprintf(`%v: '%v' in "%v"`, step, fnCmd.Command, cmd.Function),
+				})
+			}
+		}
+	}
+	return out
+}
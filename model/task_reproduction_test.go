@@ -0,0 +1,143 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var reproductionProjectYAML = `
+pre:
+  - command: git.get_project
+    params:
+      directory: src
+functions:
+  "run tests":
+    - command: shell.exec
+      params:
+        working_dir: src
+        script: ./run ${suite}
+post:
+  - command: attach.results
+    params:
+      file_location: report.json
+tasks:
+  - name: compile
+    commands:
+      - command: shell.exec
+        params:
+          script: make ${target}
+      - func: "run tests"
+        vars:
+          suite: ${target}_tests
+      - command: shell.exec
+        variants: ["windows"]
+        params:
+          script: nmake
+buildvariants:
+  - name: linux
+    expansions:
+      target: all
+    tasks:
+      - name: compile
+`
+
+func TestTaskScript(t *testing.T) {
+	Convey("With a project that has pre, task, post and function commands", t, func() {
+		project := &Project{}
+		So(LoadProjectInto([]byte(reproductionProjectYAML), "reproduce", project), ShouldBeNil)
+		expansions := command.NewExpansions(map[string]string{
+			"workdir": "/data/mci/taskdir",
+			"target":  "all",
+		})
+
+		Convey("the script should run the shell commands in order with expansions applied", func() {
+			script, err := project.TaskScript("linux", "compile", expansions)
+			So(err, ShouldBeNil)
+			So(script, ShouldStartWith, "#!/bin/bash\n")
+			So(script, ShouldContainSubstring, "cd '/data/mci/taskdir' || exit 1")
+			So(script, ShouldContainSubstring, "# skipped 'git.get_project'")
+			So(script, ShouldContainSubstring, "make all\n")
+			So(script, ShouldContainSubstring, "cd '/data/mci/taskdir/src' || exit 1")
+			So(script, ShouldContainSubstring, "./run all_tests\n")
+			So(script, ShouldContainSubstring, "# skipped 'attach.results'")
+			So(script, ShouldNotContainSubstring, "nmake")
+		})
+
+		Convey("the caller's expansions should not be modified by function vars", func() {
+			_, err := project.TaskScript("linux", "compile", expansions)
+			So(err, ShouldBeNil)
+			So(expansions.Exists("suite"), ShouldBeFalse)
+		})
+
+		Convey("an unknown task or variant should be an error", func() {
+			_, err := project.TaskScript("linux", "nope", expansions)
+			So(err, ShouldNotBeNil)
+			_, err = project.TaskScript("nope", "compile", expansions)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("the source directory should come from git.get_project", func() {
+			So(project.TaskSourceDirectory("linux", "compile"), ShouldEqual, "src")
+		})
+	})
+}
+
+func TestFindTaskExpansions(t *testing.T) {
+	Convey("With a task, its distro, version and project vars in the database", t, func() {
+		testutil.HandleTestingErr(db.ClearCollections(distro.Collection, version.Collection,
+			ProjectVarsCollection), t, "Error clearing collections")
+
+		d := &distro.Distro{
+			Id:         "d1",
+			WorkDir:    "/data/mci",
+			Expansions: []distro.Expansion{{Key: "distro_exp", Value: "val"}},
+		}
+		So(d.Insert(), ShouldBeNil)
+		v := &version.Version{
+			Id:         "v1",
+			Identifier: "reproduce",
+			Config:     reproductionProjectYAML,
+		}
+		So(v.Insert(), ShouldBeNil)
+		projectVars := &ProjectVars{
+			Id:          "reproduce",
+			Vars:        map[string]string{"public": "a", "secret": "b"},
+			PrivateVars: map[string]bool{"secret": true},
+		}
+		_, err := projectVars.Upsert()
+		So(err, ShouldBeNil)
+
+		tsk := &task.Task{
+			Id:           "t1",
+			DisplayName:  "compile",
+			BuildVariant: "linux",
+			DistroId:     "d1",
+			Version:      "v1",
+			Project:      "reproduce",
+		}
+
+		Convey("the expansions should include everything but private vars", func() {
+			expansions, err := FindTaskExpansions(tsk)
+			So(err, ShouldBeNil)
+			So(expansions.Get("task_id"), ShouldEqual, "t1")
+			So(expansions.Get("workdir"), ShouldEqual, "/data/mci")
+			So(expansions.Get("distro_exp"), ShouldEqual, "val")
+			So(expansions.Get("target"), ShouldEqual, "all")
+			So(expansions.Get("public"), ShouldEqual, "a")
+			So(expansions.Exists("secret"), ShouldBeFalse)
+		})
+
+		Convey("a missing distro should be an error", func() {
+			tsk.DistroId = "nope"
+			_, err := FindTaskExpansions(tsk)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -65,6 +65,24 @@ func FindOneTestLog(name, task string, execution int) (*TestLog, error) {
 	return tl, err
 }
 
+// FindAllTestLogs returns every test log recorded for the given task execution.
+func FindAllTestLogs(task string, execution int) ([]TestLog, error) {
+	logs := []TestLog{}
+	err := db.FindAll(
+		TestLogCollection,
+		bson.M{
+			TestLogTaskKey:          task,
+			TestLogTaskExecutionKey: execution,
+		},
+		db.NoProjection,
+		[]string{TestLogNameKey},
+		db.NoSkip,
+		db.NoLimit,
+		&logs,
+	)
+	return logs, err
+}
+
 // Insert inserts the TestLog into the database
 func (self *TestLog) Insert() error {
 	self.Id = bson.NewObjectId().Hex()
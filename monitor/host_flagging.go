@@ -182,7 +182,8 @@ func flagProvisioningFailedHosts(d []distro.Distro, s *evergreen.Settings) ([]ho
 }
 
 // flagExpiredHosts is a hostFlaggingFunc to get all user-spawned hosts
-// that have expired
+// that have expired. Stopped hosts are included, since their preserved disks
+// keep costing money until they are terminated.
 func flagExpiredHosts(d []distro.Distro, s *evergreen.Settings) ([]host.Host, error) {
 	// fetch the expired hosts
 	hosts, err := host.Find(host.ByExpiredSince(time.Now()))
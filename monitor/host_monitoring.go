@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/cloud/providers"
+	"github.com/evergreen-ci/evergreen/cloud/providers/static"
 	"github.com/evergreen-ci/evergreen/model/host"
 )
 
@@ -108,6 +109,21 @@ func checkHostReachability(host host.Host, settings *evergreen.Settings) error {
 			return fmt.Errorf("error checking ssh reachability for host %v: %v", host.Id, err)
 		}
 
+		// static hosts that keep failing their checks are taken out of service
+		if reachable {
+			if err := static.RecordSuccess(&host); err != nil {
+				return fmt.Errorf("error clearing failures for host %v: %v", host.Id, err)
+			}
+		} else {
+			quarantined, err := static.RecordFailure(&host, "failed reachability check")
+			if err != nil {
+				return fmt.Errorf("error recording failed reachability check for host %v: %v", host.Id, err)
+			}
+			if quarantined {
+				return nil
+			}
+		}
+
 		// log the status update if the reachability of the host is changing
 		if host.Status == evergreen.HostUnreachable && reachable {
 			evergreen.Logger.Logf(slogger.INFO, "Setting host %v as reachable", host.Id)
@@ -133,8 +133,9 @@ func terminateHost(host *host.Host, settings *evergreen.Settings) error {
 		return fmt.Errorf("error getting cloud host for %v: %v", host.Id, err)
 	}
 
-	// run teardown script if we have one, sending notifications if things go awry
-	if host.Distro.Teardown != "" && host.Provisioned {
+	// run teardown script if we have one, sending notifications if things go awry.
+	// stopped hosts can't be reached over ssh, so they are terminated directly
+	if host.Distro.Teardown != "" && host.Provisioned && host.Status != evergreen.HostStopped {
 		evergreen.Logger.Logf(slogger.ERROR, "Running teardown script for host %v", host.Id)
 		if err := runHostTeardown(host, cloudHost); err != nil {
 			evergreen.Logger.Logf(slogger.ERROR, "Error running teardown script for %v: %v", host.Id, err)
@@ -0,0 +1,282 @@
+package artifacts
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
+	"github.com/tychoish/grip/slogger"
+)
+
+func init() {
+	plugin.Publish(&ArtifactsPlugin{})
+}
+
+const (
+	ArtifactsPluginName = "artifacts"
+	ArtifactsPutCmd     = "put"
+	ArtifactsGetCmd     = "get"
+
+	// FilesEndpoint is where files in the local store are uploaded to and
+	// downloaded from, on both the API and UI servers.
+	FilesEndpoint = "files"
+)
+
+// ArtifactsPlugin has commands for putting build outputs in, and getting
+// them from, any artifact store. It also hosts the local store, which keeps
+// files on the servers' disks, for teams that don't use S3. The local store
+// is set up in the plugin's settings:
+//  plugins:
+//    artifacts:
+//      local_dir: /data/evergreen/artifacts
+type ArtifactsPlugin struct {
+	local *store.LocalStore
+}
+
+// Name returns the name of the plugin.
+func (ap *ArtifactsPlugin) Name() string {
+	return ArtifactsPluginName
+}
+
+// Configure sets up the local store, if the settings have a directory for it.
+func (ap *ArtifactsPlugin) Configure(conf map[string]interface{}) error {
+	local, err := localStore(conf)
+	if err != nil {
+		return err
+	}
+	ap.local = local
+	return nil
+}
+
+// localStore returns the local store set up in the plugin's settings, or
+// nil if there isn't one.
+func localStore(conf map[string]interface{}) (*store.LocalStore, error) {
+	dir, ok := conf["local_dir"]
+	if !ok {
+		return nil, nil
+	}
+	dirString, ok := dir.(string)
+	if !ok || dirString == "" {
+		return nil, fmt.Errorf("local_dir must be a directory")
+	}
+	return store.NewLocalStore(dirString, localLinkRoot), nil
+}
+
+// localLinkRoot is the root of the links to files in the local store.
+var localLinkRoot = fmt.Sprintf("/plugin/%v/%v", ArtifactsPluginName, FilesEndpoint)
+
+// LocalStore returns the local store set up in the settings, or nil if
+// there isn't one, for processes other than the servers that manage the
+// files in it.
+func LocalStore(settings *evergreen.Settings) (*store.LocalStore, error) {
+	return localStore(settings.Plugins[ArtifactsPluginName])
+}
+
+// NewCommand returns the requested command.
+func (ap *ArtifactsPlugin) NewCommand(cmdName string) (plugin.Command, error) {
+	switch cmdName {
+	case ArtifactsPutCmd:
+		return &PutCommand{}, nil
+	case ArtifactsGetCmd:
+		return &GetCommand{}, nil
+	default:
+		return nil, fmt.Errorf("No such %v command: %v", ArtifactsPluginName, cmdName)
+	}
+}
+
+// GetAPIHandler returns the routes agents upload files to the local store
+// and download them from.
+func (ap *ArtifactsPlugin) GetAPIHandler() http.Handler {
+	r := http.NewServeMux()
+	r.HandleFunc(fmt.Sprintf("/%v/", FilesEndpoint), ap.taskFilesHandler)
+	r.HandleFunc("/", http.NotFound) // 404 any request not routable to these endpoints
+	return r
+}
+
+// GetUIHandler returns the route that serves files in the local store to
+// logged in users.
+func (ap *ArtifactsPlugin) GetUIHandler() http.Handler {
+	r := http.NewServeMux()
+	r.HandleFunc(fmt.Sprintf("/%v/", FilesEndpoint), ap.serveFileHandler)
+	r.HandleFunc("/", http.NotFound)
+	return r
+}
+
+// GetPanelConfig returns nil, since files are shown by the attach plugin.
+func (ap *ArtifactsPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
+	return nil, nil
+}
+
+// filePath returns the path in the store named by the request's URL.
+func filePath(r *http.Request) string {
+	return strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/%v/", FilesEndpoint))
+}
+
+// taskFilesHandler uploads files to, and downloads them from, the task's
+// project's part of the local store.
+func (ap *ArtifactsPlugin) taskFilesHandler(w http.ResponseWriter, r *http.Request) {
+	t := plugin.GetTask(r)
+	if t == nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	if r.Header.Get(evergreen.TaskSecretHeader) != t.Secret {
+		http.Error(w, "wrong secret!", http.StatusConflict)
+		return
+	}
+	if ap.local == nil {
+		http.Error(w, "the local artifact store is not configured", http.StatusNotImplemented)
+		return
+	}
+	remotePath, err := store.CleanPath(filePath(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// keep each project's files apart
+	remotePath = projectDir(t.Project) + remotePath
+
+	switch r.Method {
+	case "POST":
+		file, err := ap.local.PutReader(r.Body, remotePath)
+		if err != nil {
+			message := fmt.Sprintf("error storing %v for task %v: %v", remotePath, t.Id, err)
+			evergreen.Logger.Logf(slogger.ERROR, "%v", message)
+			http.Error(w, message, http.StatusInternalServerError)
+			return
+		}
+		plugin.WriteJSON(w, http.StatusOK, file)
+	case "GET":
+		serveFile(w, r, ap.local, remotePath)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// projectDir returns the directory of the local store that the project's
+// files are kept in.
+func projectDir(project string) string {
+	return project + "/"
+}
+
+// InProjectDir returns true if the path in a store or bucket is within the
+// project's directory.
+func InProjectDir(project, remotePath string) bool {
+	cleaned, err := store.CleanPath(remotePath)
+	return err == nil && project != "" && cleaned == remotePath &&
+		strings.HasPrefix(cleaned, projectDir(project))
+}
+
+// VerifyLocations clears the store, bucket and path of files attached by a
+// task in the project unless they're where the file's link points, and, for
+// the local store, within the project's directory. Files' locations are
+// what expired files are deleted from and private links are signed for, so
+// a task can only claim locations that its own uploads could have had.
+func VerifyLocations(project string, files []artifact.File) {
+	for i := range files {
+		f := &files[i]
+		var ok bool
+		switch f.Store {
+		case store.S3:
+			ok = f.Bucket != "" && f.Path != "" && f.Link == store.S3BaseURL+f.Bucket+"/"+f.Path
+		case store.Local:
+			ok = f.Bucket == "" && InProjectDir(project, f.Path) && f.Link == localLinkRoot+"/"+f.Path
+		}
+		if !ok {
+			f.Store, f.Bucket, f.Path = "", "", ""
+		}
+	}
+}
+
+// serveFileHandler serves files in the local store to logged in users who
+// can see the tasks of the project whose directory the file is in.
+func (ap *ArtifactsPlugin) serveFileHandler(w http.ResponseWriter, r *http.Request) {
+	u := plugin.GetUser(r)
+	if u == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if ap.local == nil {
+		http.NotFound(w, r)
+		return
+	}
+	remotePath := filePath(r)
+	project := fileProject(remotePath)
+	if project == "" {
+		http.NotFound(w, r)
+		return
+	}
+	projectRef, err := model.FindOneProjectRef(project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if projectRef == nil || !canViewProject(projectRef, u) {
+		http.NotFound(w, r)
+		return
+	}
+	serveFile(w, r, ap.local, remotePath)
+}
+
+// fileProject returns the project whose directory of the local store the
+// path is in, or an empty string if it isn't in one.
+func fileProject(remotePath string) string {
+	i := strings.Index(remotePath, "/")
+	if i <= 0 || !InProjectDir(remotePath[:i], remotePath) {
+		return ""
+	}
+	return remotePath[:i]
+}
+
+// canViewProject returns true if the user may see the project's tasks, and
+// so the files they attached. Like the task pages, private projects are
+// only shown to logged in users.
+func canViewProject(projectRef *model.ProjectRef, u *user.DBUser) bool {
+	return !projectRef.Private || u != nil
+}
+
+// inlineTypes are the content types of files that are safe to show in the
+// browser. Anything else a task uploads, such as HTML or SVG, could run
+// scripts as the logged in user, so it is only served as a download.
+var inlineTypes = map[string]bool{
+	"text/plain":       true,
+	"application/json": true,
+	"image/png":        true,
+	"image/jpeg":       true,
+	"image/gif":        true,
+}
+
+// serveFile writes the contents of the file at the remote path.
+func serveFile(w http.ResponseWriter, r *http.Request, s store.ArtifactStore, remotePath string) {
+	reader, err := s.Get(remotePath)
+	if _, ok := err.(store.ErrNotFound); ok {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+	contentType := mime.TypeByExtension(path.Ext(remotePath))
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || !inlineTypes[mediaType] {
+		contentType = "application/octet-stream"
+		w.Header().Set("Content-Disposition",
+			mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(remotePath)}))
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err = io.Copy(w, reader); err != nil {
+		evergreen.Logger.Errorf(slogger.ERROR, "error serving %v: %v", remotePath, err)
+	}
+}
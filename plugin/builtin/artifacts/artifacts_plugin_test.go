@@ -0,0 +1,173 @@
+package artifacts
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArtifactsPutValidateParams(t *testing.T) {
+	Convey("With an artifacts put command", t, func() {
+		cmd := &PutCommand{}
+
+		Convey("the local store should be used by default", func() {
+			params := map[string]interface{}{
+				"local_file":  "local",
+				"remote_file": "remote",
+			}
+			So(cmd.ParseParams(params), ShouldBeNil)
+			So(cmd.Store, ShouldEqual, "local")
+		})
+		Convey("an unknown store should cause an error", func() {
+			params := map[string]interface{}{
+				"store":       "ftp",
+				"local_file":  "local",
+				"remote_file": "remote",
+			}
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+		})
+		Convey("the s3 store should require credentials and a bucket", func() {
+			params := map[string]interface{}{
+				"store":       "s3",
+				"aws_key":     "key",
+				"local_file":  "local",
+				"remote_file": "remote",
+			}
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+
+			params["aws_secret"] = "secret"
+			params["bucket"] = "bck"
+			So(cmd.ParseParams(params), ShouldBeNil)
+			So(cmd.Bucket, ShouldEqual, "bck")
+		})
+		Convey("a local file and an inclusion filter should cause an error", func() {
+			params := map[string]interface{}{
+				"local_file":                 "local",
+				"local_files_include_filter": []string{"local"},
+				"remote_file":                "remote",
+			}
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+		})
+		Convey("an invalid visibility should cause an error", func() {
+			params := map[string]interface{}{
+				"local_file":  "local",
+				"remote_file": "remote",
+				"visibility":  "everyone",
+			}
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestArtifactsGetValidateParams(t *testing.T) {
+	Convey("With an artifacts get command", t, func() {
+		cmd := &GetCommand{}
+
+		Convey("exactly one of local_file and extract_to should be required", func() {
+			params := map[string]interface{}{"remote_file": "remote"}
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+
+			params["local_file"] = "local"
+			params["extract_to"] = "dir"
+			So(cmd.ParseParams(params), ShouldNotBeNil)
+
+			// decoding doesn't clear parameters left from the last parse
+			cmd = &GetCommand{}
+			delete(params, "extract_to")
+			So(cmd.ParseParams(params), ShouldBeNil)
+		})
+	})
+}
+
+func TestTaskFilesHandler(t *testing.T) {
+	Convey("With the artifacts plugin hosting a local store", t, func() {
+		root, err := ioutil.TempDir("", "artifacts")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		ap := &ArtifactsPlugin{}
+		So(ap.Configure(map[string]interface{}{"local_dir": root}), ShouldBeNil)
+		handler := ap.GetAPIHandler()
+		testTask := &task.Task{Id: "t1", Project: "proj", Secret: "shh"}
+
+		request := func(method, path, secret string, body []byte) *httptest.ResponseRecorder {
+			r, err := http.NewRequest(method, path, bytes.NewReader(body))
+			So(err, ShouldBeNil)
+			r.Header.Set(evergreen.TaskSecretHeader, secret)
+			plugin.SetTask(r, testTask)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			return w
+		}
+
+		Convey("a file posted by the task should be stored under its project", func() {
+			w := request("POST", "/files/out.tgz", "shh", []byte("contents"))
+			So(w.Code, ShouldEqual, http.StatusOK)
+			file := artifact.File{}
+			So(json.Unmarshal(w.Body.Bytes(), &file), ShouldBeNil)
+			So(file.Store, ShouldEqual, "local")
+			So(file.Path, ShouldEqual, "proj/out.tgz")
+			So(file.Link, ShouldEqual, "/plugin/artifacts/files/proj/out.tgz")
+
+			w = request("GET", "/files/out.tgz", "shh", nil)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldEqual, "contents")
+		})
+		Convey("a missing file should not be found", func() {
+			w := request("GET", "/files/missing.tgz", "shh", nil)
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+		Convey("a request with the wrong secret should be rejected", func() {
+			w := request("POST", "/files/out.tgz", "wrong", []byte("contents"))
+			So(w.Code, ShouldEqual, http.StatusConflict)
+		})
+	})
+}
+
+func TestVerifyLocations(t *testing.T) {
+	Convey("When verifying the locations of files attached by a task in a project", t, func() {
+		files := []artifact.File{
+			{Link: "https://s3.amazonaws.com/bucket/dir/a.tgz", Store: "s3", Bucket: "bucket", Path: "dir/a.tgz"},
+			{Link: "https://example.com/a.tgz", Store: "s3", Bucket: "other", Path: "secret.tgz"},
+			{Link: "/plugin/artifacts/files/p1/a.tgz", Store: "local", Path: "p1/a.tgz"},
+			{Link: "/plugin/artifacts/files/p2/a.tgz", Store: "local", Path: "p2/a.tgz"},
+			{Link: "/plugin/artifacts/files/p1/../p2/a.tgz", Store: "local", Path: "p1/../p2/a.tgz"},
+			{Link: "https://example.com/a.tgz", Store: "ftp", Path: "a.tgz"},
+		}
+		VerifyLocations("p1", files)
+
+		Convey("locations matching their links in the project should be kept", func() {
+			So(files[0].Path, ShouldEqual, "dir/a.tgz")
+			So(files[2].Path, ShouldEqual, "p1/a.tgz")
+		})
+		Convey("any other locations should be cleared", func() {
+			for _, i := range []int{1, 3, 4, 5} {
+				So(files[i].Store, ShouldEqual, "")
+				So(files[i].Bucket, ShouldEqual, "")
+				So(files[i].Path, ShouldEqual, "")
+			}
+		})
+	})
+}
+
+func TestFileProject(t *testing.T) {
+	Convey("The project of a file should be the first directory of its path", t, func() {
+		So(fileProject("p1/a.tgz"), ShouldEqual, "p1")
+		So(fileProject("p1/dir/a.tgz"), ShouldEqual, "p1")
+		Convey("unless the path isn't within a project's directory", func() {
+			So(fileProject("a.tgz"), ShouldEqual, "")
+			So(fileProject("/p1/a.tgz"), ShouldEqual, "")
+			So(fileProject("p1/../p2/a.tgz"), ShouldEqual, "")
+		})
+	})
+}
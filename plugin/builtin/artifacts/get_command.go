@@ -0,0 +1,162 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/archive"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/tychoish/grip/slogger"
+)
+
+var (
+	maxGetAttempts = 10
+	getSleep       = 5 * time.Second
+)
+
+// GetCommand gets a file from an artifact store, either writing it to a
+// local file or extracting it as a tarball.
+type GetCommand struct {
+	StoreParams `mapstructure:",squash" plugin:"expand"`
+
+	// RemoteFile is the path of the file in the store.
+	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
+
+	// LocalFile is where the file is written, relative to the working
+	// directory if not absolute.
+	LocalFile string `mapstructure:"local_file" plugin:"expand"`
+
+	// ExtractTo is the directory a tarball is extracted into, as an
+	// alternative to LocalFile.
+	ExtractTo string `mapstructure:"extract_to" plugin:"expand"`
+}
+
+func (gc *GetCommand) Name() string {
+	return ArtifactsGetCmd
+}
+
+func (gc *GetCommand) Plugin() string {
+	return ArtifactsPluginName
+}
+
+// ParseParams decodes and validates the command's parameters.
+func (gc *GetCommand) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, gc); err != nil {
+		return fmt.Errorf("error decoding %v params: %v", gc.Name(), err)
+	}
+	if err := gc.validateParams(); err != nil {
+		return fmt.Errorf("error validating %v params: %v", gc.Name(), err)
+	}
+	return nil
+}
+
+func (gc *GetCommand) validateParams() error {
+	if gc.RemoteFile == "" {
+		return fmt.Errorf("remote_file cannot be blank")
+	}
+	if gc.LocalFile != "" && gc.ExtractTo != "" {
+		return fmt.Errorf("only one of local_file or extract_to can be specified")
+	}
+	if gc.LocalFile == "" && gc.ExtractTo == "" {
+		return fmt.Errorf("one of local_file or extract_to must be specified")
+	}
+	return gc.StoreParams.validate()
+}
+
+// Execute gets the file from the store.
+func (gc *GetCommand) Execute(log plugin.Logger, com plugin.PluginCommunicator,
+	conf *model.TaskConfig, stop chan bool) error {
+
+	if err := plugin.ExpandValues(gc, conf.Expansions); err != nil {
+		return err
+	}
+	if err := gc.validateParams(); err != nil {
+		return fmt.Errorf("expanded params are not valid: %v", err)
+	}
+	if gc.LocalFile != "" && !filepath.IsAbs(gc.LocalFile) {
+		gc.LocalFile = filepath.Join(conf.WorkDir, gc.LocalFile)
+	}
+	if gc.ExtractTo != "" && !filepath.IsAbs(gc.ExtractTo) {
+		gc.ExtractTo = filepath.Join(conf.WorkDir, gc.ExtractTo)
+	}
+
+	s := gc.StoreParams.open(com)
+	errChan := make(chan error)
+	go func() {
+		errChan <- gc.getWithRetry(log, s)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-stop:
+		log.LogExecution(slogger.INFO, "Received signal to terminate execution of artifacts get command")
+		return nil
+	}
+}
+
+// getWithRetry gets the file, retrying failures other than the file not
+// being in the store.
+func (gc *GetCommand) getWithRetry(log plugin.Logger, s store.ArtifactStore) error {
+	log.LogTask(slogger.INFO, "Getting %v from the %v store", gc.RemoteFile, s.Name())
+
+	retriableGet := util.RetriableFunc(
+		func() error {
+			err := gc.get(s)
+			if err == nil {
+				return nil
+			}
+			// there's no use trying again for a file that isn't there
+			if _, ok := err.(store.ErrNotFound); ok {
+				return err
+			}
+			log.LogExecution(slogger.ERROR, "Error getting %v: %v", gc.RemoteFile, err)
+			return util.RetriableError{Failure: err}
+		},
+	)
+	retryFail, err := util.RetryArithmeticBackoff(retriableGet, maxGetAttempts, getSleep)
+	if retryFail || err != nil {
+		return fmt.Errorf("getting %v failed: %v", gc.RemoteFile, err)
+	}
+	return nil
+}
+
+// get writes the file out, or extracts it.
+func (gc *GetCommand) get(s store.ArtifactStore) error {
+	reader, err := s.Get(gc.RemoteFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if gc.LocalFile != "" {
+		if err = os.RemoveAll(gc.LocalFile); err != nil {
+			return fmt.Errorf("error clearing local file %v: %v", gc.LocalFile, err)
+		}
+		file, err := os.Create(gc.LocalFile)
+		if err != nil {
+			return fmt.Errorf("error opening local file %v: %v", gc.LocalFile, err)
+		}
+		defer file.Close()
+		_, err = io.Copy(file, reader)
+		return err
+	}
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return fmt.Errorf("error creating gzip reader for %v: %v", gc.RemoteFile, err)
+	}
+	if err = archive.Extract(tar.NewReader(gzipReader), gc.ExtractTo); err != nil {
+		return fmt.Errorf("error extracting %v to %v: %v", gc.RemoteFile, gc.ExtractTo, err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/tychoish/grip/slogger"
+)
+
+var (
+	maxPutAttempts = 5
+	putSleep       = 5 * time.Second
+)
+
+// PutCommand puts files in an artifact store, and links them to the task.
+type PutCommand struct {
+	StoreParams `mapstructure:",squash" plugin:"expand"`
+
+	// LocalFile is the path to the file to put, relative to the working
+	// directory if not absolute.
+	LocalFile string `mapstructure:"local_file" plugin:"expand"`
+
+	// LocalFilesIncludeFilter is a list of patterns matching the files to
+	// put, as an alternative to LocalFile.
+	LocalFilesIncludeFilter []string `mapstructure:"local_files_include_filter" plugin:"expand"`
+
+	// RemoteFile is where the file is put in the store. It is a prefix when
+	// putting the files matching LocalFilesIncludeFilter.
+	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
+
+	// DisplayName is the name the file is linked with. It is a prefix when
+	// putting the files matching LocalFilesIncludeFilter.
+	DisplayName string `mapstructure:"display_name" plugin:"expand"`
+
+	// Visibility determines who can see the file links in the UI:
+	// "public" (the default), "private" or "none".
+	Visibility string `mapstructure:"visibility" plugin:"expand"`
+
+	// Optional skips the command, rather than failing, when LocalFile
+	// doesn't exist.
+	Optional bool `mapstructure:"optional"`
+}
+
+func (pc *PutCommand) Name() string {
+	return ArtifactsPutCmd
+}
+
+func (pc *PutCommand) Plugin() string {
+	return ArtifactsPluginName
+}
+
+// ParseParams decodes and validates the command's parameters.
+func (pc *PutCommand) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, pc); err != nil {
+		return fmt.Errorf("error decoding %v params: %v", pc.Name(), err)
+	}
+	if err := pc.validateParams(); err != nil {
+		return fmt.Errorf("error validating %v params: %v", pc.Name(), err)
+	}
+	return nil
+}
+
+func (pc *PutCommand) validateParams() error {
+	if pc.LocalFile == "" && len(pc.LocalFilesIncludeFilter) == 0 {
+		return fmt.Errorf("local_file and local_files_include_filter cannot both be blank")
+	}
+	if pc.LocalFile != "" && len(pc.LocalFilesIncludeFilter) != 0 {
+		return fmt.Errorf("local_file and local_files_include_filter cannot both be specified")
+	}
+	if pc.Optional && len(pc.LocalFilesIncludeFilter) != 0 {
+		return fmt.Errorf("cannot use optional upload with local_files_include_filter")
+	}
+	if pc.RemoteFile == "" {
+		return fmt.Errorf("remote_file cannot be blank")
+	}
+	if !util.SliceContains(artifact.ValidVisibilities, pc.Visibility) {
+		return fmt.Errorf("invalid visibility setting: %v", pc.Visibility)
+	}
+	return pc.StoreParams.validate()
+}
+
+// Execute puts the files in the store and attaches links to them to the
+// task.
+func (pc *PutCommand) Execute(log plugin.Logger, com plugin.PluginCommunicator,
+	conf *model.TaskConfig, stop chan bool) error {
+
+	if err := plugin.ExpandValues(pc, conf.Expansions); err != nil {
+		return err
+	}
+	if err := pc.validateParams(); err != nil {
+		return fmt.Errorf("expanded params are not valid: %v", err)
+	}
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- pc.put(log, com, conf.WorkDir)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-stop:
+		log.LogExecution(slogger.INFO, "Received signal to terminate execution of artifacts put command")
+		return nil
+	}
+}
+
+// upload is a file to be put in the store.
+type upload struct {
+	localPath   string
+	remotePath  string
+	displayName string
+}
+
+// uploads lists the files to put.
+func (pc *PutCommand) uploads(workDir string) ([]upload, error) {
+	if workDir == "" {
+		workDir = "."
+	}
+	if len(pc.LocalFilesIncludeFilter) == 0 {
+		localPath := pc.LocalFile
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(workDir, localPath)
+		}
+		displayName := pc.DisplayName
+		if displayName == "" {
+			displayName = filepath.Base(localPath)
+		}
+		return []upload{{localPath, pc.RemoteFile, displayName}}, nil
+	}
+
+	matched, err := util.BuildFileList(workDir, pc.LocalFilesIncludeFilter...)
+	if err != nil {
+		return nil, err
+	}
+	uploads := []upload{}
+	for _, path := range matched {
+		name := filepath.Base(path)
+		uploads = append(uploads, upload{
+			localPath:   path,
+			remotePath:  pc.RemoteFile + name,
+			displayName: pc.DisplayName + name,
+		})
+	}
+	return uploads, nil
+}
+
+// put puts each file in the store, retrying failures, and then attaches
+// them to the task.
+func (pc *PutCommand) put(log plugin.Logger, com plugin.PluginCommunicator, workDir string) error {
+	uploads, err := pc.uploads(workDir)
+	if err != nil {
+		return err
+	}
+	s := pc.StoreParams.open(com)
+
+	files := []*artifact.File{}
+	for _, u := range uploads {
+		if pc.Optional {
+			if _, err := os.Stat(u.localPath); os.IsNotExist(err) {
+				log.LogTask(slogger.INFO, "Skipping missing optional file %v", u.localPath)
+				return nil
+			}
+		}
+		log.LogTask(slogger.INFO, "Putting %v into %v in the %v store", u.localPath, u.remotePath, s.Name())
+
+		var file *artifact.File
+		retriablePut := util.RetriableFunc(
+			func() error {
+				var err error
+				file, err = s.Put(u.localPath, u.remotePath)
+				if err != nil {
+					log.LogExecution(slogger.ERROR, "Error putting %v: %v", u.localPath, err)
+					return util.RetriableError{Failure: err}
+				}
+				return nil
+			},
+		)
+		retryFail, err := util.RetryArithmeticBackoff(retriablePut, maxPutAttempts, putSleep)
+		if retryFail || err != nil {
+			return fmt.Errorf("putting %v failed: %v", u.localPath, err)
+		}
+		file.Name = u.displayName
+		file.Visibility = pc.Visibility
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+	if err := com.PostTaskFiles(files); err != nil {
+		return fmt.Errorf("Attach files failed: %v", err)
+	}
+	log.LogExecution(slogger.INFO, "API attach files call succeeded")
+	return nil
+}
@@ -0,0 +1,153 @@
+package artifacts
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// StoreParams are the parameters that choose the store an artifacts command
+// uses. The local store needs no further parameters; S3 needs credentials
+// and a bucket.
+type StoreParams struct {
+	// Store is the kind of store: "local" (the default) or "s3".
+	Store string `mapstructure:"store" plugin:"expand"`
+
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+	Bucket    string `mapstructure:"bucket" plugin:"expand"`
+
+	// Permissions is the canned ACL applied to files put in S3.
+	Permissions string `mapstructure:"permissions" plugin:"expand"`
+
+	// ContentType is the MIME type of files put in S3.
+	ContentType string `mapstructure:"content_type" plugin:"expand"`
+}
+
+// validate checks that the store is known, and has what it needs.
+func (sp *StoreParams) validate() error {
+	if sp.Store == "" {
+		sp.Store = store.Local
+	}
+	switch sp.Store {
+	case store.Local:
+		return nil
+	case store.S3:
+		if sp.AwsKey == "" {
+			return fmt.Errorf("aws_key cannot be blank")
+		}
+		if sp.AwsSecret == "" {
+			return fmt.Errorf("aws_secret cannot be blank")
+		}
+		if sp.Bucket == "" {
+			return fmt.Errorf("bucket cannot be blank")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid store '%v': must be one of %v", sp.Store, store.ValidStores)
+	}
+}
+
+// open returns the store the parameters choose.
+func (sp *StoreParams) open(com plugin.PluginCommunicator) store.ArtifactStore {
+	if sp.Store == store.S3 {
+		return store.NewS3Store(sp.AwsKey, sp.AwsSecret, sp.Bucket, sp.Permissions, sp.ContentType)
+	}
+	return &serverStore{com}
+}
+
+// serverStore is the agent's way into the local store, through the API
+// server hosting it.
+type serverStore struct {
+	com plugin.PluginCommunicator
+}
+
+func (s *serverStore) Name() string {
+	return store.Local
+}
+
+// fileEndpoint returns the plugin endpoint for the file at the remote path.
+func fileEndpoint(remotePath string) (string, error) {
+	cleaned, err := store.CleanPath(remotePath)
+	if err != nil {
+		return "", err
+	}
+	u := url.URL{Path: FilesEndpoint + "/" + cleaned}
+	return u.EscapedPath(), nil
+}
+
+// responseError describes an unsuccessful response.
+func responseError(resp *http.Response) error {
+	body, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Errorf("unexpected response (%v): %v", resp.StatusCode, string(body))
+}
+
+// Put uploads the local file to the API server, and checks that what the
+// server stored matches it.
+func (s *serverStore) Put(localPath, remotePath string) (*artifact.File, error) {
+	endpoint, err := fileEndpoint(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	sum, _, err := store.FileChecksum(localPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	resp, err := s.com.TaskPostFile(endpoint, f)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+	file := &artifact.File{}
+	if err = util.ReadJSONInto(resp.Body, file); err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if file.SHA256 != sum {
+		return nil, store.ErrChecksumMismatch{Path: remotePath, Expected: sum, Actual: file.SHA256}
+	}
+	return file, nil
+}
+
+// Get downloads the file from the API server.
+func (s *serverStore) Get(remotePath string) (io.ReadCloser, error) {
+	endpoint, err := fileEndpoint(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.com.TaskGetJSON(endpoint)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, store.ErrNotFound{Path: remotePath}
+	default:
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+}
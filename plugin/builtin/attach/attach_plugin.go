@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
 	"github.com/evergreen-ci/evergreen/util"
 )
 
@@ -72,6 +73,17 @@ func stripHiddenFiles(files []artifact.File, pluginUser *user.DBUser) []artifact
 	return publicFiles
 }
 
+// visibleFiles returns the files the user is allowed to see, with private
+// files linked to through short-lived signed links.
+func visibleFiles(files []artifact.File, context plugin.UIContext) []artifact.File {
+	visible := stripHiddenFiles(files, context.User)
+	now := time.Now()
+	for i := range visible {
+		visible[i].Link = store.SignedLink(visible[i], context.Settings.SignedURLs, now)
+	}
+	return visible
+}
+
 // GetPanelConfig returns a plugin.PanelConfig struct representing panels
 // that will be added to the Task and Build pages.
 func (self *AttachPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
@@ -93,7 +105,7 @@ func (self *AttachPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
 					if artifactEntry == nil {
 						return nil, nil
 					}
-					return stripHiddenFiles(artifactEntry.Files, context.User), nil
+					return visibleFiles(artifactEntry.Files, context), nil
 				},
 			},
 			{
@@ -111,7 +123,7 @@ func (self *AttachPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
 					}
 					for i := range taskArtifactFiles {
 						// remove hidden files if the user isn't logged in
-						taskArtifactFiles[i].Files = stripHiddenFiles(taskArtifactFiles[i].Files, context.User)
+						taskArtifactFiles[i].Files = visibleFiles(taskArtifactFiles[i].Files, context)
 					}
 					return taskArtifactFiles, nil
 				},
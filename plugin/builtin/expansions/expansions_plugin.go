@@ -1,11 +1,17 @@
 package expansions
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/tychoish/grip/slogger"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/plugin"
 	"github.com/mitchellh/mapstructure"
@@ -18,6 +24,7 @@ func init() {
 const (
 	ExpansionsPluginName = "expansions"
 	UpdateVarsCmdName    = "update"
+	WriteVarsCmdName     = "write"
 )
 
 // ExpansionsPlugin handles updating expansions in a task at runtime.
@@ -32,7 +39,8 @@ func (self *ExpansionsPlugin) Name() string {
 // from the API server.
 func (self *ExpansionsPlugin) GetAPIHandler() http.Handler {
 	r := http.NewServeMux()
-	r.HandleFunc(fmt.Sprintf("/%v", FetchVarsRoute), FetchVarsHandler) // GET
+	r.HandleFunc(fmt.Sprintf("/%v", FetchVarsRoute), FetchVarsHandler)     // GET
+	r.HandleFunc(fmt.Sprintf("/%v", PrivateVarsRoute), PrivateVarsHandler) // GET
 	r.HandleFunc("/", http.NotFound)
 	return r
 }
@@ -47,6 +55,8 @@ func (self *ExpansionsPlugin) NewCommand(cmdName string) (plugin.Command, error)
 		return &UpdateCommand{}, nil
 	} else if cmdName == FetchVarsCmdname {
 		return &FetchVarsCommand{}, nil
+	} else if cmdName == WriteVarsCmdName {
+		return &WriteCommand{}, nil
 	}
 	return nil, &plugin.ErrUnknownCommand{cmdName}
 }
@@ -61,6 +71,7 @@ type UpdateCommand struct {
 	// Filename for a yaml file containing expansion updates
 	// in the form of
 	//   "expansion_key: expansions_value"
+	// Files ending in .json are read as a JSON object instead.
 	YamlFile string `mapstructure:"file"`
 }
 
@@ -75,6 +86,11 @@ type PutCommandParams struct {
 
 	// Can optionally concat a string to the end of the current value
 	Concat string
+
+	// Can optionally set the value to the standard output of a shell
+	// command, less any trailing newlines. The command is run in the
+	// task's working directory.
+	Command string
 }
 
 func (self *UpdateCommand) Name() string {
@@ -98,14 +114,33 @@ func (self *UpdateCommand) ParseParams(params map[string]interface{}) error {
 			return fmt.Errorf("error parsing '%v' params: key must not be "+
 				"a blank string", self.Name())
 		}
+		if item.Command != "" && (item.Value != "" || item.Concat != "") {
+			return fmt.Errorf("error parsing '%v' params: '%v' cannot have "+
+				"both a command and a value", self.Name(), item.Key)
+		}
 	}
 
 	return nil
 }
 
 func (self *UpdateCommand) ExecuteUpdates(conf *model.TaskConfig) error {
+	return self.executeUpdates(conf, nil)
+}
+
+// executeUpdates applies the updates in order, killing any command whose
+// output is being captured if a value is received on stop.
+func (self *UpdateCommand) executeUpdates(conf *model.TaskConfig, stop chan bool) error {
 	for _, update := range self.Updates {
-		if update.Concat == "" {
+		if update.Command != "" {
+			newValue, err := captureOutput(update.Command, conf, stop)
+			if err == errInterrupted {
+				return err
+			}
+			if err != nil {
+				return fmt.Errorf("error running command for '%v': %v", update.Key, err)
+			}
+			conf.Expansions.Put(update.Key, newValue)
+		} else if update.Concat == "" {
 			newValue, err := conf.Expansions.ExpandString(update.Value)
 
 			if err != nil {
@@ -130,7 +165,12 @@ func (self *UpdateCommand) ExecuteUpdates(conf *model.TaskConfig) error {
 func (self *UpdateCommand) Execute(pluginLogger plugin.Logger,
 	pluginCom plugin.PluginCommunicator, conf *model.TaskConfig, stop chan bool) error {
 
-	err := self.ExecuteUpdates(conf)
+	// the updates are run here rather than in the background, so that the
+	// expansions aren't changed once the command has returned
+	err := self.executeUpdates(conf, stop)
+	if err == errInterrupted {
+		pluginLogger.LogExecution(slogger.INFO, "Received signal to terminate execution of expansions update")
+	}
 	if err != nil {
 		return err
 	}
@@ -143,7 +183,11 @@ func (self *UpdateCommand) Execute(pluginLogger plugin.Logger,
 
 		pluginLogger.LogTask(slogger.INFO, "Updating expansions with keys from file: %v", self.YamlFile)
 		filename := filepath.Join(conf.WorkDir, self.YamlFile)
-		err := conf.Expansions.UpdateFromYaml(filename)
+		if strings.HasSuffix(filename, ".json") {
+			err = conf.Expansions.UpdateFromJSON(filename)
+		} else {
+			err = conf.Expansions.UpdateFromYaml(filename)
+		}
 		if err != nil {
 			return err
 		}
@@ -151,3 +195,65 @@ func (self *UpdateCommand) Execute(pluginLogger plugin.Logger,
 	return nil
 
 }
+
+// errInterrupted is returned when an update is stopped before it finishes.
+var errInterrupted = fmt.Errorf("expansions update interrupted")
+
+// stopWaitTimeout is how long an interrupted command is waited for after it's
+// killed. Processes it started may hold its output open after it exits;
+// they're left to be cleaned up at the end of the task.
+var stopWaitTimeout = 2 * time.Second
+
+// captureOutput runs the shell command, after expanding it, in the task's
+// working directory and returns its standard output. If a value is received
+// on stop, the command is killed and briefly waited for before returning.
+func captureOutput(cmdString string, conf *model.TaskConfig, stop chan bool) (string, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	env := os.Environ()
+	if conf.Task != nil {
+		// mark the command's processes as the task's, like shell.exec does,
+		// so that any left running are killed when the task ends
+		env = append(env, fmt.Sprintf("EVR_TASK_ID=%v", conf.Task.Id))
+		env = append(env, fmt.Sprintf("EVR_AGENT_PID=%v", os.Getpid()))
+	}
+	localCmd := &command.LocalCommand{
+		CmdString:        cmdString,
+		WorkingDirectory: conf.WorkDir,
+		Environment:      env,
+		Stdout:           stdout,
+		Stderr:           stderr,
+	}
+	if err := localCmd.PrepToRun(conf.Expansions); err != nil {
+		return "", err
+	}
+	if err := localCmd.Start(); err != nil {
+		return "", err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- localCmd.Cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-stop:
+		if err = localCmd.Stop(); err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error stopping '%v': %v", cmdString, err)
+		}
+		select {
+		case <-done:
+		case <-time.After(stopWaitTimeout):
+			evergreen.Logger.Logf(slogger.WARN, "Gave up waiting for '%v' to exit after stopping it", cmdString)
+		}
+		return "", errInterrupted
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%v: %v", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
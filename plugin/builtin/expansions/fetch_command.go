@@ -12,6 +12,7 @@ import (
 )
 
 const FetchVarsRoute = "fetch_vars"
+const PrivateVarsRoute = "private_vars"
 const FetchVarsCmdname = "fetch"
 
 type ExpansionVars map[string]string
@@ -83,6 +84,32 @@ func FetchVarsHandler(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// PrivateVarsHandler is an API hook for returning the names of the private
+// variables of a task's project.
+func PrivateVarsHandler(w http.ResponseWriter, r *http.Request) {
+	task := plugin.GetTask(r)
+	if task == nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	projectVars, err := model.FindOneProjectVars(task.Project)
+	if err != nil {
+		message := fmt.Sprintf("Failed to fetch vars for task %v: %v", task.Id, err)
+		evergreen.Logger.Logf(slogger.ERROR, message)
+		http.Error(w, message, http.StatusInternalServerError)
+		return
+	}
+	names := []string{}
+	if projectVars != nil {
+		for name, private := range projectVars.PrivateVars {
+			if private {
+				names = append(names, name)
+			}
+		}
+	}
+	plugin.WriteJSON(w, http.StatusOK, names)
+}
+
 // Execute fetches the expansions from the API server
 func (self *FetchVarsCommand) Execute(pluginLogger plugin.Logger,
 	pluginCom plugin.PluginCommunicator,
@@ -0,0 +1,137 @@
+package expansions
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/tychoish/grip/slogger"
+	"gopkg.in/yaml.v2"
+)
+
+// WriteCommand writes the task's expansions to a yaml file, in the form
+// read by UpdateCommand, so that scripts can use them. The values of the
+// project's private variables are left out.
+type WriteCommand struct {
+	// File is the path, relative to the task's working directory, of the
+	// file to write
+	File string `mapstructure:"file" plugin:"expand"`
+}
+
+func (self *WriteCommand) Name() string {
+	return WriteVarsCmdName
+}
+
+func (self *WriteCommand) Plugin() string {
+	return ExpansionsPluginName
+}
+
+// ParseParams reads in the command's config. Fulfills the Command interface.
+func (self *WriteCommand) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, self); err != nil {
+		return err
+	}
+	if self.File == "" {
+		return fmt.Errorf("error parsing '%v' params: file must not be "+
+			"a blank string", self.Name())
+	}
+	return nil
+}
+
+// Execute writes the expansions to the file. Fulfills the Command interface.
+func (self *WriteCommand) Execute(pluginLogger plugin.Logger,
+	pluginCom plugin.PluginCommunicator, conf *model.TaskConfig, stop chan bool) error {
+
+	if err := plugin.ExpandValues(self, conf.Expansions); err != nil {
+		return err
+	}
+
+	private, err := fetchPrivateVars(pluginLogger, pluginCom)
+	if err != nil {
+		return fmt.Errorf("error fetching private variables: %v", err)
+	}
+
+	out := RedactedExpansions(*conf.Expansions, private)
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(conf.WorkDir, self.File)
+	if err = ioutil.WriteFile(filename, data, 0644); err != nil {
+		return err
+	}
+	pluginLogger.LogTask(slogger.INFO, "Wrote %v expansions to file: %v (%v private left out)",
+		len(out), self.File, len(*conf.Expansions)-len(out))
+	return nil
+}
+
+// minCopiedPrivateLength is the length below which a private value is too
+// likely to turn up in unrelated expansions, e.g. "1" or "true", to be
+// looked for in them.
+const minCopiedPrivateLength = 6
+
+// RedactedExpansions returns the expansions without the private ones. Since
+// a private variable's value may have been copied into other expansions,
+// e.g. by expansions.update or a function's vars, any expansion whose value
+// contains a whole private value is left out too, unless that value is
+// shorter than minCopiedPrivateLength.
+func RedactedExpansions(expansions map[string]string, private []string) map[string]string {
+	hidden := map[string]bool{}
+	values := []string{}
+	for _, name := range private {
+		hidden[name] = true
+		if value := expansions[name]; len(value) >= minCopiedPrivateLength {
+			values = append(values, value)
+		}
+	}
+	out := map[string]string{}
+	for key, value := range expansions {
+		if !hidden[key] && !containsAny(value, values) {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// containsAny returns whether s contains any of the substrings.
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchPrivateVars gets the names of the project's private variables from
+// the API server.
+func fetchPrivateVars(log plugin.Logger, com plugin.PluginCommunicator) ([]string, error) {
+	var names []string
+	retriableGet := util.RetriableFunc(
+		func() error {
+			resp, err := com.TaskGetJSON(PrivateVarsRoute)
+			if resp != nil {
+				defer resp.Body.Close()
+			}
+			if err != nil {
+				log.LogExecution(slogger.WARN, "Error connecting to API server: %v", err)
+				return util.RetriableError{Failure: err}
+			}
+			if resp.StatusCode != http.StatusOK {
+				log.LogExecution(slogger.WARN, "Unexpected status code %v, retrying", resp.StatusCode)
+				return util.RetriableError{Failure: fmt.Errorf("Unexpected status code %v", resp.StatusCode)}
+			}
+			return util.ReadJSONInto(resp.Body, &names)
+		})
+
+	_, err := util.RetryArithmeticBackoff(retriableGet, 5, 5*time.Second)
+	return names, err
+}
@@ -7,15 +7,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen/archive"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/plugin"
-	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/store"
 	"github.com/evergreen-ci/evergreen/util"
-	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -48,6 +48,11 @@ type S3GetCommand struct {
 	// downloaded to the specified directory.
 	LocalFile string `mapstructure:"local_file" plugin:"expand"`
 	ExtractTo string `mapstructure:"extract_to" plugin:"expand"`
+
+	// SHA256 is the checksum the file is verified against, if set. Files
+	// put by s3.put are verified against the checksum recorded with them
+	// regardless.
+	SHA256 string `mapstructure:"sha256" plugin:"expand"`
 }
 
 func (self *S3GetCommand) Name() string {
@@ -196,21 +201,13 @@ func (self *S3GetCommand) GetWithRetry(pluginLogger plugin.Logger) error {
 // Fetch the specified resource from s3.
 func (self *S3GetCommand) Get() error {
 
-	// get the appropriate session and bucket
-	auth := &aws.Auth{
-		AccessKey: self.AwsKey,
-		SecretKey: self.AwsSecret,
-	}
-
-	session := thirdparty.NewS3Session(auth, aws.USEast)
-	bucket := session.Bucket(self.Bucket)
-
-	// get a reader for the bucket
-	reader, err := bucket.GetReader(self.RemoteFile)
+	// get a reader for the file in the bucket
+	bucket := store.NewS3Store(self.AwsKey, self.AwsSecret, self.Bucket, "", "")
+	reader, err := bucket.Get(self.RemoteFile)
 	if err != nil {
-		return fmt.Errorf("error getting bucket reader for file %v: %v",
-			self.RemoteFile, err)
+		return err
 	}
+	reader = store.NewVerifyingReader(reader, self.RemoteFile, strings.ToLower(self.SHA256))
 	defer reader.Close()
 
 	// either untar the remote, or just write to a file
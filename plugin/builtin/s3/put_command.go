@@ -3,18 +3,19 @@ package s3
 import (
 	"errors"
 	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
-	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -23,7 +24,16 @@ var (
 	s3PutSleep                 = 5 * time.Second
 	attachResultsPostRetries   = 5
 	attachResultsRetrySleepSec = 10 * time.Second
-	s3baseURL                  = "https://s3.amazonaws.com/"
+)
+
+const (
+	// defaultPartSizeMB is the size, in megabytes, of the parts large files
+	// are uploaded in if part_size_mb isn't set.
+	defaultPartSizeMB = 100
+
+	// defaultMaxParallel is how many files are uploaded at once if
+	// max_parallel isn't set.
+	defaultMaxParallel = 4
 )
 
 var errSkippedFile = errors.New("missing optional file was skipped")
@@ -85,6 +95,40 @@ type S3PutCommand struct {
 	// the path specified in local_file does not exist. Defaults to false, which triggers errors
 	// for missing files.
 	Optional bool `mapstructure:"optional"`
+
+	// PartSizeMB is the size, in megabytes, of the parts that larger files
+	// are uploaded in. Each part is retried on its own, and a failed upload
+	// resumes from the parts already uploaded, until the command gives up
+	// and aborts it. Must be at least 5; defaults to 100.
+	PartSizeMB int `mapstructure:"part_size_mb"`
+
+	// MaxParallel is the most files matching LocalFilesIncludeFilter that are
+	// uploaded at once. Defaults to 4.
+	MaxParallel int `mapstructure:"max_parallel"`
+
+	// uploaded describes the file put, for attaching it to the task, when
+	// a single file is put
+	uploaded *artifact.File
+
+	// done holds the local paths of the files already put, so that retrying
+	// the command doesn't upload them again
+	done map[string]bool
+
+	// transfers holds the files put since they were last logged
+	transfers []transfer
+
+	// unfinished holds the remote paths of the files the last put failed
+	// on, whose uploads are aborted if the command gives up
+	unfinished []string
+}
+
+// transfer is the outcome of putting a single file.
+type transfer struct {
+	path     string
+	remote   string
+	file     *artifact.File
+	duration time.Duration
+	err      error
 }
 
 func (s3pc *S3PutCommand) Name() string {
@@ -135,6 +179,12 @@ func (s3pc *S3PutCommand) validateParams() error {
 	if !util.SliceContains(artifact.ValidVisibilities, s3pc.Visibility) {
 		return fmt.Errorf("invalid visibility setting: %v", s3pc.Visibility)
 	}
+	if s3pc.PartSizeMB < 0 || (s3pc.PartSizeMB > 0 && s3pc.PartSizeMB*1024*1024 < thirdparty.MinS3PartSize) {
+		return fmt.Errorf("part_size_mb must be at least %v", thirdparty.MinS3PartSize/(1024*1024))
+	}
+	if s3pc.MaxParallel < 0 {
+		return fmt.Errorf("max_parallel cannot be negative")
+	}
 
 	// make sure the bucket is valid
 	if err := validateS3BucketName(s3pc.Bucket); err != nil {
@@ -225,6 +275,7 @@ func (s3pc *S3PutCommand) PutWithRetry(log plugin.Logger, com plugin.PluginCommu
 	retriablePut := util.RetriableFunc(
 		func() error {
 			err := s3pc.Put()
+			s3pc.logTransfers(log)
 			if err != nil {
 				if err == errSkippedFile {
 					return err
@@ -243,12 +294,49 @@ func (s3pc *S3PutCommand) PutWithRetry(log plugin.Logger, com plugin.PluginCommu
 	}
 	if retryFail {
 		log.LogExecution(slogger.ERROR, "S3 put failed with error: %v", err)
+		s3pc.abortUnfinished(log)
 		return err
 	}
 	return s3pc.AttachTaskFiles(log, com)
 }
 
-// Put the specified resource to s3.
+// abortUnfinished discards the parts uploaded for the files the last put
+// failed on, so that S3 doesn't keep them once the command has given up.
+func (s3pc *S3PutCommand) abortUnfinished(log plugin.Logger) {
+	bucket := s3pc.store()
+	for _, remoteName := range s3pc.unfinished {
+		if err := bucket.AbortPut(remoteName); err != nil {
+			log.LogExecution(slogger.WARN, "Error cleaning up failed s3 put: %v", err)
+		}
+	}
+	s3pc.unfinished = nil
+}
+
+// store returns the bucket to put files in.
+func (s3pc *S3PutCommand) store() *store.S3Store {
+	bucket := store.NewS3Store(s3pc.AwsKey, s3pc.AwsSecret, s3pc.Bucket, s3pc.Permissions, s3pc.ContentType)
+	partSizeMB := s3pc.PartSizeMB
+	if partSizeMB == 0 {
+		partSizeMB = defaultPartSizeMB
+	}
+	bucket.PartSize = int64(partSizeMB) * 1024 * 1024
+	return bucket
+}
+
+// workers returns how many files to upload at once.
+func (s3pc *S3PutCommand) workers(files int) int {
+	workers := s3pc.MaxParallel
+	if workers == 0 {
+		workers = defaultMaxParallel
+	}
+	if workers > files {
+		workers = files
+	}
+	return workers
+}
+
+// Put the specified resource to s3. Files matching the include filter are
+// put in parallel, and files put by an earlier call aren't put again.
 func (s3pc *S3PutCommand) Put() error {
 	var filesList []string
 
@@ -261,33 +349,75 @@ func (s3pc *S3PutCommand) Put() error {
 			return err
 		}
 	}
+	if s3pc.done == nil {
+		s3pc.done = map[string]bool{}
+	}
+
+	pending := make(chan string, len(filesList))
 	for _, fpath := range filesList {
-		remoteName := s3pc.RemoteFile
-		if s3pc.isMulti() {
-			fname := filepath.Base(fpath)
-			remoteName = fmt.Sprintf("%s%s", s3pc.RemoteFile, fname)
+		if !s3pc.done[fpath] {
+			pending <- fpath
 		}
+	}
+	close(pending)
+
+	bucket := s3pc.store()
+	results := make(chan transfer, len(filesList))
+	wg := &sync.WaitGroup{}
+	for i := 0; i < s3pc.workers(len(pending)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fpath := range pending {
+				remoteName := s3pc.RemoteFile
+				if s3pc.isMulti() {
+					fname := filepath.Base(fpath)
+					remoteName = fmt.Sprintf("%s%s", s3pc.RemoteFile, fname)
+				}
+				start := time.Now()
+				file, err := bucket.Put(fpath, remoteName)
+				results <- transfer{path: fpath, remote: remoteName, file: file, duration: time.Since(start), err: err}
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
 
-		auth := &aws.Auth{
-			AccessKey: s3pc.AwsKey,
-			SecretKey: s3pc.AwsSecret,
-		}
-		s3URL := url.URL{
-			Scheme: "s3",
-			Host:   s3pc.Bucket,
-			Path:   remoteName,
-		}
-		err := thirdparty.PutS3File(auth, fpath, s3URL.String(), s3pc.ContentType, s3pc.Permissions)
-		if err != nil {
+	s3pc.unfinished = nil
+	for result := range results {
+		if result.err != nil {
+			s3pc.unfinished = append(s3pc.unfinished, result.remote)
 			if !s3pc.isMulti() {
-				if s3pc.Optional && os.IsNotExist(err) {
+				if s3pc.Optional && os.IsNotExist(result.err) {
 					return errSkippedFile
 				}
 			}
-			return err
+			if err == nil {
+				err = fmt.Errorf("error putting %v: %v", result.path, result.err)
+			}
+			continue
 		}
+		s3pc.done[result.path] = true
+		if !s3pc.isMulti() {
+			s3pc.uploaded = result.file
+		}
+		s3pc.transfers = append(s3pc.transfers, result)
 	}
-	return nil
+	return err
+}
+
+// logTransfers reports the size and throughput of the files put since it was
+// last called.
+func (s3pc *S3PutCommand) logTransfers(log plugin.Logger) {
+	for _, t := range s3pc.transfers {
+		rate := "-"
+		if seconds := t.duration.Seconds(); seconds > 0 {
+			rate = humanize.Bytes(uint64(float64(t.file.Size)/seconds)) + "/s"
+		}
+		log.LogTask(slogger.INFO, "Put %v (%v) in %v (%v)",
+			t.path, humanize.Bytes(uint64(t.file.Size)), t.duration, rate)
+	}
+	s3pc.transfers = nil
 }
 
 // AttachTaskFiles is responsible for sending the
@@ -296,7 +426,7 @@ func (s3pc *S3PutCommand) AttachTaskFiles(log plugin.Logger,
 	com plugin.PluginCommunicator) error {
 
 	remoteFile := filepath.ToSlash(s3pc.RemoteFile)
-	fileLink := s3baseURL + s3pc.Bucket + "/" + remoteFile
+	fileLink := store.S3BaseURL + s3pc.Bucket + "/" + remoteFile
 
 	displayName := s3pc.DisplayName
 	if displayName == "" {
@@ -306,6 +436,15 @@ func (s3pc *S3PutCommand) AttachTaskFiles(log plugin.Logger,
 		Name:       displayName,
 		Link:       fileLink,
 		Visibility: s3pc.Visibility,
+		Store:      store.S3,
+		Bucket:     s3pc.Bucket,
+		Path:       remoteFile,
+	}
+	// the entry for a prefix doesn't describe any one of the files under it
+	if s3pc.uploaded != nil && !s3pc.isMulti() {
+		file.Size = s3pc.uploaded.Size
+		file.SHA256 = s3pc.uploaded.SHA256
+		file.ContentType = s3pc.uploaded.ContentType
 	}
 
 	err := com.PostTaskFiles([]*artifact.File{file})
@@ -218,6 +218,60 @@ func TestS3PutValidateParams(t *testing.T) {
 				So(cmd.DisplayName, ShouldEqual, params["display_name"])
 
 			})
+
+			Convey("a part size smaller than S3 allows should cause an error", func() {
+
+				params := map[string]interface{}{
+					"aws_key":      "key",
+					"aws_secret":   "secret",
+					"local_file":   "local",
+					"remote_file":  "remote",
+					"bucket":       "bck",
+					"permissions":  "public-read",
+					"content_type": "application/x-tar",
+					"part_size_mb": 1,
+				}
+				So(cmd.ParseParams(params), ShouldNotBeNil)
+				So(cmd.validateParams(), ShouldNotBeNil)
+			})
+
+			Convey("a negative parallel upload limit should cause an error", func() {
+
+				params := map[string]interface{}{
+					"aws_key":                    "key",
+					"aws_secret":                 "secret",
+					"local_files_include_filter": []string{"local"},
+					"remote_file":                "remote",
+					"bucket":                     "bck",
+					"permissions":                "public-read",
+					"content_type":               "application/x-tar",
+					"max_parallel":               -1,
+				}
+				So(cmd.ParseParams(params), ShouldNotBeNil)
+				So(cmd.validateParams(), ShouldNotBeNil)
+			})
+
+			Convey("a valid part size and parallel upload limit should be"+
+				" parsed", func() {
+
+				params := map[string]interface{}{
+					"aws_key":                    "key",
+					"aws_secret":                 "secret",
+					"local_files_include_filter": []string{"local"},
+					"remote_file":                "remote",
+					"bucket":                     "bck",
+					"permissions":                "public-read",
+					"content_type":               "application/x-tar",
+					"part_size_mb":               64,
+					"max_parallel":               8,
+				}
+				So(cmd.ParseParams(params), ShouldBeNil)
+				So(cmd.PartSizeMB, ShouldEqual, 64)
+				So(cmd.MaxParallel, ShouldEqual, 8)
+				So(cmd.store().PartSize, ShouldEqual, 64*1024*1024)
+				So(cmd.workers(20), ShouldEqual, 8)
+				So(cmd.workers(3), ShouldEqual, 3)
+			})
 		})
 
 	})
@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/store"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/goamz/goamz/aws"
@@ -29,7 +30,6 @@ const (
 	s3CopyCmd         = "copy"
 	s3CopyPluginName  = "s3Copy"
 	s3CopyAPIEndpoint = "s3Copy"
-	s3baseURL         = "https://s3.amazonaws.com/"
 
 	s3CopyRetrySleepTimeSec = 5
 	s3CopyRetryNumRetries   = 5
@@ -388,7 +388,7 @@ func (c *S3CopyCommand) AttachTaskFiles(pluginLogger plugin.Logger,
 	pluginCom plugin.PluginCommunicator, request S3CopyRequest) error {
 
 	remotePath := filepath.ToSlash(request.S3DestinationPath)
-	fileLink := s3baseURL + request.S3DestinationBucket + "/" + remotePath
+	fileLink := store.S3BaseURL + request.S3DestinationBucket + "/" + remotePath
 
 	displayName := request.S3DisplayName
 
@@ -398,8 +398,11 @@ func (c *S3CopyCommand) AttachTaskFiles(pluginLogger plugin.Logger,
 
 	pluginLogger.LogExecution(slogger.INFO, "attaching file with name %v", displayName)
 	file := artifact.File{
-		Name: displayName,
-		Link: fileLink,
+		Name:   displayName,
+		Link:   fileLink,
+		Store:  store.S3,
+		Bucket: request.S3DestinationBucket,
+		Path:   remotePath,
 	}
 
 	files := []*artifact.File{&file}
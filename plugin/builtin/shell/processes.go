@@ -0,0 +1,222 @@
+package shell
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+)
+
+const (
+	// MaxRecordedProcesses is the most processes recorded for a task, so that
+	// tasks that start many short-lived processes don't bloat their reports.
+	MaxRecordedProcesses = 200
+
+	// processScanInterval is how often the processes spawned under a task are
+	// looked for, on platforms that support it.
+	processScanInterval = 5 * time.Second
+
+	// maxCommandLength is how much of a process's command line is recorded.
+	maxCommandLength = 200
+)
+
+// observedProcess is a process found running under a task.
+type observedProcess struct {
+	pid     int
+	command string
+	peakRSS int64
+}
+
+// taskProcesses holds the processes recorded for a single task.
+type taskProcesses struct {
+	report apimodels.ProcessReport
+	// running maps the pids of processes that haven't been seen to end to
+	// their index in the report
+	running map[int]int
+	// owned holds the pids of running processes that the agent started
+	owned map[int]bool
+	stop  chan struct{}
+}
+
+// processRecorder records the processes spawned under each task that the
+// agent runs.
+type processRecorder struct {
+	sync.Mutex
+	tasks map[string]*taskProcesses
+}
+
+var processes = &processRecorder{tasks: map[string]*taskProcesses{}}
+
+// forTask returns the processes recorded for the task, starting to look for
+// the task's processes in the background the first time. The caller must
+// hold the lock.
+func (r *processRecorder) forTask(taskId string) *taskProcesses {
+	tp, ok := r.tasks[taskId]
+	if !ok {
+		tp = &taskProcesses{running: map[int]int{}, owned: map[int]bool{}, stop: make(chan struct{})}
+		r.tasks[taskId] = tp
+		go r.watch(taskId, tp.stop)
+	}
+	return tp
+}
+
+// add records a newly seen process, unless the task has too many already.
+// The caller must hold the lock.
+func (tp *taskProcesses) add(info apimodels.ProcessInfo) {
+	if len(tp.report.Processes) >= MaxRecordedProcesses {
+		tp.report.Dropped++
+		return
+	}
+	tp.running[info.Pid] = len(tp.report.Processes)
+	tp.report.Processes = append(tp.report.Processes, info)
+}
+
+// started records a process that the agent started for the task.
+func (r *processRecorder) started(taskId string, pid int, command string, startTime time.Time) {
+	r.Lock()
+	defer r.Unlock()
+	tp := r.forTask(taskId)
+	tp.owned[pid] = true
+	tp.add(apimodels.ProcessInfo{
+		Pid:       pid,
+		Command:   truncateCommand(command),
+		StartTime: startTime,
+	})
+}
+
+// exited records the end of a process that the agent started for the task.
+func (r *processRecorder) exited(taskId string, pid int, exitCode int, peakRSS int64, endTime time.Time) {
+	r.Lock()
+	defer r.Unlock()
+	tp, ok := r.tasks[taskId]
+	if !ok {
+		return
+	}
+	delete(tp.owned, pid)
+	i, ok := tp.running[pid]
+	if !ok {
+		return
+	}
+	delete(tp.running, pid)
+	info := &tp.report.Processes[i]
+	info.EndTime = endTime
+	info.ExitCode = &exitCode
+	if peakRSS > info.PeakRSS {
+		info.PeakRSS = peakRSS
+	}
+}
+
+// observed updates the task's processes with the ones found running at the
+// given time. New processes are recorded, and running ones that weren't
+// found are taken to have ended.
+func (r *processRecorder) observed(taskId string, found []observedProcess, now time.Time) {
+	r.Lock()
+	defer r.Unlock()
+	tp, ok := r.tasks[taskId]
+	if !ok {
+		return
+	}
+	alive := map[int]bool{}
+	for _, p := range found {
+		alive[p.pid] = true
+		if i, ok := tp.running[p.pid]; ok {
+			if p.peakRSS > tp.report.Processes[i].PeakRSS {
+				tp.report.Processes[i].PeakRSS = p.peakRSS
+			}
+			continue
+		}
+		tp.add(apimodels.ProcessInfo{
+			Pid:       p.pid,
+			Command:   truncateCommand(p.command),
+			StartTime: now,
+			PeakRSS:   p.peakRSS,
+		})
+	}
+	for pid, i := range tp.running {
+		// processes the agent started are ended when it waits on them
+		if alive[pid] || tp.owned[pid] {
+			continue
+		}
+		tp.report.Processes[i].EndTime = now
+		delete(tp.running, pid)
+	}
+}
+
+// killed records a process that was still running at the end of the task.
+func (r *processRecorder) killed(taskId string, p observedProcess, now time.Time) {
+	r.Lock()
+	defer r.Unlock()
+	tp := r.tasks[taskId]
+	if tp == nil {
+		// the process was never seen, e.g. because tracking was off
+		tp = &taskProcesses{running: map[int]int{}, owned: map[int]bool{}}
+		r.tasks[taskId] = tp
+	}
+	info := apimodels.ProcessInfo{
+		Pid:     p.pid,
+		Command: truncateCommand(p.command),
+		PeakRSS: p.peakRSS,
+		EndTime: now,
+	}
+	if i, ok := tp.running[p.pid]; ok {
+		recorded := &tp.report.Processes[i]
+		recorded.EndTime = now
+		if p.peakRSS > recorded.PeakRSS {
+			recorded.PeakRSS = p.peakRSS
+		}
+		if info.Command == "" {
+			info.Command = recorded.Command
+		}
+		info.StartTime = recorded.StartTime
+		delete(tp.running, p.pid)
+		delete(tp.owned, p.pid)
+	}
+	tp.report.Leftovers = append(tp.report.Leftovers, info)
+}
+
+// watch periodically looks for the processes spawned under a task until the
+// task's processes are collected.
+func (r *processRecorder) watch(taskId string, stop <-chan struct{}) {
+	ticker := time.NewTicker(processScanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			found, err := findProcesses(taskId)
+			if err != nil || found == nil {
+				// nothing to be learned on this platform
+				continue
+			}
+			r.observed(taskId, found, now)
+		}
+	}
+}
+
+// TaskProcesses returns what is known about the processes spawned under a
+// task, including the ones killed when cleaning up after it, and stops
+// recording them. Returns nil if no processes were recorded.
+func TaskProcesses(taskId string) *apimodels.ProcessReport {
+	processes.Lock()
+	defer processes.Unlock()
+	tp, ok := processes.tasks[taskId]
+	if !ok {
+		return nil
+	}
+	delete(processes.tasks, taskId)
+	if tp.stop != nil {
+		close(tp.stop)
+	}
+	return &tp.report
+}
+
+// truncateCommand shortens a command line to what is worth reporting.
+func truncateCommand(command string) string {
+	command = strings.TrimSpace(command)
+	if len(command) > maxCommandLength {
+		return command[:maxCommandLength] + "..."
+	}
+	return command
+}
@@ -0,0 +1,85 @@
+package shell
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessRecorder(t *testing.T) {
+	Convey("With a process recorder", t, func() {
+		r := &processRecorder{tasks: map[string]*taskProcesses{}}
+		taskId := "t1"
+		start := time.Now()
+		r.started(taskId, 100, "sh: make", start)
+		defer close(r.tasks[taskId].stop)
+
+		Convey("a process the agent started should be recorded with its exit"+
+			" code and memory", func() {
+			r.exited(taskId, 100, 2, 1024, start.Add(time.Minute))
+			report := r.tasks[taskId].report
+			So(len(report.Processes), ShouldEqual, 1)
+			So(report.Processes[0].Command, ShouldEqual, "sh: make")
+			So(*report.Processes[0].ExitCode, ShouldEqual, 2)
+			So(report.Processes[0].PeakRSS, ShouldEqual, 1024)
+			So(report.Processes[0].EndTime, ShouldResemble, start.Add(time.Minute))
+		})
+
+		Convey("descendants should be recorded when seen and ended when gone", func() {
+			r.observed(taskId, []observedProcess{
+				{pid: 100, command: "sh"},
+				{pid: 101, command: "gcc main.c", peakRSS: 500},
+			}, start.Add(time.Second))
+			r.observed(taskId, []observedProcess{
+				{pid: 101, command: "gcc main.c", peakRSS: 800},
+			}, start.Add(2*time.Second))
+			r.observed(taskId, []observedProcess{}, start.Add(3*time.Second))
+
+			report := r.tasks[taskId].report
+			So(len(report.Processes), ShouldEqual, 2)
+			gcc := report.Processes[1]
+			So(gcc.Pid, ShouldEqual, 101)
+			So(gcc.StartTime, ShouldResemble, start.Add(time.Second))
+			So(gcc.EndTime, ShouldResemble, start.Add(3*time.Second))
+			So(gcc.PeakRSS, ShouldEqual, 800)
+			So(gcc.ExitCode, ShouldBeNil)
+
+			Convey("but the agent's own processes should wait for their exit", func() {
+				So(report.Processes[0].EndTime.IsZero(), ShouldBeTrue)
+			})
+		})
+
+		Convey("processes killed at the end of the task should be reported as"+
+			" leftovers", func() {
+			r.observed(taskId, []observedProcess{{pid: 102, command: "mongod"}}, start.Add(time.Second))
+			r.killed(taskId, observedProcess{pid: 102}, start.Add(time.Hour))
+			report := r.tasks[taskId].report
+			So(len(report.Leftovers), ShouldEqual, 1)
+			So(report.Leftovers[0].Command, ShouldEqual, "mongod")
+			So(report.Leftovers[0].StartTime, ShouldResemble, start.Add(time.Second))
+			So(report.Processes[1].EndTime, ShouldResemble, start.Add(time.Hour))
+		})
+
+		Convey("processes past the limit should only be counted", func() {
+			found := []observedProcess{}
+			for pid := 1000; pid < 1000+MaxRecordedProcesses; pid++ {
+				found = append(found, observedProcess{pid: pid})
+			}
+			r.observed(taskId, found, start.Add(time.Second))
+			report := r.tasks[taskId].report
+			So(len(report.Processes), ShouldEqual, MaxRecordedProcesses)
+			So(report.Dropped, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestTaskProcesses(t *testing.T) {
+	Convey("Collecting a task's processes should stop recording them", t, func() {
+		processes.started("t2", 100, "sh", time.Now())
+		report := TaskProcesses("t2")
+		So(report, ShouldNotBeNil)
+		So(len(report.Processes), ShouldEqual, 1)
+		So(TaskProcesses("t2"), ShouldBeNil)
+	})
+}
@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model"
@@ -194,7 +197,9 @@ func (sec *ShellExecCommand) Execute(pluginLogger plugin.Logger,
 		localCmd.Environment = env
 		err = localCmd.Start()
 		if err == nil {
-			pluginLogger.LogSystem(slogger.DEBUG, "spawned shell process with pid %v", localCmd.Cmd.Process.Pid)
+			pid := localCmd.Cmd.Process.Pid
+			pluginLogger.LogSystem(slogger.DEBUG, "spawned shell process with pid %v", pid)
+			processes.started(conf.Task.Id, pid, sec.describe(localCmd), time.Now())
 
 			// Call the platform's process-tracking function. On some OSes this will be a noop,
 			// on others this may need to do some additional work to track the process so that
@@ -208,6 +213,9 @@ func (sec *ShellExecCommand) Execute(pluginLogger plugin.Logger,
 
 			if !sec.Background {
 				err = localCmd.Cmd.Wait()
+				if state := localCmd.Cmd.ProcessState; state != nil {
+					processes.exited(conf.Task.Id, pid, exitCode(state), peakRSS(state), time.Now())
+				}
 			}
 		} else {
 			pluginLogger.LogSystem(slogger.DEBUG, "error spawning shell process: %v", err)
@@ -248,6 +256,35 @@ func (sec *ShellExecCommand) Execute(pluginLogger plugin.Logger,
 	return nil
 }
 
+// describe returns the command line to record for the shell process, which
+// includes the first line of the script, before expansion so that no secrets
+// are recorded, unless the script is hidden.
+func (sec *ShellExecCommand) describe(localCmd *command.LocalCommand) string {
+	if sec.Silent {
+		return localCmd.Shell
+	}
+	script := strings.TrimSpace(sec.Script)
+	if i := strings.Index(script, "\n"); i >= 0 {
+		script = script[:i] + " ..."
+	}
+	return fmt.Sprintf("%v: %v", localCmd.Shell, script)
+}
+
+// exitCode returns the exit code of a process that the agent waited on. As in
+// the shell, a process killed by a signal exits with 128 plus the signal.
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok {
+		if status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return status.ExitStatus()
+	}
+	if state.Success() {
+		return 0
+	}
+	return 1
+}
+
 // listProc() returns a list of active pids on the system, by listing the contents of /proc
 // and looking for entries that appear to be valid pids. Only usable on systems with a /proc
 // filesystem (Solaris and UNIX/Linux)
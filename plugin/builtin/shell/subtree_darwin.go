@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen/plugin"
@@ -22,6 +25,21 @@ func trackProcess(key string, pid int, log plugin.Logger) {
 	// cleanup() and we don't need to do any special bookkeeping up-front.
 }
 
+// findProcesses is a noop on OSX, where processes are only looked for when
+// cleaning up after a task.
+func findProcesses(key string) ([]observedProcess, error) {
+	return nil, nil
+}
+
+// peakRSS returns the most memory, in bytes, that a process the agent waited
+// on had resident. OSX reports it in bytes.
+func peakRSS(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return usage.Maxrss
+	}
+	return 0
+}
+
 func cleanup(key string, log plugin.Logger) error {
 	/*
 		Usage of ps on OSX for extracting environment variables:
@@ -41,7 +59,7 @@ func cleanup(key string, log plugin.Logger) error {
 	}
 	myPid := fmt.Sprintf("%v", os.Getpid())
 
-	pidsToKill := []int{}
+	toKill := []observedProcess{}
 	lines := strings.Split(string(out), "\n")
 
 	// Look through the output of the "ps" command and find the processes we need to kill.
@@ -69,20 +87,32 @@ func cleanup(key string, log plugin.Logger) error {
 			continue
 		}
 
-		pidsToKill = append(pidsToKill, pidAsInt)
+		toKill = append(toKill, observedProcess{pid: pidAsInt, command: psCommand(line)})
 	}
 
 	// Iterate through the list of processes to kill that we just built, and actually kill them.
-	for _, pid := range pidsToKill {
+	for _, proc := range toKill {
 		p := os.Process{}
-		p.Pid = pid
+		p.Pid = proc.pid
 		err := p.Kill()
 		if err != nil {
-			log.LogSystem(slogger.ERROR, "Cleanup got error killing pid %v: %v", pid, err)
+			log.LogSystem(slogger.ERROR, "Cleanup got error killing pid %v: %v", proc.pid, err)
 		} else {
-			log.LogSystem(slogger.INFO, "Cleanup killed pid %v", pid)
+			log.LogSystem(slogger.INFO, "Cleanup killed pid %v", proc.pid)
+			processes.killed(key, proc, time.Now())
 		}
 	}
 	return nil
 
 }
+
+// psCommand extracts the executable name from a line of 'ps -E' output. The
+// rest of the command line is left out, since expansions with secrets in
+// them may have been passed as arguments.
+func psCommand(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ""
+	}
+	return filepath.Base(fields[1])
+}
@@ -5,6 +5,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen/plugin"
@@ -37,26 +42,83 @@ func getEnv(pid int) ([]string, error) {
 	return results, nil
 }
 
-func cleanup(key string, log plugin.Logger) error {
+// findProcesses returns the processes running under the given task, with
+// their command lines and the most memory they have had resident.
+func findProcesses(key string) ([]observedProcess, error) {
 	pids, err := listProc()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	pidMarker := fmt.Sprintf("EVR_AGENT_PID=%v", os.Getpid())
 	taskMarker := fmt.Sprintf("EVR_TASK_ID=%v", key)
+	found := []observedProcess{}
 	for _, pid := range pids {
 		env, err := getEnv(pid)
 		if err != nil {
 			continue
 		}
 		if envHasMarkers(env, pidMarker, taskMarker) {
-			p := os.Process{}
-			p.Pid = pid
-			if err := p.Kill(); err != nil {
-				log.LogTask(slogger.INFO, "Killing %v failed: %v", pid, err)
-			} else {
-				log.LogTask(slogger.INFO, "Killed process %v", pid)
+			found = append(found, describeProcess(pid))
+		}
+	}
+	return found, nil
+}
+
+// describeProcess reads a process's executable name and peak resident memory
+// from /proc. Either may be missing if the process has already exited. The
+// rest of the command line is left out, since expansions with secrets in
+// them may have been passed as arguments.
+func describeProcess(pid int) observedProcess {
+	p := observedProcess{pid: pid}
+	if cmdline, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+		if argv := bytes.Split(cmdline, []byte{0}); len(argv[0]) > 0 {
+			p.command = filepath.Base(string(argv[0]))
+		}
+	}
+	if status, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/status", pid)); err == nil {
+		p.peakRSS = parsePeakRSS(string(status))
+	}
+	return p
+}
+
+// parsePeakRSS reads the VmHWM line, the peak resident set size in kB, from
+// the contents of /proc/$PID/status and returns it in bytes.
+func parsePeakRSS(status string) int64 {
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "VmHWM:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0
 			}
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// peakRSS returns the most memory, in bytes, that a process the agent waited
+// on had resident. Linux reports it in kB.
+func peakRSS(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return usage.Maxrss * 1024
+	}
+	return 0
+}
+
+func cleanup(key string, log plugin.Logger) error {
+	found, err := findProcesses(key)
+	if err != nil {
+		return err
+	}
+	for _, proc := range found {
+		p := os.Process{}
+		p.Pid = proc.pid
+		if err := p.Kill(); err != nil {
+			log.LogTask(slogger.INFO, "Killing %v failed: %v", proc.pid, err)
+		} else {
+			log.LogTask(slogger.INFO, "Killed process %v", proc.pid)
+			processes.killed(key, proc, time.Now())
 		}
 	}
 	return nil
@@ -0,0 +1,36 @@
+package shell
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParsePeakRSS(t *testing.T) {
+	Convey("The peak resident memory should be read from a process's status", t, func() {
+		status := "Name:\tsleep\nVmPeak:\t    7000 kB\nVmHWM:\t     680 kB\nVmRSS:\t     600 kB\n"
+		So(parsePeakRSS(status), ShouldEqual, 680*1024)
+		So(parsePeakRSS("Name:\tkthreadd\n"), ShouldEqual, 0)
+	})
+}
+
+func TestDescribeProcess(t *testing.T) {
+	Convey("Only the executable should be recorded for a process", t, func() {
+		cmd := exec.Command("/bin/sh", "-c", "sleep 100; echo $1", "sh", "hunter2")
+		So(cmd.Start(), ShouldBeNil)
+		defer cmd.Wait()
+		defer cmd.Process.Kill()
+
+		// the command line is empty until the process has exec'd
+		p := describeProcess(cmd.Process.Pid)
+		for i := 0; i < 50 && p.command == ""; i++ {
+			time.Sleep(10 * time.Millisecond)
+			p = describeProcess(cmd.Process.Pid)
+		}
+		So(p.pid, ShouldEqual, cmd.Process.Pid)
+		So(p.command, ShouldEqual, "sh")
+		So(p.peakRSS, ShouldBeGreaterThan, 0)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen/plugin"
@@ -47,6 +48,17 @@ func getEnv(pid int) ([]string, error) {
 	return results, nil
 }
 
+// findProcesses is a noop on solaris, where processes are only looked for
+// when cleaning up after a task.
+func findProcesses(key string) ([]observedProcess, error) {
+	return nil, nil
+}
+
+// peakRSS is not reported on solaris.
+func peakRSS(state *os.ProcessState) int64 {
+	return 0
+}
+
 func cleanup(key string, log plugin.Logger) error {
 	pids, err := listProc()
 	if err != nil {
@@ -66,6 +78,7 @@ func cleanup(key string, log plugin.Logger) error {
 				log.LogSystem(slogger.INFO, "Cleanup killing %v failed: %v", pid, err)
 			} else {
 				log.LogTask(slogger.INFO, "Cleanup killed process %v", pid)
+				processes.killed(key, observedProcess{pid: pid}, time.Now())
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package shell
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"syscall"
 	"unsafe"
@@ -100,6 +101,17 @@ func trackProcess(taskId string, pid int, log plugin.Logger) error {
 	return err
 }
 
+// findProcesses is a noop on windows, where a task's processes are only
+// known to its job object.
+func findProcesses(key string) ([]observedProcess, error) {
+	return nil, nil
+}
+
+// peakRSS is not reported on windows.
+func peakRSS(state *os.ProcessState) int64 {
+	return 0
+}
+
 // cleanup() has a windows-specific implementation which finds the job object associated with the
 // given task key, and if it exists, terminates it. This will guarantee that any shell processes
 // started throughout the task run are destroyed, as long as they were captured in trackProcess.
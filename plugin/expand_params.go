@@ -116,7 +116,7 @@ func expandStruct(inputVal reflect.Value, expansions *command.Expansions) error
 			err := expandStruct(inputVal.FieldByName(field.Name), expansions)
 			if err != nil {
 				return fmt.Errorf("error expanding struct in field %v: %v",
-					field.Name, err)
+					paramName(field), err)
 			}
 			continue
 		}
@@ -127,7 +127,7 @@ func expandStruct(inputVal reflect.Value, expansions *command.Expansions) error
 			err := expandMap(inputMap, expansions)
 			if err != nil {
 				return fmt.Errorf("error expanding map in field %v: %v",
-					field.Name, err)
+					paramName(field), err)
 			}
 			continue
 		}
@@ -148,7 +148,7 @@ func expandStruct(inputVal reflect.Value, expansions *command.Expansions) error
 				}
 				if err != nil {
 					return fmt.Errorf("error expanding struct in field %v: %v",
-						field.Name, err)
+						paramName(field), err)
 				}
 			}
 			continue
@@ -164,14 +164,23 @@ func expandStruct(inputVal reflect.Value, expansions *command.Expansions) error
 		fieldOfElem := inputVal.FieldByName(field.Name)
 		err := expandString(fieldOfElem, expansions)
 		if err != nil {
-			return fmt.Errorf("error applying expansions to field %v with"+
-				" value %v: %v", field.Name, fieldOfElem.String(), err)
+			return fmt.Errorf("error applying expansions to field %v: %v",
+				paramName(field), err)
 		}
 	}
 
 	return nil
 }
 
+// paramName returns the name of the command parameter a field is decoded
+// from, so that errors point at what is in the project file.
+func paramName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
 func expandString(inputVal reflect.Value, expansions *command.Expansions) error {
 	expanded, err := expansions.ExpandString(inputVal.String())
 	if err != nil {
@@ -77,6 +77,19 @@ func TestExpandValues(t *testing.T) {
 
 		})
 
+		Convey("errors should name the parameter that failed to expand", func() {
+
+			type s struct {
+				FieldOne string `mapstructure:"field_one" plugin:"expand"`
+			}
+
+			s1 := &s{FieldOne: "hi ${upper(exp1}"}
+			err := ExpandValues(s1, expansions)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "field_one")
+
+		})
+
 		Convey("any nested structs tagged as expandable should have their"+
 			" fields expanded appropriately", func() {
 
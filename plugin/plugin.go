@@ -95,6 +95,9 @@ type PluginCommunicator interface {
 	// Make a GET request to the given endpoint with content type "application/json"
 	TaskGetJSON(endpoint string) (*http.Response, error)
 
+	// Make a POST request to the given endpoint, streaming the body's contents
+	TaskPostFile(endpoint string, body io.Reader) (*http.Response, error)
+
 	// Make a POST request against the results api endpoint
 	TaskPostResults(results *task.TestResults) error
 
@@ -0,0 +1,70 @@
+mciModule.controller('CostCtrl', function($scope, $http) {
+	var url = '/rest/v1/cost/report';
+
+	$scope.numberDays = [
+	{display: "1 day", value: 1},
+	{display: "1 week", value: 7},
+	{display: "2 weeks", value: 14},
+	{display: "1 month", value: 30}
+	];
+	$scope.currentNumberDays = $scope.numberDays[1];
+
+	// groupings are the dimensions the report can be rolled up by, in the
+	// order the server reports them in
+	$scope.groupings = [
+	{display: "Day", value: "day", enabled: true},
+	{display: "Project", value: "project", enabled: true},
+	{display: "Variant", value: "variant", enabled: false},
+	{display: "Task", value: "task", enabled: false},
+	{display: "Requester", value: "requester", enabled: true}
+	];
+
+	$scope.filter = {project: ""};
+	$scope.report = null;
+	$scope.loading = false;
+	$scope.errorMessage = "";
+
+	$scope.isGrouped = function(value) {
+		return _.some($scope.groupings, function(g) {
+			return g.value == value && g.enabled;
+		});
+	};
+
+	$scope.getCostReport = function() {
+		var groupBy = _.pluck(_.filter($scope.groupings, function(g) { return g.enabled; }), "value");
+		if (groupBy.length == 0) {
+			$scope.errorMessage = "Select at least one grouping";
+			return;
+		}
+		$scope.loading = true;
+		$scope.errorMessage = "";
+		$http.get(url, {
+			params: {
+				csv: false,
+				numberDays: $scope.currentNumberDays.value,
+				group_by: groupBy.join(","),
+				project: $scope.filter.project
+			}
+		})
+		.success(function(data) {
+			$scope.loading = false;
+			$scope.report = data;
+		})
+		.error(function(data) {
+			$scope.loading = false;
+			$scope.errorMessage = (data && data.message) || "Error loading cost report";
+		});
+	};
+
+	$scope.setNumberDays = function(numberDays) {
+		$scope.currentNumberDays = numberDays;
+		$scope.getCostReport();
+	};
+
+	$scope.toggleGrouping = function(grouping) {
+		grouping.enabled = !grouping.enabled;
+		$scope.getCostReport();
+	};
+
+	$scope.getCostReport();
+});
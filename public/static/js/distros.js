@@ -85,6 +85,32 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
     $location.hash(distro._id);
   };
 
+  // reset the bootstrap steps editor whenever a different distro is selected
+  $scope.$watch('activeDistro', function(distro) {
+    if (!distro) {
+      return;
+    }
+    $scope.bootstrap = {
+      text: distro.bootstrap_steps && distro.bootstrap_steps.length ? jsyaml.safeDump(distro.bootstrap_steps) : '',
+      error: '',
+    };
+  });
+
+  // parseBootstrapSteps updates the active distro's bootstrap steps from the
+  // YAML the user has entered.
+  $scope.parseBootstrapSteps = function() {
+    try {
+      var steps = jsyaml.safeLoad($scope.bootstrap.text || '') || [];
+      if (!_.isArray(steps)) {
+        throw new Error('bootstrap steps must be a list');
+      }
+      $scope.activeDistro.bootstrap_steps = steps;
+      $scope.bootstrap.error = '';
+    } catch (e) {
+      $scope.bootstrap.error = 'Invalid bootstrap steps: ' + e.message;
+    }
+  };
+
   $scope.getDistroById = function(id) {
     return _.find($scope.distros, function(distro) {
         return distro._id === id;
@@ -192,6 +218,9 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
   }
 
   $scope.saveConfiguration = function() {
+    if ($scope.bootstrap && $scope.bootstrap.error) {
+      return;
+    }
     if ($scope.activeDistro.new) {
       mciDistroRestService.addDistro(
         $scope.activeDistro, {
@@ -273,10 +302,13 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
         'setup': $scope.activeDistro.setup,
         'pool_size': $scope.activeDistro.pool_size,
         'setup_as_sudo' : $scope.activeDistro.setup_as_sudo,
+        'pull_agent': $scope.activeDistro.pull_agent,
 
       }
       newDistro.settings = _.clone($scope.activeDistro.settings);
       newDistro.expansions = _.clone($scope.activeDistro.expansions);
+      newDistro.bootstrap_steps = _.clone($scope.activeDistro.bootstrap_steps);
+      newDistro.tags = _.clone($scope.activeDistro.tags);
 
       $scope.distros.unshift(newDistro);
       $scope.hasNew = true;
@@ -5,6 +5,7 @@ mciModule.controller('VersionMatrixController', function($scope, $window, $locat
   $scope.allVersions = $window.allVersions;
   $scope.userTz = $window.userTz;
   $scope.baseRef = $window.baseRef;
+  $scope.annotations = $window.annotations || {};
 
   $scope.consts = { recentFailuresView: "RF", 
                     gridView: "GV", 
@@ -17,6 +18,27 @@ mciModule.controller('VersionMatrixController', function($scope, $window, $locat
                     revisionSort: "RS",
                   }
 
+  var classificationLabels = {
+    known_failure: 'Known failure',
+    infrastructure: 'Infrastructure',
+    product_bug: 'Product bug',
+    test_bug: 'Test bug'
+  };
+
+  // annotationSummary describes a task's annotations in a line of text,
+  // or returns an empty string if the task has none.
+  $scope.annotationSummary = function(taskId) {
+    return _.map($scope.annotations[taskId] || [], function(annotation) {
+      var parts = _.compact([
+        classificationLabels[annotation.classification] || annotation.classification,
+        annotation.issue_key,
+        annotation.suspected_culprit && 'culprit ' + annotation.suspected_culprit.substr(0, 10),
+        annotation.note
+      ]);
+      return parts.join(': ');
+    }).join('; ');
+  };
+
   $scope.taskFailures = [];
   $scope.testFailures = [];
   $scope.variantFailures = [];
@@ -4,6 +4,16 @@ mciModule.controller('HostCtrl', function($scope, $window) {
   $scope.running_task = $window.runningTask;
   $scope.events = $window.events.reverse();
 
+  // the host's statuses, newest first, and the total time spent in each
+  $scope.statusHistory = ($window.statusHistory || []).slice().reverse();
+  var totals = {};
+  _.each($scope.statusHistory, function(period) {
+    totals[period.status] = (totals[period.status] || 0) + period.duration;
+  });
+  $scope.statusTotals = _.map(totals, function(duration, status) {
+    return {status: status, duration: duration};
+  });
+
   $scope.host.uptime = "N/A";
   if ($scope.host.host_type !== "static") {
       var uptime;
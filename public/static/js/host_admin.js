@@ -27,6 +27,31 @@ mciModule.controller('AdminOptionsCtrl', ['$scope', 'mciHostRestService', 'notif
     );
   };
 
+  // a quarantined static host can be returned to service by passing its
+  // distro's health check
+  $scope.canRunHealthCheck = function() {
+    return $scope.host.status == 'quarantined' && $scope.host.host_type == 'static';
+  };
+
+  // the check runs in the background; its outcome shows up in the event log
+  $scope.runHealthCheck = function() {
+    $scope.healthCheckRunning = true;
+    hostRestService.updateStatus(
+      $scope.host.id,
+      'healthCheck',
+      {},
+      {
+        success: function(data, status) {
+          window.location.reload();
+        },
+        error: function(jqXHR, status, errorThrown) {
+          $scope.healthCheckRunning = false;
+          notifier.pushNotification('Error starting health check: ' + jqXHR, 'errorModal');
+        }
+      }
+    );
+  };
+
   $scope.setHostStatus = function(status) {
     $scope.newStatus = status;
   };
@@ -65,3 +90,10 @@ mciModule.directive('adminUpdateStatus', function() {
   };
 });
 
+mciModule.directive('adminHealthCheck', function() {
+  return {
+    restrict: 'E',
+    templateUrl: '/static/partials/host_health_check.html'
+  };
+});
+
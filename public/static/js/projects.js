@@ -134,17 +134,21 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
       .success(function(data, status){
         $scope.projectView = true;
         $scope.projectRef = data.ProjectRef;
+        $scope.budgetStatuses = data.BudgetStatuses || [];
 
         if (data.ProjectVars) {
          $scope.projectVars = data.ProjectVars.vars;
+         $scope.privateVars = data.ProjectVars.private_vars || {};
         }
         else {
           $scope.projectVars = {};
+          $scope.privateVars = {};
         }
 
         $scope.settingsFormData = {
           identifier : $scope.projectRef.identifier,
           project_vars: $scope.projectVars,
+          private_vars: $scope.privateVars,
           display_name : $scope.projectRef.display_name,
           remote_path:$scope.projectRef.remote_path,
           batch_time: parseInt($scope.projectRef.batch_time),
@@ -158,6 +162,9 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
           alert_config: $scope.projectRef.alert_config || {},
           repotracker_error: $scope.projectRef.repotracker_error || {},
           admins : $scope.projectRef.admins || [],
+          daily_budget: $scope.projectRef.daily_budget || {},
+          monthly_budget: $scope.projectRef.monthly_budget || {},
+          artifact_retention: $scope.projectRef.artifact_retention || {},
         };
 
         $scope.displayName = $scope.projectRef.display_name ? $scope.projectRef.display_name : $scope.projectRef.identifier;
@@ -190,8 +197,43 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
     return false;
   }
 
+  $scope.isBudgetValid = function(budget){
+    if(!budget){
+      return true
+    }
+    return $scope.isBatchTimeValid(budget.soft_limit || '') && $scope.isBatchTimeValid(budget.hard_limit || '')
+  }
+
+  $scope.isRetentionValid = function(days){
+    if(!days){
+      return true
+    }
+    return $scope.isBatchTimeValid(days) && Number(days) % 1 == 0
+  }
+
+  $scope.isOverBudget = function(){
+    return _.some($scope.budgetStatuses, function(s){ return s.over_hard_limit; });
+  }
+
+  $scope.overrideBudget = function(){
+    $http.post('/project/' + $scope.settingsFormData.identifier + '/budget/override').
+      success(function(data, status) {
+        $scope.budgetStatuses = data.BudgetStatuses || [];
+      }).
+      error(function(data, status, errorThrown){
+        console.log(status);
+      });
+  }
+
   $scope.saveProject = function() {
     $scope.settingsFormData.batch_time = parseInt($scope.settingsFormData.batch_time)
+    _.each([$scope.settingsFormData.daily_budget, $scope.settingsFormData.monthly_budget], function(budget){
+      budget.soft_limit = parseFloat(budget.soft_limit) || 0;
+      budget.hard_limit = parseFloat(budget.hard_limit) || 0;
+    });
+    var retention = $scope.settingsFormData.artifact_retention;
+    retention.patch_days = parseInt(retention.patch_days) || 0;
+    retention.mainline_days = parseInt(retention.mainline_days) || 0;
     if ($scope.proj_var) {
       $scope.addProjectVar();
     }
@@ -213,13 +255,18 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
   $scope.addProjectVar = function() {
     if ($scope.proj_var.name && $scope.proj_var.value) {
       $scope.settingsFormData.project_vars[$scope.proj_var.name] = $scope.proj_var.value;
+      if ($scope.proj_var.is_private) {
+        $scope.settingsFormData.private_vars[$scope.proj_var.name] = true;
+      }
       $scope.proj_var.name="";
       $scope.proj_var.value="";
+      $scope.proj_var.is_private=false;
     }
   };
 
   $scope.removeProjectVar = function(name) {
     delete $scope.settingsFormData.project_vars[name];
+    delete $scope.settingsFormData.private_vars[name];
     $scope.isDirty = true;
   };
 
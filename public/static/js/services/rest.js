@@ -245,6 +245,24 @@ mciServices.rest.factory('mciSpawnRestService', ['mciBaseRestService', function(
         baseSvc.postResource(resource, [], config, callbacks);
     };
 
+    service.stopHost = function(hostId, data, callbacks) {
+        var config = {
+            data: data
+        };
+        config.data['action'] = 'stop';
+        config.data['host_id'] = hostId;
+        baseSvc.postResource(resource, [], config, callbacks);
+    };
+
+    service.startHost = function(hostId, data, callbacks) {
+        var config = {
+            data: data
+        };
+        config.data['action'] = 'start';
+        config.data['host_id'] = hostId;
+        baseSvc.postResource(resource, [], config, callbacks);
+    };
+
     service.updateRDPPassword = function(action, hostId, rdpPassword, data, callbacks) {
         var config = {
             data: data
@@ -205,6 +205,32 @@ mciModule.controller('SpawnedHostsCtrl', ['$scope','$window', '$timeout', 'mciSp
       );
     };
 
+    $scope.stopHost = function(host) {
+      mciSpawnRestService.stopHost(
+        host.id, {}, {
+          success: function(data, status) {
+            window.location.href = "/spawn";
+          },
+          error: function(jqXHR, status, errorThrown) {
+            notificationService.pushNotification('Error stopping host: ' + jqXHR,'errorHeader');
+          }
+        }
+      );
+    };
+
+    $scope.startHost = function(host) {
+      mciSpawnRestService.startHost(
+        host.id, {}, {
+          success: function(data, status) {
+            window.location.href = "/spawn";
+          },
+          error: function(jqXHR, status, errorThrown) {
+            notificationService.pushNotification('Error starting host: ' + jqXHR,'errorHeader');
+          }
+        }
+      );
+    };
+
     // API helper methods
     $scope.setSpawnableDistros = function(distros, selectDistroId) {
       if (distros.length == 0) {
@@ -294,6 +320,9 @@ mciModule.controller('SpawnedHostsCtrl', ['$scope','$window', '$timeout', 'mciSp
         case 'starting':
           return 'label block-status-started';
           break;
+        case 'stopped':
+          return 'label block-status-inactive';
+          break;
         case 'decommissioned':
         case 'unreachable':
         case 'quarantined':
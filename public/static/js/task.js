@@ -288,6 +288,24 @@ mciModule.controller('TaskCtrl', function($scope, $rootScope, $now, $timeout, $i
   };
 
 
+  // processDuration returns how long a process spawned by the task ran, if
+  // both its start and end were seen.
+  $scope.processDuration = function(proc) {
+    var start = moment(proc.start);
+    var end = moment(proc.end);
+    if (start.year() <= 1 || end.year() <= 1) {
+      return '';
+    }
+    return $filter('stringifyNanoseconds')(end.diff(start) * 1000 * 1000);
+  };
+
+  $scope.formatProcessMemory = function(bytes) {
+    if (!bytes) {
+      return '';
+    }
+    return (bytes / (1024 * 1024)).toFixed(1) + ' MB';
+  };
+
   $scope.setTask = function(task) {
     $scope.task = task;
     $scope.md5 = md5;
@@ -514,6 +532,192 @@ mciModule.directive('testResultBar', function($filter) {
   }
 });
 
+mciModule.controller('TaskSystemStatsCtrl', function($scope, $window, $http, $timeout) {
+  $scope.stats = [];
+  $scope.summary = {};
+
+  var metrics = [
+    {name: 'CPU', color: '#337ab7', value: function(s) { return s.cpu_pct; }},
+    {name: 'Memory', color: '#d9534f', value: function(s) { return s.mem_total ? 100 * s.mem_used / s.mem_total : 0; }},
+    {name: 'Disk', color: '#f0ad4e', value: function(s) { return s.disk_total ? 100 * s.disk_used / s.disk_total : 0; }},
+  ];
+
+  $scope.formatBytes = function(bytes) {
+    var units = ['B', 'KB', 'MB', 'GB', 'TB'];
+    var i = 0;
+    bytes = bytes || 0;
+    while (bytes >= 1024 && i < units.length - 1) {
+      bytes /= 1024;
+      i++;
+    }
+    return bytes.toFixed(i == 0 ? 0 : 1) + ' ' + units[i];
+  };
+
+  $scope.drawGraph = function() {
+    var graphId = '#system-stats-graph';
+    $(graphId).empty();
+    if ($scope.stats.length < 2) {
+      return;
+    }
+
+    var margin = {top: 20, right: 100, bottom: 30, left: 50};
+    var width = $(graphId).width() - margin.left - margin.right;
+    var height = 200;
+    var svg = d3.select(graphId)
+      .append('svg')
+      .attr('width', width + margin.left + margin.right)
+      .attr('height', height + margin.top + margin.bottom)
+      .append('g')
+      .attr('transform', 'translate(' + margin.left + ',' + margin.top + ')');
+
+    var times = _.map($scope.stats, function(s) { return new Date(s.ts); });
+    var xScale = d3.time.scale().domain(d3.extent(times)).range([0, width]);
+    var yScale = d3.scale.linear().domain([0, 100]).range([height, 0]);
+
+    svg.append('g')
+      .attr('class', 'x axis')
+      .attr('transform', 'translate(0,' + height + ')')
+      .call(d3.svg.axis().scale(xScale).orient('bottom').ticks(6));
+    svg.append('g')
+      .attr('class', 'y axis')
+      .call(d3.svg.axis().scale(yScale).orient('left').ticks(5)
+        .tickFormat(function(d) { return d + '%'; }));
+
+    _.each(metrics, function(metric, i) {
+      var line = d3.svg.line()
+        .x(function(s, j) { return xScale(times[j]); })
+        .y(function(s) { return yScale(metric.value(s)); });
+      svg.append('path')
+        .datum($scope.stats)
+        .attr('d', line)
+        .attr('fill', 'none')
+        .attr('stroke', metric.color)
+        .attr('stroke-width', 2);
+      svg.append('text')
+        .attr('x', width + 10)
+        .attr('y', 15 * (i + 1))
+        .attr('fill', metric.color)
+        .text(metric.name);
+    });
+  };
+
+  var task = $window.task_data;
+  $http.get('/json/task_system_stats/' + task.id + '/' + task.execution).
+    success(function(data) {
+      $scope.stats = data.stats || [];
+      $scope.summary = data.summary;
+      // wait for the pod to be shown so the graph can be sized to it
+      $timeout($scope.drawGraph);
+    }).
+    error(function(data, status) {
+      console.log('error loading system stats: ' + status);
+    });
+});
+
+mciModule.controller('TaskAnnotationsCtrl', ['$scope', '$window', '$http', 'notificationService', function($scope, $window, $http, notifier) {
+  var task = $window.task_data;
+  $scope.annotations = [];
+  $scope.canAnnotate = !!$window.have_user && !task.archived;
+  $scope.classifications = [
+    {value: '', label: 'None'},
+    {value: 'known_failure', label: 'Known failure'},
+    {value: 'infrastructure', label: 'Infrastructure'},
+    {value: 'product_bug', label: 'Product bug'},
+    {value: 'test_bug', label: 'Test bug'}
+  ];
+
+  var emptyAnnotation = function() {
+    return {note: '', issue_key: '', classification: '', suspected_culprit: ''};
+  };
+  $scope.newAnnotation = emptyAnnotation();
+
+  $scope.canRemove = function(annotation) {
+    return !!$window.user && ($window.isSuperUser || $window.user.Id == annotation.author);
+  };
+
+  $scope.classificationLabel = function(classification) {
+    var found = _.findWhere($scope.classifications, {value: classification});
+    return found ? found.label : classification;
+  };
+
+  $scope.loadAnnotations = function() {
+    $http.get('/rest/v1/tasks/' + task.id + '/annotations', {params: {execution: task.execution}}).
+      success(function(data) {
+        $scope.annotations = data || [];
+      }).
+      error(function(data) {
+        notifier.pushNotification('Error loading annotations: ' + (data && data.message || data), 'errorHeader');
+      });
+  };
+
+  $scope.addAnnotation = function() {
+    $http.post('/rest/v1/tasks/' + task.id + '/annotations', $scope.newAnnotation).
+      success(function(data) {
+        $scope.annotations.push(data);
+        $scope.newAnnotation = emptyAnnotation();
+      }).
+      error(function(data) {
+        notifier.pushNotification('Error annotating task: ' + (data && data.message || data), 'errorHeader');
+      });
+  };
+
+  $scope.removeAnnotation = function(annotation) {
+    $http.delete('/rest/v1/tasks/' + task.id + '/annotations/' + annotation.id).
+      success(function() {
+        $scope.annotations = _.without($scope.annotations, annotation);
+      }).
+      error(function(data) {
+        notifier.pushNotification('Error removing annotation: ' + (data && data.message || data), 'errorHeader');
+      });
+  };
+
+  // annotations made elsewhere on the page, e.g. by linking a suggested ticket
+  $scope.$on('taskAnnotated', function(event, annotation) {
+    $scope.annotations.push(annotation);
+  });
+
+  $scope.loadAnnotations();
+}]);
+
+mciModule.controller('TaskFailureMatchesCtrl', ['$scope', '$rootScope', '$window', '$http', 'notificationService', function($scope, $rootScope, $window, $http, notifier) {
+  var task = $window.task_data;
+  $scope.suggestions = [];
+  $scope.linked = {};
+  $scope.loading = false;
+
+  $scope.loadSuggestions = function() {
+    $scope.loading = true;
+    $http.get('/json/task_failure_matches/' + task.id).
+      success(function(data) {
+        $scope.loading = false;
+        $scope.suggestions = data || [];
+      }).
+      error(function(data, status) {
+        // failure matching is optional, so say nothing if it isn't set up
+        $scope.loading = false;
+        console.log('error matching failure: ' + status);
+      });
+  };
+
+  // linkTicket records that the suggested ticket describes the failure by
+  // annotating the task with it
+  $scope.linkTicket = function(suggestion) {
+    var annotation = {issue_key: suggestion.key, classification: 'known_failure'};
+    $http.post('/rest/v1/tasks/' + task.id + '/annotations', annotation).
+      success(function(data) {
+        $scope.linked[suggestion.key] = true;
+        $rootScope.$broadcast('taskAnnotated', data);
+      }).
+      error(function(data) {
+        notifier.pushNotification('Error linking ticket: ' + (data && data.message || data), 'errorHeader');
+      });
+  };
+
+  if (task.status == 'failed' && !task.archived) {
+    $scope.loadSuggestions();
+  }
+}]);
+
 mciModule.controller('TaskLogCtrl', ['$scope', '$timeout', '$http', '$location', '$window', '$filter', 'notificationService', function($scope, $timeout, $http, $location, $window, $filter, notifier) {
   $scope.logs = 'Loading...';
   $scope.task = {};
@@ -594,6 +798,35 @@ mciModule.controller('TaskLogCtrl', ['$scope', '$timeout', '$http', '$location',
     }
   };
 
+  $scope.logSearch = {pattern: '', loading: false, results: null};
+
+  $scope.searchLogs = function() {
+    if (!$scope.logSearch.pattern) {
+      return;
+    }
+    $scope.logSearch.loading = true;
+    $http.get('/rest/v1/tasks/' + $scope.taskId + '/log_search', {
+      params: {
+        q: $scope.logSearch.pattern,
+        context: 2,
+        execution: $scope.task.execution,
+        type: $scope.currentLogs
+      }
+    }).
+    success(function(data) {
+      $scope.logSearch.loading = false;
+      $scope.logSearch.results = data;
+    }).
+    error(function(data) {
+      $scope.logSearch.loading = false;
+      notifier.pushNotification('Error searching logs: ' + (data && data.message || data), 'errorHeader');
+    });
+  };
+
+  $scope.clearLogSearch = function() {
+    $scope.logSearch.results = null;
+  };
+
   $scope.setTask = function(task) {
     $scope.task = task;
     $scope.taskId = task.id;
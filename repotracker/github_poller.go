@@ -86,7 +86,21 @@ func (gRepoPoller *GithubRepositoryPoller) GetRemoteConfig(
 	}
 
 	projectConfig = &model.Project{}
-	err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, projectConfig)
+	err = model.LoadProjectWithIncludes(projectFileBytes, projectRef.Identifier, projectConfig,
+		model.GithubIncludeFetcher(gRepoPoller.OauthToken, projectRef, projectFileRevision))
+	if fetchErr, ok := err.(model.IncludeFetchError); ok {
+		switch fetchErr.Err.(type) {
+		case thirdparty.APIRequestError, thirdparty.FileNotFoundError,
+			thirdparty.FileDecodeError, thirdparty.YAMLFormatError:
+			// the included file is missing or unreadable, which is a problem
+			// with the configuration
+			return nil, thirdparty.YAMLFormatError{Message: err.Error()}
+		default:
+			// GitHub couldn't be reached or is rate limiting, so this is
+			// retried rather than recorded as a bad configuration
+			return nil, fetchErr.Err
+		}
+	}
 	if err != nil {
 		return nil, thirdparty.YAMLFormatError{err.Error()}
 	}
@@ -6,6 +6,7 @@ package runner
 import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/alerts"
+	"github.com/evergreen-ci/evergreen/artifactgc"
 	"github.com/evergreen-ci/evergreen/hostinit"
 	"github.com/evergreen-ci/evergreen/monitor"
 	"github.com/evergreen-ci/evergreen/notify"
@@ -34,6 +35,7 @@ var (
 		&repotracker.Runner{},
 		&taskrunner.Runner{},
 		&alerts.QueueProcessor{},
+		&artifactgc.Runner{},
 		&scheduler.Runner{},
 	}
 )
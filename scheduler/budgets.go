@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/alerts"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/tychoish/grip/slogger"
+)
+
+// budgetCheckInterval is how long the scheduler reuses the budget statuses
+// of a project before adding up its spending again.
+const budgetCheckInterval = 10 * time.Minute
+
+// budgetCache holds the budget statuses last found for each project, since
+// adding up a month of spending on every scheduler pass is expensive.
+type budgetCache struct {
+	mu      sync.Mutex
+	entries map[string]budgetCacheEntry
+
+	// find returns the budget statuses of the project
+	find func(projectRef *model.ProjectRef, now time.Time) ([]model.BudgetStatus, error)
+}
+
+// budgetCacheEntry is the budget statuses of a project, along with the
+// budgets they were found for.
+type budgetCacheEntry struct {
+	found         time.Time
+	daily         model.ProjectBudget
+	monthly       model.ProjectBudget
+	overrideUntil time.Time
+	statuses      []model.BudgetStatus
+}
+
+var overBudgetCache = &budgetCache{
+	find: func(projectRef *model.ProjectRef, now time.Time) ([]model.BudgetStatus, error) {
+		return projectRef.BudgetStatuses(now)
+	},
+}
+
+// statuses returns the budget statuses of the project, finding them again if
+// they're older than the check interval, if a period they cover has ended,
+// or if the project's budgets have changed since. Returns true if the
+// statuses were found again.
+func (c *budgetCache) statuses(projectRef *model.ProjectRef, now time.Time) (
+	[]model.BudgetStatus, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[projectRef.Identifier]; ok && entry.current(projectRef, now) {
+		return entry.statuses, false, nil
+	}
+	statuses, err := c.find(projectRef, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.entries == nil {
+		c.entries = map[string]budgetCacheEntry{}
+	}
+	c.entries[projectRef.Identifier] = budgetCacheEntry{
+		found:         now,
+		daily:         projectRef.DailyBudget,
+		monthly:       projectRef.MonthlyBudget,
+		overrideUntil: projectRef.BudgetOverrideUntil,
+		statuses:      statuses,
+	}
+	return statuses, true, nil
+}
+
+// current returns true if the entry's statuses can still be used for the
+// project at the given time.
+func (e *budgetCacheEntry) current(projectRef *model.ProjectRef, now time.Time) bool {
+	if now.Sub(e.found) >= budgetCheckInterval || now.Before(e.found) {
+		return false
+	}
+	if e.daily != projectRef.DailyBudget || e.monthly != projectRef.MonthlyBudget ||
+		!e.overrideUntil.Equal(projectRef.BudgetOverrideUntil) {
+		return false
+	}
+	for _, status := range e.statuses {
+		if !now.Before(status.PeriodEnd) {
+			return false
+		}
+	}
+	return true
+}
+
+// findOverBudgetProjects checks the spending of every project with a budget,
+// firing alerts for the projects that cross a soft limit. Returns the set of
+// projects that are over a hard limit. A project's spending is added up again
+// only every budgetCheckInterval. Errors are logged, and leave the project's
+// tasks alone.
+func findOverBudgetProjects(now time.Time) map[string]bool {
+	overBudget := map[string]bool{}
+	projectRefs, err := model.FindAllProjectRefs()
+	if err != nil {
+		evergreen.Logger.Logf(slogger.ERROR, "Error finding projects to check budgets of: %v", err)
+		return overBudget
+	}
+	for i := range projectRefs {
+		projectRef := &projectRefs[i]
+		if len(projectRef.Budgets()) == 0 {
+			continue
+		}
+		statuses, found, err := overBudgetCache.statuses(projectRef, now)
+		if err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error checking budgets of project %v: %v",
+				projectRef.Identifier, err)
+			continue
+		}
+		// alerts only need checking when the spending has been added up again
+		if found {
+			for j := range statuses {
+				if err = alerts.RunBudgetTriggers(projectRef, &statuses[j]); err != nil {
+					evergreen.Logger.Logf(slogger.ERROR, "Error queueing budget alert for project %v: %v",
+						projectRef.Identifier, err)
+				}
+			}
+		}
+		if model.OverBudget(statuses) {
+			evergreen.Logger.Logf(slogger.WARN, "Project %v is over budget, holding its patch "+
+				"tasks and deprioritizing its mainline tasks", projectRef.Identifier)
+			overBudget[projectRef.Identifier] = true
+		}
+	}
+	return overBudget
+}
+
+// holdOverBudgetPatches removes the patch tasks of over-budget projects from
+// the runnable tasks, leaving them undispatched until the project is back
+// under budget.
+func holdOverBudgetPatches(tasks []task.Task, overBudget map[string]bool) []task.Task {
+	if len(overBudget) == 0 {
+		return tasks
+	}
+	runnable := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if overBudget[t.Project] && t.Requester == evergreen.PatchVersionRequester {
+			continue
+		}
+		runnable = append(runnable, t)
+	}
+	return runnable
+}
+
+// deprioritizeOverBudget moves the tasks of over-budget projects to the back
+// of a prioritized queue, keeping the relative order of the rest.
+func deprioritizeOverBudget(tasks []task.Task, overBudget map[string]bool) []task.Task {
+	if len(overBudget) == 0 {
+		return tasks
+	}
+	inBudget := make([]task.Task, 0, len(tasks))
+	deprioritized := []task.Task{}
+	for _, t := range tasks {
+		if overBudget[t.Project] {
+			deprioritized = append(deprioritized, t)
+		} else {
+			inBudget = append(inBudget, t)
+		}
+	}
+	return append(inBudget, deprioritized...)
+}
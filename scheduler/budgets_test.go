@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOverBudgetTasks(t *testing.T) {
+	Convey("With tasks from a project over budget and one under budget", t, func() {
+		tasks := []task.Task{
+			{Id: "t1", Project: "over", Requester: evergreen.RepotrackerVersionRequester},
+			{Id: "t2", Project: "under", Requester: evergreen.PatchVersionRequester},
+			{Id: "t3", Project: "over", Requester: evergreen.PatchVersionRequester},
+			{Id: "t4", Project: "under", Requester: evergreen.RepotrackerVersionRequester},
+		}
+		overBudget := map[string]bool{"over": true}
+
+		Convey("only the patch tasks of the project over budget should be held", func() {
+			runnable := holdOverBudgetPatches(tasks, overBudget)
+			So(len(runnable), ShouldEqual, 3)
+			for _, t := range runnable {
+				So(t.Id, ShouldNotEqual, "t3")
+			}
+		})
+
+		Convey("the tasks of the project over budget should be moved to the back of the queue", func() {
+			queue := deprioritizeOverBudget(tasks, overBudget)
+			So(len(queue), ShouldEqual, 4)
+			So(queue[0].Id, ShouldEqual, "t2")
+			So(queue[1].Id, ShouldEqual, "t4")
+			So(queue[2].Id, ShouldEqual, "t1")
+			So(queue[3].Id, ShouldEqual, "t3")
+		})
+
+		Convey("no projects over budget should leave the tasks alone", func() {
+			So(holdOverBudgetPatches(tasks, nil), ShouldResemble, tasks)
+			So(deprioritizeOverBudget(tasks, nil), ShouldResemble, tasks)
+		})
+	})
+}
+
+func TestBudgetCache(t *testing.T) {
+	Convey("With a cache of budget statuses", t, func() {
+		finds := 0
+		now := time.Date(2017, time.March, 10, 12, 0, 0, 0, time.UTC)
+		cache := &budgetCache{
+			find: func(projectRef *model.ProjectRef, now time.Time) ([]model.BudgetStatus, error) {
+				finds++
+				return []model.BudgetStatus{{
+					ProjectId: projectRef.Identifier,
+					PeriodEnd: time.Date(2017, time.March, 11, 0, 0, 0, 0, time.UTC),
+				}}, nil
+			},
+		}
+		projectRef := &model.ProjectRef{
+			Identifier:  "proj",
+			DailyBudget: model.ProjectBudget{HardLimit: 100},
+		}
+		_, found, err := cache.statuses(projectRef, now)
+		So(err, ShouldBeNil)
+		So(found, ShouldBeTrue)
+
+		Convey("statuses should be reused within the check interval", func() {
+			_, found, err = cache.statuses(projectRef, now.Add(budgetCheckInterval/2))
+			So(err, ShouldBeNil)
+			So(found, ShouldBeFalse)
+			So(finds, ShouldEqual, 1)
+		})
+
+		Convey("statuses should be found again after the check interval", func() {
+			_, found, err = cache.statuses(projectRef, now.Add(budgetCheckInterval))
+			So(err, ShouldBeNil)
+			So(found, ShouldBeTrue)
+			So(finds, ShouldEqual, 2)
+		})
+
+		Convey("statuses should be found again once their period has ended", func() {
+			end := time.Date(2017, time.March, 11, 0, 0, 0, 0, time.UTC)
+			cache.entries["proj"] = budgetCacheEntry{
+				found:    end.Add(-time.Minute),
+				daily:    projectRef.DailyBudget,
+				statuses: cache.entries["proj"].statuses,
+			}
+			_, found, err = cache.statuses(projectRef, end)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeTrue)
+		})
+
+		Convey("statuses should be found again when the budget is overridden", func() {
+			projectRef.BudgetOverrideUntil = now.Add(time.Hour)
+			_, found, err = cache.statuses(projectRef, now.Add(time.Minute))
+			So(err, ShouldBeNil)
+			So(found, ShouldBeTrue)
+		})
+	})
+}
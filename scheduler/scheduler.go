@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +20,11 @@ import (
 	"github.com/evergreen-ci/evergreen/util"
 )
 
+// DefaultFallbackQueueLength is how many tasks a distro's queue may hold
+// before tasks that can run on other distros are placed on those instead,
+// unless the scheduler is configured otherwise.
+const DefaultFallbackQueueLength = 100
+
 // Responsible for prioritizing and scheduling tasks to be run, on a per-distro
 // basis.
 type Scheduler struct {
@@ -57,13 +63,11 @@ func (s *Scheduler) Schedule() error {
 		return fmt.Errorf("Error finding runnable tasks: %v", err)
 	}
 
-	evergreen.Logger.Logf(slogger.INFO, "There are %v tasks ready to be run", len(runnableTasks))
+	// hold the patch tasks of projects that are over budget
+	overBudget := findOverBudgetProjects(time.Now())
+	runnableTasks = holdOverBudgetPatches(runnableTasks, overBudget)
 
-	// split the tasks by distro
-	tasksByDistro, taskRunDistros, err := s.splitTasksByDistro(runnableTasks)
-	if err != nil {
-		return fmt.Errorf("Error splitting tasks by distro to run on: %v", err)
-	}
+	evergreen.Logger.Logf(slogger.INFO, "There are %v tasks ready to be run", len(runnableTasks))
 
 	// load in all of the distros
 	distros, err := distro.Find(distro.All)
@@ -71,6 +75,12 @@ func (s *Scheduler) Schedule() error {
 		return fmt.Errorf("Error finding distros: %v", err)
 	}
 
+	// split the tasks by distro
+	tasksByDistro, taskRunDistros, err := s.splitTasksByDistro(runnableTasks, distros)
+	if err != nil {
+		return fmt.Errorf("Error splitting tasks by distro to run on: %v", err)
+	}
+
 	// get the expected run duration of all runnable tasks
 	taskExpectedDuration, err := s.GetExpectedDurations(runnableTasks)
 
@@ -111,7 +121,7 @@ func (s *Scheduler) Schedule() error {
 			// read the inputs for scheduling this distro
 			for d := range distroInputChan {
 				// schedule the distro
-				res := s.scheduleDistro(d.distroId, d.runnableTasksForDistro, taskExpectedDuration)
+				res := s.scheduleDistro(d.distroId, d.runnableTasksForDistro, taskExpectedDuration, overBudget)
 				if res.err != nil {
 					evergreen.Logger.Logf(slogger.ERROR, "%v", err)
 				}
@@ -246,7 +256,7 @@ type distroSchedulerResult struct {
 }
 
 func (s *Scheduler) scheduleDistro(distroId string, runnableTasksForDistro []task.Task,
-	taskExpectedDuration model.ProjectTaskDurations) *distroSchedulerResult {
+	taskExpectedDuration model.ProjectTaskDurations, overBudget map[string]bool) *distroSchedulerResult {
 
 	res := distroSchedulerResult{
 		distroId: distroId,
@@ -260,6 +270,7 @@ func (s *Scheduler) scheduleDistro(distroId string, runnableTasksForDistro []tas
 		res.err = fmt.Errorf("Error prioritizing tasks: %v", err)
 		return &res
 	}
+	prioritizedTasks = deprioritizeOverBudget(prioritizedTasks, overBudget)
 
 	// persist the queue of tasks
 	evergreen.Logger.Logf(slogger.INFO, "Saving task queue for distro %v...", distroId)
@@ -325,14 +336,24 @@ func (s *Scheduler) updateVersionBuildVarMap(versionStr string,
 // Returns a map of distro name -> tasks that can be run on that distro
 // and a map of task id -> distros that the task can be run on (for tasks
 // that can be run on multiple distro)
-func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task) (
+func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task, distros []distro.Distro) (
 	map[string][]task.Task, map[string][]string, error) {
 	tasksByDistro := make(map[string][]task.Task)
 	taskRunDistros := make(map[string][]string)
 
+	// resolves distro aliases and selectors into distro ids
+	distroEval := model.NewDistroSelectorEvaluator(distros)
+	fallbackQueueLength := s.Settings.Scheduler.FallbackQueueLength
+	if fallbackQueueLength <= 0 {
+		fallbackQueueLength = DefaultFallbackQueueLength
+	}
+
 	// map of versionBuildVariant -> build variant
 	versionBuildVarMap := make(map[versionBuildVariant]model.BuildVariant)
 
+	// the tasks that have no distro to run on in this pass
+	stillUnschedulable := map[string]bool{}
+
 	// insert the tasks into the appropriate distro's queue in our map
 	for _, task := range tasksToSplit {
 		key := versionBuildVariant{task.Version, task.BuildVariant}
@@ -372,9 +393,32 @@ func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task) (
 
 		// use the specified distros for the task, or, if none are specified,
 		// the default distros for the build variant
-		distrosToUse := buildVariant.RunOn
+		runOn := buildVariant.RunOn
 		if len(taskSpec.Distros) != 0 {
-			distrosToUse = taskSpec.Distros
+			runOn = taskSpec.Distros
+		}
+
+		// if none of the task's own distros can be resolved, fall back to
+		// the default distros for the build variant
+		candidateSets, failures := resolveRunOn(distroEval, runOn)
+		if len(candidateSets) == 0 && len(taskSpec.Distros) != 0 {
+			evergreen.Logger.Logf(slogger.WARN, "task %v can not run on any of its distros, "+
+				"using those of build variant %v", task.Id, buildVariant.Name)
+			var variantFailures []string
+			candidateSets, variantFailures = resolveRunOn(distroEval, buildVariant.RunOn)
+			failures = append(failures, variantFailures...)
+		}
+		if len(candidateSets) == 0 {
+			reportUnschedulable(task.Id, failures)
+			stillUnschedulable[task.Id] = true
+			continue
+		}
+
+		// place the task on the best distro that each entry refers to
+		distrosToUse := []string{}
+		for _, candidates := range candidateSets {
+			distrosToUse = append(distrosToUse,
+				pickDistro(candidates, tasksByDistro, fallbackQueueLength))
 		}
 		// remove duplicates to avoid scheduling twice
 		distrosToUse = util.UniqueStrings(distrosToUse)
@@ -388,11 +432,85 @@ func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task) (
 			taskRunDistros[task.Id] = distrosToUse
 		}
 	}
+	forgetSchedulable(stillUnschedulable)
 
 	return tasksByDistro, taskRunDistros, nil
 
 }
 
+// resolveRunOn resolves each of the run_on entries into the distros it
+// refers to, returning the candidates for the entries that resolved, along
+// with why the rest didn't.
+func resolveRunOn(distroEval *model.DistroSelectorEvaluator, runOn []string) ([][]string, []string) {
+	candidateSets := [][]string{}
+	failures := []string{}
+	for _, entry := range runOn {
+		candidates, err := distroEval.Resolve(entry)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("can not run on '%v': %v", entry, err))
+			continue
+		}
+		candidateSets = append(candidateSets, candidates)
+	}
+	return candidateSets, failures
+}
+
+// unschedulable holds the ids of the tasks already reported as having no
+// distro to run on, so that each is reported once rather than on every pass.
+// Tasks are forgotten once they can be scheduled or are no longer runnable.
+var unschedulable = struct {
+	sync.Mutex
+	tasks map[string]bool
+}{tasks: map[string]bool{}}
+
+// reportUnschedulable logs that the task has no distro to run on, and
+// records it as a task event so that it's visible on the task's page.
+func reportUnschedulable(taskId string, failures []string) {
+	reason := "it has no distros to run on"
+	if len(failures) != 0 {
+		reason = strings.Join(failures, "; ")
+	}
+	evergreen.Logger.Logf(slogger.ERROR, "task %v can not be scheduled: %v", taskId, reason)
+
+	unschedulable.Lock()
+	defer unschedulable.Unlock()
+	if unschedulable.tasks[taskId] {
+		return
+	}
+	unschedulable.tasks[taskId] = true
+	event.LogTaskUnschedulable(taskId, reason)
+}
+
+// forgetSchedulable removes the tasks that weren't found to have no distro
+// to run on in the latest pass from the set of those already reported, so
+// that it only holds tasks that are still waiting.
+func forgetSchedulable(stillUnschedulable map[string]bool) {
+	unschedulable.Lock()
+	defer unschedulable.Unlock()
+	for taskId := range unschedulable.tasks {
+		if !stillUnschedulable[taskId] {
+			delete(unschedulable.tasks, taskId)
+		}
+	}
+}
+
+// pickDistro returns the first of the candidate distros, in order of
+// preference, whose queue is shorter than the fallback length. If every
+// queue is that long, it returns the candidate with the shortest queue.
+func pickDistro(candidates []string, tasksByDistro map[string][]task.Task,
+	fallbackQueueLength int) string {
+	best := candidates[0]
+	for _, d := range candidates {
+		if len(tasksByDistro[d]) < fallbackQueueLength {
+			return d
+		}
+		if len(tasksByDistro[d]) < len(tasksByDistro[best]) {
+			best = d
+		}
+	}
+	return best
+}
+
 // Call out to the embedded CloudManager to spawn hosts.  Takes in a map of
 // distro -> number of hosts to spawn for the distro.
 // Returns a map of distro -> hosts spawned, and an error if one occurs.
@@ -228,3 +228,75 @@ func TestSpawnHosts(t *testing.T) {
 	})
 
 }
+
+func TestPickDistro(t *testing.T) {
+
+	Convey("When picking a distro for a task", t, func() {
+
+		tasksByDistro := map[string][]task.Task{
+			"d1": make([]task.Task, 3),
+			"d2": make([]task.Task, 1),
+			"d3": make([]task.Task, 2),
+		}
+
+		Convey("the most preferred distro should be used while its queue is"+
+			" short", func() {
+			So(pickDistro([]string{"d1", "d2"}, tasksByDistro, 5), ShouldEqual, "d1")
+		})
+
+		Convey("the next distro should be used once the preferred queue is"+
+			" long", func() {
+			So(pickDistro([]string{"d1", "d3", "d2"}, tasksByDistro, 3), ShouldEqual, "d3")
+		})
+
+		Convey("the shortest queue should be used when every queue is long", func() {
+			So(pickDistro([]string{"d1", "d3", "d2"}, tasksByDistro, 1), ShouldEqual, "d2")
+		})
+
+	})
+}
+
+func TestResolveRunOn(t *testing.T) {
+
+	Convey("When resolving the distros a task runs on", t, func() {
+
+		distroEval := model.NewDistroSelectorEvaluator([]distro.Distro{
+			{Id: "d1", Tags: []string{"linux"}},
+			{Id: "d2", Tags: []string{"linux"}},
+		})
+
+		Convey("entries that resolve should give their candidates", func() {
+			candidateSets, failures := resolveRunOn(distroEval, []string{"d2", ".linux"})
+			So(failures, ShouldBeEmpty)
+			So(len(candidateSets), ShouldEqual, 2)
+			So(candidateSets[0], ShouldResemble, []string{"d2"})
+		})
+
+		Convey("entries that don't resolve should be reported", func() {
+			candidateSets, failures := resolveRunOn(distroEval, []string{".solaris"})
+			So(candidateSets, ShouldBeEmpty)
+			So(len(failures), ShouldEqual, 1)
+		})
+
+		Convey("no entries should give no candidates", func() {
+			candidateSets, failures := resolveRunOn(distroEval, nil)
+			So(candidateSets, ShouldBeEmpty)
+			So(failures, ShouldBeEmpty)
+		})
+
+	})
+}
+
+func TestForgetSchedulable(t *testing.T) {
+
+	Convey("When tasks have been reported as unschedulable", t, func() {
+
+		unschedulable.tasks = map[string]bool{"t1": true, "t2": true}
+
+		Convey("only those still unschedulable should be remembered", func() {
+			forgetSchedulable(map[string]bool{"t2": true, "t3": true})
+			So(unschedulable.tasks, ShouldResemble, map[string]bool{"t2": true})
+		})
+
+	})
+}
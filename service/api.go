@@ -19,6 +19,7 @@ import (
 	"github.com/evergreen-ci/evergreen/bookkeeping"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/cloud/providers"
+	"github.com/evergreen-ci/evergreen/cloud/providers/static"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
@@ -28,6 +29,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/plugin"
+	"github.com/evergreen-ci/evergreen/plugin/builtin/artifacts"
 	"github.com/evergreen-ci/evergreen/taskrunner"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
@@ -312,7 +314,7 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		as.taskFinished(w, t, finishTime)
+		as.taskFinished(w, t, details, finishTime)
 		return
 	}
 
@@ -327,7 +329,7 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	evergreen.Logger.Logf(slogger.INFO, "Successfully marked task %v as finished", t.Id)
 
 	// construct and return the appropriate response for the agent
-	as.taskFinished(w, t, finishTime)
+	as.taskFinished(w, t, details, finishTime)
 }
 
 // updateTaskCost determines a task's cost based on the host it ran on. Hosts that
@@ -372,11 +374,13 @@ func markHostRunningTaskFinished(h *host.Host, t *task.Task, newTaskId string) {
 // The first case is the usual expected flow. The second case however, could
 // occur for a number of reasons including:
 // a. The version of the agent running on the remote machine is stale
-// b. The host the agent is running on has been decommissioned
+// b. The host the agent is running on has been decommissioned, or quarantined
+// because of repeated system failures
 // c. There is no currently queued dispatchable and activated task
 // In any of these aforementioned cases, the agent in question should terminate
 // immediately and cease running any tasks on its host.
-func (as *APIServer) taskFinished(w http.ResponseWriter, t *task.Task, finishTime time.Time) {
+func (as *APIServer) taskFinished(w http.ResponseWriter, t *task.Task, details *apimodels.TaskEndDetail,
+	finishTime time.Time) {
 	taskEndResponse := &apimodels.TaskEndResponse{}
 
 	// a. fetch the host this task just completed on to see if it's
@@ -408,9 +412,28 @@ func (as *APIServer) taskFinished(w http.ResponseWriter, t *task.Task, finishTim
 		return
 	}
 
+	// static hosts with repeated system failures are taken out of service
+	if recordHostHealth(host, t, details) {
+		markHostRunningTaskFinished(host, t, "")
+		message := fmt.Sprintf("Host %v - running %v - has been quarantined. Agent will terminate",
+			t.HostId, t.Id)
+		evergreen.Logger.Logf(slogger.INFO, message)
+		taskEndResponse.Message = message
+		as.WriteJSON(w, http.StatusOK, taskEndResponse)
+		return
+	}
+
 	// task cost calculations have no impact on task results, so do them in their own goroutine
 	go as.updateTaskCost(t, host, finishTime)
 
+	// a long-running agent that is ending a task is still alive, even though
+	// it has not polled for a task while running this one
+	if host.Distro.PullAgent {
+		if err = host.SetAgentContact(host.AgentRevision, finishTime); err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error recording agent contact for host %v: %v", host.Id, err)
+		}
+	}
+
 	// b. check if the agent needs to be rebuilt
 	taskRunnerInstance := taskrunner.NewTaskRunner(&as.Settings)
 	agentRevision, err := taskRunnerInstance.HostGateway.GetAgentRevision()
@@ -454,6 +477,25 @@ func (as *APIServer) taskFinished(w http.ResponseWriter, t *task.Task, finishTim
 	as.WriteJSON(w, http.StatusOK, taskEndResponse)
 }
 
+// recordHostHealth counts a system failure against a static host, or clears
+// its failures if the task succeeded. Returns true if the host has been
+// quarantined. Errors are logged, and leave the host in service.
+func recordHostHealth(h *host.Host, t *task.Task, details *apimodels.TaskEndDetail) bool {
+	switch {
+	case details.Status == evergreen.TaskSucceeded:
+		if err := static.RecordSuccess(h); err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error clearing failures for host %v: %v", h.Id, err)
+		}
+	case details.Status == evergreen.TaskFailed && details.Type == model.SystemCommandType:
+		quarantined, err := static.RecordFailure(h, fmt.Sprintf("system failure in task %v", t.Id))
+		if err != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Error recording system failure for host %v: %v", h.Id, err)
+		}
+		return quarantined
+	}
+	return false
+}
+
 // getNextDistroTask fetches the next task to run for the given distro and marks
 // the task as dispatched in the given host's document
 func getNextDistroTask(currentTask *task.Task, host *host.Host) (
@@ -559,6 +601,10 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 		TaskId:          t.Id,
 		TaskDisplayName: t.DisplayName,
 		BuildId:         t.BuildId,
+		Project:         t.Project,
+		Version:         t.Version,
+		Requester:       t.Requester,
+		CreateTime:      time.Now(),
 	}
 
 	err := util.ReadJSONInto(r.Body, &entry.Files)
@@ -569,6 +615,7 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	fmt.Printf("file entry is %#v\n", entry)
+	artifacts.VerifyLocations(t.Project, entry.Files)
 
 	if err := entry.Upsert(); err != nil {
 		message := fmt.Sprintf("Error updating artifact file info for task %v: %v", t.Id, err)
@@ -600,6 +647,25 @@ func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 	as.WriteJSON(w, http.StatusOK, "Logs added")
 }
 
+// AddSystemStats stores a batch of the resource usage samples that the agent
+// took on its host while running the task.
+func (as *APIServer) AddSystemStats(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	stats := []model.SystemStats{}
+
+	if err := util.ReadJSONInto(r.Body, &stats); err != nil {
+		http.Error(w, "unable to read system stats from request", http.StatusBadRequest)
+		return
+	}
+
+	if err := model.InsertSystemStats(t.Id, t.Execution, t.HostId, stats); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	as.WriteJSON(w, http.StatusOK, "System stats added")
+}
+
 // FetchTask loads the task from the database and sends it to the requester.
 func (as *APIServer) FetchTask(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
@@ -983,6 +1049,8 @@ func (as *APIServer) Handler() (http.Handler, error) {
 	// Hosts callback
 	host := r.PathPrefix("/host/{tag:[\\w_\\-\\@]+}/").Subrouter()
 	host.HandleFunc("/ready/{status}", as.hostReady).Methods("POST")
+	host.HandleFunc("/agent/next_task", as.checkHost(as.NextTask)).Methods("POST")
+	host.HandleFunc("/agent/binary", as.checkHost(as.AgentBinary)).Methods("GET")
 
 	// Spawnhost routes - creating new hosts, listing existing hosts, listing distros
 	spawns := apiRootOld.PathPrefix("/spawns/").Subrouter()
@@ -994,6 +1062,7 @@ func (as *APIServer) Handler() (http.Handler, error) {
 	taskRouter.HandleFunc("/start", as.checkTask(true, as.StartTask)).Methods("POST")
 	taskRouter.HandleFunc("/end", as.checkTask(true, as.EndTask)).Methods("POST")
 	taskRouter.HandleFunc("/log", as.checkTask(true, as.AppendTaskLog)).Methods("POST")
+	taskRouter.HandleFunc("/system_stats", as.checkTask(true, as.AddSystemStats)).Methods("POST")
 	taskRouter.HandleFunc("/heartbeat", as.checkTask(true, as.Heartbeat)).Methods("POST")
 	taskRouter.HandleFunc("/results", as.checkTask(true, as.AttachResults)).Methods("POST")
 	taskRouter.HandleFunc("/test_logs", as.checkTask(true, as.AttachTestLog)).Methods("POST")
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/taskrunner"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/context"
+	"github.com/tychoish/grip/slogger"
+)
+
+type hostKey int
+
+const apiHostKey hostKey = 0
+
+// MustHaveHost gets the host attached to the request by checkHost, panicking
+// if there is none.
+func MustHaveHost(r *http.Request) *host.Host {
+	if rv := context.Get(r, apiHostKey); rv != nil {
+		return rv.(*host.Host)
+	}
+	panic("no host attached to request")
+}
+
+// checkHost finds the host with the id in the request, and checks that the
+// request carries the secret of the host's long-running agent.
+func (as *APIServer) checkHost(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, err := getHostFromRequest(r)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		secret := r.Header.Get(evergreen.HostSecretHeader)
+		if h.Secret == "" || secret != h.Secret {
+			evergreen.Logger.Logf(slogger.ERROR, "Wrong secret sent for host %v", h.Id)
+			http.Error(w, "wrong secret!", http.StatusConflict)
+			return
+		}
+
+		context.Set(r, apiHostKey, h)
+		next(w, r)
+	}
+}
+
+// NextTask records that the host's long-running agent is alive and running
+// the given revision, and returns the task assigned to the host, if any. No
+// task is returned if the agent needs to be updated first.
+func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
+	h := MustHaveHost(r)
+
+	req := &apimodels.NextTaskRequest{}
+	if err := util.ReadJSONInto(r.Body, req); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.SetAgentContact(req.AgentRevision, time.Now()); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &apimodels.NextTaskResponse{}
+	if h.Status != evergreen.HostRunning {
+		resp.ShouldExit = true
+		resp.Message = fmt.Sprintf("host %v is in state '%v'", h.Id, h.Status)
+		as.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	agentRevision, err := taskrunner.NewAgentHostGateway(&as.Settings).GetAgentRevision()
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	resp.AgentRevision = agentRevision
+	if req.AgentRevision != agentRevision {
+		resp.Message = "agent needs to be updated"
+		as.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	if h.RunningTask == "" {
+		resp.Message = "no task assigned to host"
+		as.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+	t, err := task.FindOne(task.ById(h.RunningTask))
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if t == nil {
+		as.LoggedError(w, r, http.StatusInternalServerError,
+			fmt.Errorf("task %v assigned to host %v not found", h.RunningTask, h.Id))
+		return
+	}
+	// a task that has already started is being run by another agent
+	if t.Status != evergreen.TaskDispatched {
+		resp.Message = fmt.Sprintf("task %v assigned to host is in state '%v'", t.Id, t.Status)
+		as.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	resp.TaskId = t.Id
+	resp.TaskSecret = t.Secret
+	as.WriteJSON(w, http.StatusOK, resp)
+}
+
+// AgentBinary serves the current agent executable for the host's distro.
+func (as *APIServer) AgentBinary(w http.ResponseWriter, r *http.Request) {
+	h := MustHaveHost(r)
+
+	path := taskrunner.NewAgentHostGateway(&as.Settings).AgentExecutablePath(&h.Distro)
+	if _, err := os.Stat(path); err != nil {
+		as.LoggedError(w, r, http.StatusNotFound, fmt.Errorf("no agent for distro %v: %v", h.Distro.Id, err))
+		return
+	}
+	http.ServeFile(w, r, path)
+}
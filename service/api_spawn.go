@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen"
@@ -215,7 +216,7 @@ func (as *APIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
 
 	user := GetUser(r)
 	if user == nil || user.Id != host.StartedBy {
-		message := fmt.Sprintf("Only %v is authorized to terminate this host", host.StartedBy)
+		message := fmt.Sprintf("Only %v is authorized to modify this host", host.StartedBy)
 		http.Error(w, message, http.StatusUnauthorized)
 		return
 	}
@@ -238,8 +239,46 @@ func (as *APIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		as.WriteJSON(w, http.StatusOK, spawnResponse{HostInfo: *host})
+	case "stop":
+		spawner := spawn.New(&as.Settings)
+		if err = spawner.StopHost(host, user.Id); err != nil {
+			as.spawnActionError(w, r, err)
+			return
+		}
+		as.WriteJSON(w, http.StatusOK, spawnResponse{HostInfo: *host})
+	case "start":
+		spawner := spawn.New(&as.Settings)
+		if err = spawner.ValidateStart(host); err != nil {
+			as.spawnActionError(w, r, err)
+			return
+		}
+		// as in the UI, wait for the host to come back up in the background
+		go startSpawnHost(&as.Settings, spawner, *host, user.Id)
+		as.WriteJSON(w, http.StatusOK, spawnResponse{HostInfo: *host})
+	case "extend":
+		addHours, err := util.GetIntValue(r, "add_hours", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		spawner := spawn.New(&as.Settings)
+		if err = spawner.ExtendExpiration(host, user.Id, time.Duration(addHours)*time.Hour); err != nil {
+			as.spawnActionError(w, r, err)
+			return
+		}
+		as.WriteJSON(w, http.StatusOK, spawnResponse{HostInfo: *host})
 	default:
 		http.Error(w, fmt.Sprintf("Unrecognized action %v", hostAction), http.StatusBadRequest)
 	}
 
 }
+
+// spawnActionError writes the error from a spawn host modification, treating
+// invalid requests as client errors.
+func (as *APIServer) spawnActionError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := err.(spawn.BadOptionsErr); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	as.LoggedError(w, r, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/buildbaron"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+)
+
+// taskFailureMatches returns the open JIRA tickets that may describe a failed
+// task's failure.
+func (uis *UIServer) taskFailureMatches(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveProjectContext(r)
+	t := projCtx.Task
+	if t == nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if t.Status != evergreen.TaskFailed {
+		uis.WriteJSON(w, http.StatusOK, []buildbaron.Suggestion{})
+		return
+	}
+
+	conf := uis.Settings.Jira
+	if conf.Host == "" {
+		http.Error(w, "JIRA is not configured", http.StatusNotFound)
+		return
+	}
+	projects := buildbaron.SearchProjects(conf, projCtx.ProjectRef)
+	if len(projects) == 0 {
+		http.Error(w, "No JIRA projects are configured for failure matching", http.StatusNotFound)
+		return
+	}
+
+	jira := thirdparty.NewJiraHandler(conf.Host, conf.Username, conf.Password)
+	suggestions, err := buildbaron.NewMatcher(&jira, projects).Suggest(buildbaron.FailureFromTask(t))
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error matching failure of task '%v': %v", t.Id, err))
+		return
+	}
+	uis.WriteJSON(w, http.StatusOK, suggestions)
+}
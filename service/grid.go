@@ -6,9 +6,11 @@ import (
 	"strconv"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/annotations"
 	"github.com/evergreen-ci/evergreen/model/grid"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/gorilla/mux"
 )
 
@@ -40,6 +42,7 @@ func (uis *UIServer) grid(w http.ResponseWriter, r *http.Request) {
 	var cells grid.Grid
 	var failures grid.Failures
 	var revisionFailures grid.RevisionFailures
+	var taskAnnotations map[string][]annotations.Annotation
 	var depth int
 	var err error
 
@@ -91,11 +94,18 @@ func (uis *UIServer) grid(w http.ResponseWriter, r *http.Request) {
 			uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error fetching revision failures: %v", err))
 			return
 		}
+
+		taskAnnotations, err = gridAnnotations(cells, failures, revisionFailures)
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error fetching annotations: %v", err))
+			return
+		}
 	} else {
 		versions = make(map[string]version.Version)
 		cells = make(grid.Grid, 0)
 		failures = make(grid.Failures, 0)
 		revisionFailures = make(grid.RevisionFailures, 0)
+		taskAnnotations = make(map[string][]annotations.Annotation)
 	}
 	uis.WriteHTML(w, http.StatusOK, struct {
 		ProjectData      projectContext
@@ -103,6 +113,37 @@ func (uis *UIServer) grid(w http.ResponseWriter, r *http.Request) {
 		GridCells        grid.Grid
 		Failures         grid.Failures
 		RevisionFailures grid.RevisionFailures
+		Annotations      map[string][]annotations.Annotation
 		User             *user.DBUser
-	}{projCtx, versions, cells, failures, revisionFailures, GetUser(r)}, "base", "grid.html", "base_angular.html", "menu.html")
+	}{projCtx, versions, cells, failures, revisionFailures, taskAnnotations, GetUser(r)}, "base", "grid.html", "base_angular.html", "menu.html")
+}
+
+// gridAnnotations returns the annotations on the latest annotated execution
+// of each task shown on the grid, keyed by task id.
+func gridAnnotations(cells grid.Grid, failures grid.Failures, revisionFailures grid.RevisionFailures) (map[string][]annotations.Annotation, error) {
+	taskIds := []string{}
+	for _, cell := range cells {
+		for _, h := range cell.History {
+			taskIds = append(taskIds, h.Id)
+		}
+	}
+	for _, failure := range failures {
+		for _, v := range failure.Variants {
+			taskIds = append(taskIds, v.TaskId)
+		}
+	}
+	for _, revisionFailure := range revisionFailures {
+		for _, f := range revisionFailure.Failures {
+			taskIds = append(taskIds, f.TaskId)
+		}
+	}
+	if len(taskIds) == 0 {
+		return map[string][]annotations.Annotation{}, nil
+	}
+
+	found, err := annotations.Find(annotations.ByTaskIds(util.UniqueStrings(taskIds)))
+	if err != nil {
+		return nil, err
+	}
+	return annotations.LatestByTask(found), nil
 }
@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostinit"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -59,6 +61,11 @@ func (uis *UIServer) hostPage(w http.ResponseWriter, r *http.Request) {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	statusHistory, err := host.StatusHistory(id, time.Now())
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
 	runningTask := &task.Task{}
 	if h.RunningTask != "" {
 		runningTask, err = task.FindOne(task.ById(h.RunningTask))
@@ -70,16 +77,42 @@ func (uis *UIServer) hostPage(w http.ResponseWriter, r *http.Request) {
 
 	flashes := PopFlashes(uis.CookieStore, r, w)
 	uis.WriteHTML(w, http.StatusOK, struct {
-		Flashes     []interface{}
-		Events      []event.Event
-		Host        *host.Host
-		RunningTask *task.Task
-		User        *user.DBUser
-		ProjectData projectContext
-	}{flashes, events, h, runningTask, GetUser(r), projCtx},
+		Flashes       []interface{}
+		Events        []event.Event
+		StatusHistory []host.StatusPeriod
+		Host          *host.Host
+		RunningTask   *task.Task
+		User          *user.DBUser
+		ProjectData   projectContext
+	}{flashes, events, statusHistory, h, runningTask, GetUser(r), projCtx},
 		"base", "host.html", "base_angular.html", "menu.html")
 }
 
+// hostStatusHistory returns the statuses a host has been in, and how long it
+// spent in each.
+func (uis *UIServer) hostStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["host_id"]
+	h, err := host.FindOne(host.ById(id))
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if h == nil {
+		http.Error(w, "Host not found", http.StatusNotFound)
+		return
+	}
+
+	periods, err := host.StatusHistory(id, time.Now())
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	uis.WriteJSON(w, http.StatusOK, struct {
+		History []host.StatusPeriod      `json:"history"`
+		Totals  map[string]time.Duration `json:"totals"`
+	}{periods, host.TimeInStatuses(periods)})
+}
+
 func (uis *UIServer) hostsPage(w http.ResponseWriter, r *http.Request) {
 	projCtx := MustHaveProjectContext(r)
 
@@ -102,18 +135,18 @@ func (uis *UIServer) hostsPage(w http.ResponseWriter, r *http.Request) {
 }
 
 func (uis *UIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
-	_ = MustHaveUser(r)
+	u := MustHaveUser(r)
 
 	vars := mux.Vars(r)
 	id := vars["host_id"]
 
-	host, err := host.FindOne(host.ById(id))
+	h, err := host.FindOne(host.ById(id))
 	if err != nil {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	if host == nil {
+	if h == nil {
 		http.Error(w, "Host not found", http.StatusNotFound)
 		return
 	}
@@ -128,20 +161,35 @@ func (uis *UIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
 	// determine what action needs to be taken
 	switch opts.Action {
 	case "updateStatus":
-		currentStatus := host.Status
+		currentStatus := h.Status
 		newStatus := opts.Status
 		if !util.SliceContains(validUpdateToStatuses, newStatus) {
 			http.Error(w, fmt.Sprintf("'%v' is not a valid status", newStatus), http.StatusBadRequest)
 			return
 		}
-		err := host.SetStatus(newStatus)
+		err := h.SetStatus(newStatus)
 		if err != nil {
+			if host.IsStatusTransitionError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error updating host: %v", err))
 			return
 		}
-		msg := NewSuccessFlash(fmt.Sprintf("Host status successfully updated from '%v' to '%v'", currentStatus, host.Status))
+		msg := NewSuccessFlash(fmt.Sprintf("Host status successfully updated from '%v' to '%v'", currentStatus, h.Status))
 		PushFlash(uis.CookieStore, r, w, msg)
 		uis.WriteJSON(w, http.StatusOK, "Successfully updated host status")
+	case "healthCheck":
+		// the check can take minutes, so its outcome is left in the host's
+		// event log rather than waited for
+		hostInit := &hostinit.HostInit{Settings: &uis.Settings}
+		if err = hostInit.ReturnToService(h, u.Id); err != nil {
+			uis.WriteJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		PushFlash(uis.CookieStore, r, w, NewSuccessFlash("Health check started; its outcome will "+
+			"appear in the host's event log"))
+		uis.WriteJSON(w, http.StatusAccepted, "Health check started")
 	default:
 		uis.WriteJSON(w, http.StatusBadRequest, fmt.Sprintf("Unrecognized action: %v", opts.Action))
 	}
@@ -183,16 +231,41 @@ func (uis *UIServer) modifyHosts(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, fmt.Sprintf("Invalid status: %v", opts.Status), http.StatusBadRequest)
 			return
 		}
-		numHostsUpdated := 0
 
-		for _, host := range hosts {
-			err := host.SetStatus(newStatus)
+		// check every host before updating any, so that a bad request
+		// leaves them all as they were
+		invalid := []string{}
+		for _, h := range hosts {
+			if !host.CanTransition(h.Status, newStatus) {
+				invalid = append(invalid, (&host.StatusTransitionError{
+					HostId: h.Id, From: h.Status, To: newStatus}).Error())
+			}
+		}
+		if len(invalid) > 0 {
+			http.Error(w, strings.Join(invalid, "\n"), http.StatusBadRequest)
+			return
+		}
+
+		numHostsUpdated := 0
+		failed := []string{}
+		for _, h := range hosts {
+			err := h.SetStatus(newStatus)
 			if err != nil {
+				// the host's status changed since it was checked
+				if host.IsStatusTransitionError(err) {
+					failed = append(failed, err.Error())
+					continue
+				}
 				uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error updating host %v", err))
 				return
 			}
 			numHostsUpdated += 1
 		}
+		if len(failed) > 0 {
+			http.Error(w, fmt.Sprintf("%v host(s) status updated to '%v', but not:\n%v",
+				numHostsUpdated, newStatus, strings.Join(failed, "\n")), http.StatusConflict)
+			return
+		}
 		msg := NewSuccessFlash(fmt.Sprintf("%v host(s) status successfully updated to '%v'",
 			numHostsUpdated, newStatus))
 		PushFlash(uis.CookieStore, r, w, msg)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/alerts"
 	"github.com/evergreen-ci/evergreen/model"
@@ -56,7 +57,8 @@ func (uis *UIServer) projectsPage(w http.ResponseWriter, r *http.Request) {
 
 	// construct a json-marshaling friendly representation of our supported triggers
 	allTaskTriggers := []interface{}{}
-	for _, taskTrigger := range alerts.AvailableTaskFailTriggers {
+	triggers := append(append([]alerts.Trigger{}, alerts.AvailableTaskFailTriggers...), alerts.AvailableBudgetTriggers...)
+	for _, taskTrigger := range triggers {
 		allTaskTriggers = append(allTaskTriggers, struct {
 			Id      string `json:"id"`
 			Display string `json:"display"`
@@ -94,10 +96,20 @@ func (uis *UIServer) projectPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var budgetStatuses []model.BudgetStatus
+	if projRef != nil {
+		budgetStatuses, err = projRef.BudgetStatuses(time.Now())
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	data := struct {
-		ProjectRef  *model.ProjectRef
-		ProjectVars *model.ProjectVars
-	}{projRef, projVars}
+		ProjectRef     *model.ProjectRef
+		ProjectVars    *model.ProjectVars
+		BudgetStatuses []model.BudgetStatus
+	}{projRef, projVars, budgetStatuses}
 
 	// the project context has all projects so make the ui list using all projects
 	uis.WriteJSON(w, http.StatusOK, data)
@@ -133,18 +145,22 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	responseRef := struct {
-		Identifier         string            `json:"id"`
-		DisplayName        string            `json:"display_name"`
-		RemotePath         string            `json:"remote_path"`
-		BatchTime          int               `json:"batch_time"`
-		DeactivatePrevious bool              `json:"deactivate_previous"`
-		Branch             string            `json:"branch_name"`
-		ProjVarsMap        map[string]string `json:"project_vars"`
-		Enabled            bool              `json:"enabled"`
-		Private            bool              `json:"private"`
-		Owner              string            `json:"owner_name"`
-		Repo               string            `json:"repo_name"`
-		Admins             []string          `json:"admins"`
+		Identifier         string                  `json:"id"`
+		DisplayName        string                  `json:"display_name"`
+		RemotePath         string                  `json:"remote_path"`
+		BatchTime          int                     `json:"batch_time"`
+		DeactivatePrevious bool                    `json:"deactivate_previous"`
+		Branch             string                  `json:"branch_name"`
+		ProjVarsMap        map[string]string       `json:"project_vars"`
+		PrivateVars        map[string]bool         `json:"private_vars"`
+		Enabled            bool                    `json:"enabled"`
+		Private            bool                    `json:"private"`
+		Owner              string                  `json:"owner_name"`
+		Repo               string                  `json:"repo_name"`
+		Admins             []string                `json:"admins"`
+		DailyBudget        model.ProjectBudget     `json:"daily_budget"`
+		MonthlyBudget      model.ProjectBudget     `json:"monthly_budget"`
+		ArtifactRetention  model.ArtifactRetention `json:"artifact_retention"`
 		AlertConfig        map[string][]struct {
 			Provider string                 `json:"provider"`
 			Settings map[string]interface{} `json:"settings"`
@@ -168,6 +184,9 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.DeactivatePrevious = responseRef.DeactivatePrevious
 	projectRef.Repo = responseRef.Repo
 	projectRef.Admins = responseRef.Admins
+	projectRef.DailyBudget = responseRef.DailyBudget
+	projectRef.MonthlyBudget = responseRef.MonthlyBudget
+	projectRef.ArtifactRetention = responseRef.ArtifactRetention
 	projectRef.Identifier = id
 
 	projectRef.Alerts = map[string][]model.AlertConfig{}
@@ -189,7 +208,11 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//modify project vars if necessary
-	projectVars := model.ProjectVars{id, responseRef.ProjVarsMap}
+	projectVars := model.ProjectVars{
+		Id:          id,
+		Vars:        responseRef.ProjVarsMap,
+		PrivateVars: responseRef.PrivateVars,
+	}
 	_, err = projectVars.Upsert()
 
 	if err != nil {
@@ -256,6 +279,40 @@ func (uis *UIServer) addProject(w http.ResponseWriter, r *http.Request) {
 	uis.WriteJSON(w, http.StatusOK, data)
 }
 
+// overrideBudget lifts the hard limits of the budgets that the project is
+// over for the rest of their periods, letting its tasks run again.
+func (uis *UIServer) overrideBudget(w http.ResponseWriter, r *http.Request) {
+	MustHaveUser(r)
+
+	id := mux.Vars(r)["project_id"]
+	projectRef, err := model.FindOneProjectRef(id)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if projectRef == nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	if err = projectRef.OverrideBudget(now); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	budgetStatuses, err := projectRef.BudgetStatuses(now)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	data := struct {
+		ProjectRef     *model.ProjectRef
+		BudgetStatuses []model.BudgetStatus
+	}{projectRef, budgetStatuses}
+	uis.WriteJSON(w, http.StatusOK, data)
+}
+
 // setRevision sets the latest revision in the Repository
 // database to the revision sent from the projects page.
 func (uis *UIServer) setRevision(w http.ResponseWriter, r *http.Request) {
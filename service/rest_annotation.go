@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// DefaultAnnotationLimit is the most annotations returned by a search of
+	// a project's annotations, unless a limit is given.
+	DefaultAnnotationLimit = 100
+	// MaxAnnotationLimit is the most annotations a search may ask for.
+	MaxAnnotationLimit = DefaultAnnotationLimit * 10
+)
+
+// annotationInput holds the fields a user sets when annotating a task.
+type annotationInput struct {
+	Note             string `json:"note"`
+	IssueKey         string `json:"issue_key"`
+	Classification   string `json:"classification"`
+	SuspectedCulprit string `json:"suspected_culprit"`
+}
+
+// getTaskAnnotations returns a JSON response with the annotations on an
+// execution of a task. The execution defaults to the task's latest.
+func (restapi restAPI) getTaskAnnotations(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	t := projCtx.Task
+	if t == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding task"})
+		return
+	}
+	execution, err := util.GetIntValue(r, "execution", t.Execution)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	found, err := annotations.Find(annotations.ByTaskExecution(t.Id, execution))
+	if err != nil {
+		msg := fmt.Sprintf("Error finding annotations for task '%v': %v", t.Id, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, found)
+}
+
+// addTaskAnnotation annotates the latest execution of a task, and returns a
+// JSON response with the new annotation.
+func (restapi restAPI) addTaskAnnotation(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	user := MustHaveUser(r)
+	t := projCtx.Task
+	if t == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding task"})
+		return
+	}
+
+	input := annotationInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	annotation := &annotations.Annotation{
+		TaskId:           t.Id,
+		TaskExecution:    t.Execution,
+		Project:          t.Project,
+		BuildVariant:     t.BuildVariant,
+		TaskName:         t.DisplayName,
+		Note:             input.Note,
+		IssueKey:         input.IssueKey,
+		Classification:   input.Classification,
+		SuspectedCulprit: input.SuspectedCulprit,
+		Author:           user.Id,
+	}
+	if err := annotation.Validate(); err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+	if err := annotation.Insert(); err != nil {
+		msg := fmt.Sprintf("Error annotating task '%v': %v", t.Id, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusCreated, annotation)
+}
+
+// removeTaskAnnotation deletes an annotation on a task. Only the annotation's
+// author or a super user may delete it.
+func (restapi restAPI) removeTaskAnnotation(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	user := MustHaveUser(r)
+	t := projCtx.Task
+	if t == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding task"})
+		return
+	}
+
+	annotationId := mux.Vars(r)["annotation_id"]
+	if !bson.IsObjectIdHex(annotationId) {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: "invalid annotation id"})
+		return
+	}
+	annotation, err := annotations.FindOne(annotations.ById(bson.ObjectIdHex(annotationId)))
+	if err != nil {
+		msg := fmt.Sprintf("Error finding annotation '%v': %v", annotationId, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	if annotation == nil || annotation.TaskId != t.Id {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding annotation"})
+		return
+	}
+
+	if annotation.Author != user.Id && !auth.IsSuperUser(restapi.GetSettings(), user) {
+		restapi.WriteJSON(w, http.StatusForbidden, responseError{Message: "only the author may remove an annotation"})
+		return
+	}
+
+	if err = annotations.Remove(annotation.Id); err != nil {
+		msg := fmt.Sprintf("Error removing annotation '%v': %v", annotationId, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, annotation)
+}
+
+// getProjectAnnotations returns a JSON response with a project's most recent
+// annotations, optionally narrowed down by the "variant", "task",
+// "issue_key", "classification" and "culprit" query parameters.
+func (restapi restAPI) getProjectAnnotations(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	if projCtx.ProjectRef == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding project"})
+		return
+	}
+	limit, err := util.GetIntValue(r, "limit", DefaultAnnotationLimit)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+	// a limit of zero would return every annotation in the project
+	if limit < 1 || limit > MaxAnnotationLimit {
+		msg := fmt.Sprintf("limit must be between 1 and %v", MaxAnnotationLimit)
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: msg})
+		return
+	}
+
+	filter := annotations.Filter{
+		Project:          projCtx.ProjectRef.Identifier,
+		BuildVariant:     r.FormValue("variant"),
+		TaskName:         r.FormValue("task"),
+		IssueKey:         r.FormValue("issue_key"),
+		Classification:   r.FormValue("classification"),
+		SuspectedCulprit: r.FormValue("culprit"),
+	}
+	found, err := annotations.Find(annotations.ByFilter(filter).Limit(limit))
+	if err != nil {
+		msg := fmt.Sprintf("Error finding annotations for project '%v': %v", filter.Project, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, found)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/cloud/providers"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// maxCostReportDays is the furthest back a cost report can go.
+const maxCostReportDays = 90
+
+// hostCostFunc returns a function that calculates the cost of a host with
+// its cloud provider. Hosts whose providers can't calculate costs cost
+// nothing.
+func hostCostFunc(settings *evergreen.Settings) model.HostCostFunc {
+	return func(h *host.Host, start, end time.Time) (float64, error) {
+		manager, err := providers.GetCloudManager(h.Provider, settings)
+		if err != nil {
+			return 0, err
+		}
+		calc, ok := manager.(cloud.CloudCostCalculator)
+		if !ok {
+			return 0, nil
+		}
+		return calc.CostForDuration(h, start, end)
+	}
+}
+
+// getCostReport returns the cost of the tasks that finished, and of idle
+// host time, over the last number of days, rolled up by the requested
+// dimensions.
+func (restapi restAPI) getCostReport(w http.ResponseWriter, r *http.Request) {
+	daysBack, err := util.GetIntValue(r, "numberDays", 7)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+	if daysBack <= 0 || daysBack > maxCostReportDays {
+		restapi.WriteJSON(w, http.StatusBadRequest,
+			responseError{Message: fmt.Sprintf("days back must be between 1 and %v", maxCostReportDays)})
+		return
+	}
+
+	isCSV, err := util.GetBoolValue(r, "csv", true)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	groupBy := []string{}
+	if g := r.FormValue("group_by"); g != "" {
+		groupBy = strings.Split(g, ",")
+	}
+	if err = model.ValidateCostGroupings(groupBy); err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	settings := restapi.GetSettings()
+	end := time.Now()
+	start := end.Add(-24 * time.Hour * time.Duration(daysBack))
+	report, err := model.CreateCostReport(start, end, r.FormValue("project"), groupBy, hostCostFunc(&settings))
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusInternalServerError,
+			responseError{Message: fmt.Sprintf("error creating cost report: %v", err)})
+		return
+	}
+
+	if isCSV {
+		util.WriteCSVResponse(w, http.StatusOK, report.Rollups)
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, report)
+}
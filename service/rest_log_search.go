@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/tychoish/grip/slogger"
+)
+
+// RestLogSearchResults is the response for a log search.
+type RestLogSearchResults struct {
+	Pattern   string                 `json:"pattern"`
+	Truncated bool                   `json:"truncated"`
+	Matches   []model.LogSearchMatch `json:"matches"`
+}
+
+// logSearchOptionsFromRequest builds the search options from the request's
+// "q", "context", "type", "test_logs" and "limit" form values. Agent and
+// system logs are only searched for logged in users. The limit is capped at
+// model.MaxLogSearchLimit.
+func logSearchOptionsFromRequest(r *http.Request) (model.LogSearchOptions, error) {
+	opts := model.LogSearchOptions{}
+	var err error
+	if opts.Pattern, err = model.ParseLogSearchPattern(r.FormValue("q")); err != nil {
+		return opts, err
+	}
+	if opts.Context, err = util.GetIntValue(r, "context", 0); err != nil {
+		return opts, err
+	}
+	if opts.Limit, err = util.GetIntValue(r, "limit", model.DefaultLogSearchLimit); err != nil {
+		return opts, err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = model.DefaultLogSearchLimit
+	}
+	opts.Limit = util.Min(opts.Limit, model.MaxLogSearchLimit)
+	if opts.IncludeTestLogs, err = util.GetBoolValue(r, "test_logs", true); err != nil {
+		return opts, err
+	}
+
+	logType := strings.ToUpper(r.FormValue("type"))
+	switch logType {
+	case "", AllLogsType:
+		if GetUser(r) == nil {
+			opts.Types = []string{model.TaskLogPrefix}
+		}
+	case model.TaskLogPrefix:
+		opts.Types = []string{logType}
+	case model.AgentLogPrefix, model.SystemLogPrefix:
+		if GetUser(r) == nil {
+			return opts, fmt.Errorf("must be logged in to search %v logs", model.LogTypeName(logType))
+		}
+		opts.Types = []string{logType}
+	default:
+		return opts, fmt.Errorf("invalid log type '%v'", logType)
+	}
+	return opts, nil
+}
+
+// searchTaskLogs returns a JSON response with the lines of a task's logs that
+// match the requested pattern. The execution defaults to the task's latest.
+func (restapi restAPI) searchTaskLogs(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	t := projCtx.Task
+	if t == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding task"})
+		return
+	}
+
+	opts, err := logSearchOptionsFromRequest(r)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+	execution, err := util.GetIntValue(r, "execution", t.Execution)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	matches, err := model.SearchTaskLogs(t.Id, execution, withExtraMatch(opts))
+	if err != nil {
+		msg := fmt.Sprintf("Error searching logs for task '%v'", t.Id)
+		evergreen.Logger.Logf(slogger.ERROR, "%v: %v", msg, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, newLogSearchResults(opts, matches))
+}
+
+// searchVersionLogs returns a JSON response with the lines matching the
+// requested pattern across the logs of every task in a version.
+func (restapi restAPI) searchVersionLogs(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	v := projCtx.Version
+	if v == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding version"})
+		return
+	}
+
+	opts, err := logSearchOptionsFromRequest(r)
+	if err != nil {
+		restapi.WriteJSON(w, http.StatusBadRequest, responseError{Message: err.Error()})
+		return
+	}
+
+	matches, err := model.SearchVersionLogs(v.Id, withExtraMatch(opts))
+	if err != nil {
+		msg := fmt.Sprintf("Error searching logs for version '%v'", v.Id)
+		evergreen.Logger.Logf(slogger.ERROR, "%v: %v", msg, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+	restapi.WriteJSON(w, http.StatusOK, newLogSearchResults(opts, matches))
+}
+
+// withExtraMatch returns the options with room for one more match than the
+// limit, so that results can be reported as truncated only if there were more
+// matches than were returned.
+func withExtraMatch(opts model.LogSearchOptions) model.LogSearchOptions {
+	opts.Limit++
+	return opts
+}
+
+// newLogSearchResults returns up to the limit of the matches of a search
+// that was run withExtraMatch.
+func newLogSearchResults(opts model.LogSearchOptions, matches []model.LogSearchMatch) RestLogSearchResults {
+	truncated := len(matches) > opts.Limit
+	if truncated {
+		matches = matches[:opts.Limit]
+	}
+	return RestLogSearchResults{
+		Pattern:   opts.Pattern.String(),
+		Truncated: truncated,
+		Matches:   matches,
+	}
+}
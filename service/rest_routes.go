@@ -89,20 +89,28 @@ func AttachRESTHandler(root *mux.Router, service restAPIService) http.Handler {
 	rtr.HandleFunc("/projects/{project_id}/versions", rest.loadCtx(rest.getRecentVersions)).Name("recent_versions").Methods("GET")
 	rtr.HandleFunc("/projects/{project_id}/revisions/{revision}", rest.loadCtx(rest.getVersionInfoViaRevision)).Name("version_info_via_revision").Methods("GET")
 	rtr.HandleFunc("/projects/{project_id}/last_green", rest.loadCtx(rest.lastGreen)).Name("last_green_version").Methods("GET")
+	rtr.HandleFunc("/projects/{project_id}/annotations", rest.loadCtx(rest.getProjectAnnotations)).Name("project_annotations").Methods("GET")
 	rtr.HandleFunc("/patches/{patch_id}", rest.loadCtx(rest.getPatch)).Name("patch_info").Methods("GET")
 	rtr.HandleFunc("/patches/{patch_id}/config", rest.loadCtx(rest.getPatchConfig)).Name("patch_config").Methods("GET")
 	rtr.HandleFunc("/versions/{version_id}", rest.loadCtx(rest.getVersionInfo)).Name("version_info").Methods("GET")
 	rtr.HandleFunc("/versions/{version_id}", requireUser(rest.loadCtx(rest.modifyVersionInfo), nil)).Name("").Methods("PATCH")
 	rtr.HandleFunc("/versions/{version_id}/status", rest.loadCtx(rest.getVersionStatus)).Name("version_status").Methods("GET")
 	rtr.HandleFunc("/versions/{version_id}/config", rest.loadCtx(rest.getVersionConfig)).Name("version_config").Methods("GET")
+	rtr.HandleFunc("/versions/{version_id}/log_search", rest.loadCtx(rest.searchVersionLogs)).Name("version_log_search").Methods("GET")
 	rtr.HandleFunc("/builds/{build_id}", rest.loadCtx(rest.getBuildInfo)).Name("build_info").Methods("GET")
 	rtr.HandleFunc("/builds/{build_id}/status", rest.loadCtx(rest.getBuildStatus)).Name("build_status").Methods("GET")
 	rtr.HandleFunc("/tasks/{task_id}", rest.loadCtx(rest.getTaskInfo)).Name("task_info").Methods("GET")
 	rtr.HandleFunc("/tasks/{task_id}/status", rest.loadCtx(rest.getTaskStatus)).Name("task_status").Methods("GET")
+	rtr.HandleFunc("/tasks/{task_id}/expansions", requireUser(rest.loadCtx(rest.getTaskExpansions), nil)).Name("task_expansions").Methods("GET")
+	rtr.HandleFunc("/tasks/{task_id}/log_search", rest.loadCtx(rest.searchTaskLogs)).Name("task_log_search").Methods("GET")
+	rtr.HandleFunc("/tasks/{task_id}/annotations", rest.loadCtx(rest.getTaskAnnotations)).Name("task_annotations").Methods("GET")
+	rtr.HandleFunc("/tasks/{task_id}/annotations", requireUser(rest.loadCtx(rest.addTaskAnnotation), nil)).Name("add_task_annotation").Methods("POST")
+	rtr.HandleFunc("/tasks/{task_id}/annotations/{annotation_id}", requireUser(rest.loadCtx(rest.removeTaskAnnotation), nil)).Name("remove_task_annotation").Methods("DELETE")
 	rtr.HandleFunc("/tasks/{task_name}/history", rest.loadCtx(rest.getTaskHistory)).Name("task_history").Methods("GET")
 	rtr.HandleFunc("/scheduler/host_utilization", rest.loadCtx(rest.getHostUtilizationStats)).Name("host_utilization").Methods("GET")
 	rtr.HandleFunc("/scheduler/distro/{distro_id}/stats", rest.loadCtx(rest.getAverageSchedulerStats)).Name("avg_stats").Methods("GET")
 	rtr.HandleFunc("/scheduler/makespans", rest.loadCtx(rest.getOptimalAndActualMakespans)).Name("makespan").Methods("GET")
+	rtr.HandleFunc("/cost/report", requireUser(rest.getCostReport, nil)).Name("cost_report").Methods("GET")
 
 	return root
 
@@ -7,9 +7,11 @@ import (
 
 	"github.com/tychoish/grip/slogger"
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/store"
 )
 
 type taskStatusContent struct {
@@ -75,8 +77,12 @@ type taskTestLogURL struct {
 }
 
 type taskFile struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Expired     bool   `json:"expired,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
 }
 
 type taskTestResultsByName map[string]taskTestResult
@@ -161,11 +167,21 @@ func (restapi restAPI) getTaskInfo(w http.ResponseWriter, r *http.Request) {
 		return
 
 	}
+	// private files are only listed for logged in users, who get signed links
+	user := GetUser(r)
+	now := time.Now()
 	for _, entry := range entries {
 		for _, _file := range entry.Files {
+			if _file.Visibility == artifact.Private && user == nil {
+				continue
+			}
 			file := taskFile{
-				Name: _file.Name,
-				URL:  _file.Link,
+				Name:        _file.Name,
+				URL:         store.SignedLink(_file, restapi.GetSettings().SignedURLs, now),
+				Expired:     _file.Expired,
+				Size:        _file.Size,
+				SHA256:      _file.SHA256,
+				ContentType: _file.ContentType,
 			}
 			destTask.Files = append(destTask.Files, file)
 		}
@@ -217,3 +233,46 @@ func (restapi restAPI) getTaskStatus(w http.ResponseWriter, r *http.Request) {
 	return
 
 }
+
+// RestTaskExpansions holds the expansions a task runs with, minus the
+// project's private variables.
+type RestTaskExpansions struct {
+	TaskId     string            `json:"task_id"`
+	Execution  int               `json:"execution"`
+	Expansions map[string]string `json:"expansions"`
+}
+
+// getTaskExpansions returns a JSON response with the expansions the agent
+// would use to run the task, so that it can be reproduced elsewhere. Since
+// they include the project's variables, only the project's admins and
+// superusers may see them.
+func (restapi restAPI) getTaskExpansions(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveRESTContext(r)
+	t := projCtx.Task
+	if t == nil {
+		restapi.WriteJSON(w, http.StatusNotFound, responseError{Message: "error finding task"})
+		return
+	}
+
+	user := GetUser(r)
+	if !auth.IsSuperUser(restapi.GetSettings(), user) &&
+		(projCtx.ProjectRef == nil || !isAdmin(user, projCtx.ProjectRef)) {
+		restapi.WriteJSON(w, http.StatusForbidden,
+			responseError{Message: "only project admins may see a task's expansions"})
+		return
+	}
+
+	expansions, err := model.FindTaskExpansions(t)
+	if err != nil {
+		msg := fmt.Sprintf("Error finding expansions for task '%v'", t.Id)
+		evergreen.Logger.Logf(slogger.ERROR, "%v: %v", msg, err)
+		restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+		return
+	}
+
+	restapi.WriteJSON(w, http.StatusOK, RestTaskExpansions{
+		TaskId:     t.Id,
+		Execution:  t.Execution,
+		Expansions: *expansions,
+	})
+}
@@ -21,10 +21,11 @@ import (
 )
 
 const (
-	HostPasswordUpdate         = "updateRDPPassword"
-	HostExpirationExtension    = "extendHostExpiration"
-	HostTerminate              = "terminate"
-	MaxExpirationDurationHours = 24 * 7 // 7 days
+	HostPasswordUpdate      = "updateRDPPassword"
+	HostExpirationExtension = "extendHostExpiration"
+	HostTerminate           = "terminate"
+	HostStop                = "stop"
+	HostStart               = "start"
 )
 
 func (uis *UIServer) spawnPage(w http.ResponseWriter, r *http.Request) {
@@ -168,7 +169,7 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 }
 
 func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
-	_ = MustHaveUser(r)
+	u := MustHaveUser(r)
 	updateParams := struct {
 		Action   string `json:"action"`
 		HostId   string `json:"host_id"`
@@ -227,32 +228,64 @@ func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "bad hours param", http.StatusBadRequest)
 			return
 		}
-		// ensure this request is valid
-		addtHourDuration := time.Duration(addtHours) * time.Hour
-		futureExpiration := host.ExpirationTime.Add(addtHourDuration)
-		expirationExtensionDuration := futureExpiration.Sub(time.Now()).Hours()
-		if expirationExtensionDuration > MaxExpirationDurationHours {
-			http.Error(w, fmt.Sprintf("Can not extend %v expiration by %v hours. "+
-				"Maximum extension is limited to %v hours", hostId,
-				int(expirationExtensionDuration), MaxExpirationDurationHours), http.StatusBadRequest)
-			return
-
-		}
-		if err = host.SetExpirationTime(futureExpiration); err != nil {
-			uis.LoggedError(w, r, http.StatusInternalServerError, fmt.Errorf("Error extending host expiration time: %v", err))
+		spawner := spawn.New(&uis.Settings)
+		if err = spawner.ExtendExpiration(host, u.Id, time.Duration(addtHours)*time.Hour); err != nil {
+			if _, ok := err.(spawn.BadOptionsErr); ok {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
 		}
 		PushFlash(uis.CookieStore, r, w, NewSuccessFlash(fmt.Sprintf("Host expiration "+
 			"extension successful; %v will expire on %v", hostId,
-			futureExpiration.Format(time.RFC850))))
+			host.ExpirationTime.Format(time.RFC850))))
 		uis.WriteJSON(w, http.StatusOK, "Successfully extended host expiration time")
 		return
+	case HostStop:
+		spawner := spawn.New(&uis.Settings)
+		if err = spawner.StopHost(host, u.Id); err != nil {
+			if _, ok := err.(spawn.BadOptionsErr); ok {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		PushFlash(uis.CookieStore, r, w, NewSuccessFlash(fmt.Sprintf("Host %v stopped", hostId)))
+		uis.WriteJSON(w, http.StatusOK, "host stopped")
+		return
+	case HostStart:
+		spawner := spawn.New(&uis.Settings)
+		if err = spawner.ValidateStart(host); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// starting can take minutes while we wait for the host to come
+		// back up, so do it in the background and email the user on failure
+		go startSpawnHost(&uis.Settings, spawner, *host, u.Id)
+		PushFlash(uis.CookieStore, r, w, NewSuccessFlash(fmt.Sprintf("Starting host %v", hostId)))
+		uis.WriteJSON(w, http.StatusOK, "host starting")
+		return
 	default:
 		http.Error(w, fmt.Sprintf("Unrecognized action: %v", updateParams.Action), http.StatusBadRequest)
 		return
 	}
 }
 
+// startSpawnHost starts a stopped spawn host, notifying its owner if the host
+// could not be started.
+func startSpawnHost(settings *evergreen.Settings, spawner spawn.Spawn, h host.Host, userId string) {
+	if err := spawner.StartHost(&h, userId); err != nil {
+		evergreen.Logger.Logf(slogger.ERROR, "Error starting spawn host %v: %v", h.Id, err)
+		mailErr := notify.TrySendNotificationToUser(h.StartedBy, "Starting host failed",
+			fmt.Sprintf("Host %v could not be started: %v", h.Id, err), notify.ConstructMailer(settings.Notify))
+		if mailErr != nil {
+			evergreen.Logger.Logf(slogger.ERROR, "Failed to send notification: %v", mailErr)
+		}
+	}
+}
+
 // constructPwdUpdateCommand returns a RemoteCommand struct used to
 // set the RDP password on a remote windows machine.
 func constructPwdUpdateCommand(settings *evergreen.Settings, hostObj *host.Host,
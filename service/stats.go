@@ -93,6 +93,19 @@ func (uis *UIServer) taskTimingPage(w http.ResponseWriter, r *http.Request) {
 	uis.WriteHTML(w, http.StatusOK, data, "base", "task_timing.html", "base_angular.html", "menu.html")
 }
 
+// costPage shows the cost of tasks and idle hosts, loading the report itself
+// from the REST API.
+func (uis *UIServer) costPage(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveProjectContext(r)
+
+	data := struct {
+		ProjectData projectContext
+		User        *user.DBUser
+	}{projCtx, GetUser(r)}
+
+	uis.WriteHTML(w, http.StatusOK, data, "base", "cost.html", "base_angular.html", "menu.html")
+}
+
 // taskTimingJSON sends over the task data for a certain task of a certain build variant
 func (uis *UIServer) taskTimingJSON(w http.ResponseWriter, r *http.Request) {
 	projCtx := MustHaveProjectContext(r)
@@ -200,6 +200,11 @@ func (uis *UIServer) taskPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// process command lines can be sensitive, like the system logs
+	if GetUser(r) == nil {
+		task.TaskEndDetails.Processes = nil
+	}
+
 	task.DependsOn = deps
 	task.TaskWaiting = taskWaiting
 	task.MinQueuePos, err = model.FindMinimumQueuePositionForTask(task.Id)
@@ -481,6 +486,33 @@ func (uis *UIServer) taskLog(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// taskSystemStats returns the resource usage samples taken on the host while
+// an execution of the task ran, along with their peaks.
+func (uis *UIServer) taskSystemStats(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveProjectContext(r)
+
+	if projCtx.Task == nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	execution, err := strconv.Atoi(mux.Vars(r)["execution"])
+	if err != nil {
+		http.Error(w, "Invalid execution number", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := model.FindSystemStats(projCtx.Task.Id, execution)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	uis.WriteJSON(w, http.StatusOK, struct {
+		Stats   []model.SystemStats      `json:"stats"`
+		Summary model.SystemStatsSummary `json:"summary"`
+	}{stats, model.SummarizeSystemStats(stats)})
+}
+
 func (uis *UIServer) taskLogRaw(w http.ResponseWriter, r *http.Request) {
 	projCtx := MustHaveProjectContext(r)
 
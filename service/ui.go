@@ -130,6 +130,8 @@ func (uis *UIServer) NewRouter() (*mux.Router, error) {
 	r.HandleFunc("/tasks/{task_id}", requireLogin(uis.loadCtx(uis.taskModify))).Methods("PUT")
 	r.HandleFunc("/json/task_log/{task_id}", uis.loadCtx(uis.taskLog))
 	r.HandleFunc("/json/task_log/{task_id}/{execution}", uis.loadCtx(uis.taskLog))
+	r.HandleFunc("/json/task_system_stats/{task_id}/{execution}", requireLogin(uis.loadCtx(uis.taskSystemStats))).Methods("GET")
+	r.HandleFunc("/json/task_failure_matches/{task_id}", requireLogin(uis.loadCtx(uis.taskFailureMatches))).Methods("GET")
 	r.HandleFunc("/task_log_raw/{task_id}/{execution}", uis.loadCtx(uis.taskLogRaw))
 
 	// Test Logs
@@ -152,6 +154,7 @@ func (uis *UIServer) NewRouter() (*mux.Router, error) {
 	r.HandleFunc("/hosts", requireLogin(uis.loadCtx(uis.modifyHosts))).Methods("PUT")
 	r.HandleFunc("/host/{host_id}", requireLogin(uis.loadCtx(uis.hostPage))).Methods("GET")
 	r.HandleFunc("/host/{host_id}", requireLogin(uis.loadCtx(uis.modifyHost))).Methods("PUT")
+	r.HandleFunc("/json/host_status_history/{host_id}", requireLogin(uis.loadCtx(uis.hostStatusHistory))).Methods("GET")
 
 	// Distros
 	r.HandleFunc("/distros", requireLogin(uis.loadCtx(uis.distrosPage))).Methods("GET")
@@ -216,6 +219,7 @@ func (uis *UIServer) NewRouter() (*mux.Router, error) {
 	r.HandleFunc("/task_timing/{project_id}", requireLogin(uis.loadCtx(uis.taskTimingPage))).Methods("GET")
 	r.HandleFunc("/json/task_timing/{project_id}/{build_variant}/{request}/{task_name}", requireLogin(uis.loadCtx(uis.taskTimingJSON))).Methods("GET")
 	r.HandleFunc("/json/task_timing/{project_id}/{build_variant}/{request}", requireLogin(uis.loadCtx(uis.taskTimingJSON))).Methods("GET")
+	r.HandleFunc("/cost", requireLogin(uis.loadCtx(uis.costPage))).Methods("GET")
 
 	// Project routes
 	r.HandleFunc("/projects", requireLogin(uis.loadCtx(uis.projectsPage))).Methods("GET")
@@ -223,6 +227,7 @@ func (uis *UIServer) NewRouter() (*mux.Router, error) {
 	r.HandleFunc("/project/{project_id}", uis.loadCtx(uis.requireAdmin(uis.modifyProject))).Methods("POST")
 	r.HandleFunc("/project/{project_id}", uis.loadCtx(uis.requireAdmin(uis.addProject))).Methods("PUT")
 	r.HandleFunc("/project/{project_id}/repo_revision", uis.loadCtx(uis.requireAdmin(uis.setRevision))).Methods("PUT")
+	r.HandleFunc("/project/{project_id}/budget/override", uis.loadCtx(uis.requireAdmin(uis.overrideBudget))).Methods("POST")
 
 	// REST API
 	AttachRESTHandler(r, uis)
@@ -15,14 +15,17 @@ import (
 	"github.com/evergreen-ci/evergreen/cloud/providers/ec2"
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
 	"gopkg.in/yaml.v2"
 )
 
 const (
 	MaxPerUser        = 3
 	DefaultExpiration = time.Duration(24 * time.Hour)
+	MaxExpiration     = time.Duration(7 * 24 * time.Hour)
 )
 
 var SpawnLimitErr = errors.New("User is already running the max allowed # of spawn hosts")
@@ -189,3 +192,73 @@ func (sm Spawn) CreateHost(so Options, owner *user.DBUser) error {
 
 	return nil
 }
+
+// StopHost stops a running spawn host without terminating it. Its disks are
+// preserved, so it can later be started again with StartHost.
+func (sm Spawn) StopHost(h *host.Host, userId string) error {
+	if h.Status != evergreen.HostRunning {
+		return BadOptionsErr{fmt.Sprintf("host %v is %v, only running hosts can be stopped", h.Id, h.Status)}
+	}
+	cloudHost, err := providers.GetCloudHost(h, sm.settings)
+	if err != nil {
+		return err
+	}
+	if !cloudHost.CanStopAndStart() {
+		return BadOptionsErr{fmt.Sprintf("hosts of distro %v can not be stopped", h.Distro.Id)}
+	}
+	if err = cloudHost.StopInstance(); err != nil {
+		return fmt.Errorf("error stopping host %v: %v", h.Id, err)
+	}
+	event.LogHostStopped(h.Id, userId)
+	return nil
+}
+
+// ValidateStart returns an instance of BadOptionsErr if the host can not be
+// started with StartHost.
+func (sm Spawn) ValidateStart(h *host.Host) error {
+	if h.Status != evergreen.HostStopped {
+		return BadOptionsErr{fmt.Sprintf("host %v is %v, only stopped hosts can be started", h.Id, h.Status)}
+	}
+	if !util.IsZeroTime(h.ExpirationTime) && h.ExpirationTime.Before(time.Now()) {
+		return BadOptionsErr{fmt.Sprintf("host %v expired at %v", h.Id, h.ExpirationTime)}
+	}
+	return nil
+}
+
+// StartHost starts a stopped spawn host, blocking until the provider reports
+// that it is up again.
+func (sm Spawn) StartHost(h *host.Host, userId string) error {
+	if err := sm.ValidateStart(h); err != nil {
+		return err
+	}
+	cloudHost, err := providers.GetCloudHost(h, sm.settings)
+	if err != nil {
+		return err
+	}
+	if err = cloudHost.StartInstance(); err != nil {
+		return fmt.Errorf("error starting host %v: %v", h.Id, err)
+	}
+	event.LogHostStarted(h.Id, userId)
+	return nil
+}
+
+// ExtendExpiration pushes back the expiration time of a spawn host, as long as
+// the new expiration time is no more than MaxExpiration from now.
+func (sm Spawn) ExtendExpiration(h *host.Host, userId string, extension time.Duration) error {
+	if extension <= 0 {
+		return BadOptionsErr{fmt.Sprintf("invalid extension %v", extension)}
+	}
+	if h.Status == evergreen.HostTerminated {
+		return BadOptionsErr{fmt.Sprintf("host %v is already terminated", h.Id)}
+	}
+	newExpiration := h.ExpirationTime.Add(extension)
+	if newExpiration.Sub(time.Now()) > MaxExpiration {
+		return BadOptionsErr{fmt.Sprintf("can not extend %v expiration by %v, "+
+			"hosts may not expire more than %v in the future", h.Id, extension, MaxExpiration)}
+	}
+	if err := h.SetExpirationTime(newExpiration); err != nil {
+		return fmt.Errorf("error extending host expiration time: %v", err)
+	}
+	event.LogHostExpirationExtended(h.Id, userId, newExpiration)
+	return nil
+}
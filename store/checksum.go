@@ -0,0 +1,107 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// ErrChecksumMismatch is returned when a file's contents don't match the
+// checksum recorded when it was stored.
+type ErrChecksumMismatch struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch for '%v': expected sha256 %v, got %v",
+		e.Path, e.Expected, e.Actual)
+}
+
+// FileChecksum returns the SHA-256 and size of the local file.
+func FileChecksum(localPath string) (string, int64, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	r := NewChecksumReader(f)
+	if _, err = io.Copy(ioutil.Discard, r); err != nil {
+		return "", 0, err
+	}
+	return r.Sum(), r.Size(), nil
+}
+
+// ChecksumReader computes the SHA-256 and size of everything read through
+// it.
+type ChecksumReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+// NewChecksumReader wraps the reader.
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{r: r, hash: sha256.New()}
+}
+
+func (c *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// Sum returns the hex-encoded SHA-256 of what has been read.
+func (c *ChecksumReader) Sum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// Size returns the number of bytes read.
+func (c *ChecksumReader) Size() int64 {
+	return c.size
+}
+
+// verifyingReader is a file's contents that are checked against its
+// checksum once they've all been read.
+type verifyingReader struct {
+	*ChecksumReader
+	closer   io.Closer
+	path     string
+	expected string
+}
+
+// NewVerifyingReader returns a reader for the file's contents that fails
+// with ErrChecksumMismatch at the end of the contents, rather than returning
+// io.EOF, if they don't match the expected SHA-256. A blank checksum isn't
+// checked.
+func NewVerifyingReader(rc io.ReadCloser, path, expected string) io.ReadCloser {
+	if expected == "" {
+		return rc
+	}
+	return &verifyingReader{
+		ChecksumReader: NewChecksumReader(rc),
+		closer:         rc,
+		path:           path,
+		expected:       expected,
+	}
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.ChecksumReader.Read(p)
+	if err == io.EOF {
+		if actual := v.Sum(); actual != v.expected {
+			return n, ErrChecksumMismatch{Path: v.path, Expected: v.expected, Actual: actual}
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.closer.Close()
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// sha256 of "contents"
+const contentsSum = "d1b2a59fbea7e20077af9f91b27e95e865061b270be03ff539ab3b73587882e8"
+
+func TestChecksums(t *testing.T) {
+	Convey("When checksumming contents", t, func() {
+		Convey("a local file's checksum and size should be found", func() {
+			f, err := ioutil.TempFile("", "checksum")
+			So(err, ShouldBeNil)
+			defer os.Remove(f.Name())
+			_, err = f.WriteString("contents")
+			So(err, ShouldBeNil)
+			So(f.Close(), ShouldBeNil)
+
+			sum, size, err := FileChecksum(f.Name())
+			So(err, ShouldBeNil)
+			So(sum, ShouldEqual, contentsSum)
+			So(size, ShouldEqual, 8)
+		})
+
+		Convey("contents matching their checksum should read cleanly", func() {
+			r := NewVerifyingReader(ioutil.NopCloser(strings.NewReader("contents")), "f", contentsSum)
+			contents, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "contents")
+		})
+
+		Convey("contents not matching their checksum should fail at the end", func() {
+			r := NewVerifyingReader(ioutil.NopCloser(strings.NewReader("corrupt")), "f", contentsSum)
+			_, err := ioutil.ReadAll(r)
+			So(err, ShouldHaveSameTypeAs, ErrChecksumMismatch{})
+		})
+
+		Convey("contents without a checksum should not be checked", func() {
+			r := NewVerifyingReader(ioutil.NopCloser(strings.NewReader("anything")), "f", "")
+			_, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+)
+
+// LocalStore keeps files in a directory on the API server's disk, or on a
+// mount shared by the API and UI servers, which serve them over HTTP.
+type LocalStore struct {
+	// Root is the directory holding the files.
+	Root string
+	// LinkRoot is the root of the links to the files.
+	LinkRoot string
+}
+
+// NewLocalStore returns a store for the given directory, whose files are
+// linked to under linkRoot.
+func NewLocalStore(root, linkRoot string) *LocalStore {
+	return &LocalStore{Root: root, LinkRoot: strings.TrimSuffix(linkRoot, "/")}
+}
+
+// Name returns "local".
+func (s *LocalStore) Name() string {
+	return Local
+}
+
+// CleanPath normalizes a remote path, so that it can't refer to anything
+// outside of the store.
+func CleanPath(remotePath string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(remotePath)), "/")
+	if cleaned == "" {
+		return "", fmt.Errorf("invalid artifact path '%v'", remotePath)
+	}
+	return cleaned, nil
+}
+
+// localPath returns where the file at the remote path is kept on disk.
+func (s *LocalStore) localPath(remotePath string) (string, string, error) {
+	cleaned, err := CleanPath(remotePath)
+	if err != nil {
+		return "", "", err
+	}
+	return cleaned, filepath.Join(s.Root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put copies the local file into the store.
+func (s *LocalStore) Put(localPath, remotePath string) (*artifact.File, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.PutReader(f, remotePath)
+}
+
+// PutReader writes the contents of the reader to the store. Files are
+// written to a temporary file first, so that a partial upload never replaces
+// a complete file.
+func (s *LocalStore) PutReader(r io.Reader, remotePath string) (*artifact.File, error) {
+	cleaned, dest, err := s.localPath(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory for %v: %v", cleaned, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), filepath.Base(dest)+".partial")
+	if err != nil {
+		return nil, fmt.Errorf("error creating %v: %v", cleaned, err)
+	}
+	checksum := NewChecksumReader(r)
+	_, err = io.Copy(tmp, checksum)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("error writing %v: %v", cleaned, err)
+	}
+	if err = os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("error writing %v: %v", cleaned, err)
+	}
+
+	return &artifact.File{
+		Link:        s.LinkRoot + "/" + cleaned,
+		Store:       Local,
+		Path:        cleaned,
+		Size:        checksum.Size(),
+		SHA256:      checksum.Sum(),
+		ContentType: mime.TypeByExtension(path.Ext(cleaned)),
+	}, nil
+}
+
+// Get opens the file at the remote path.
+func (s *LocalStore) Get(remotePath string) (io.ReadCloser, error) {
+	cleaned, src, err := s.localPath(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(src)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil, ErrNotFound{cleaned}
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete removes the file at the remote path. Deleting a file that isn't in
+// the store is not an error.
+func (s *LocalStore) Delete(remotePath string) error {
+	cleaned, src, err := s.localPath(remotePath)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(src); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting %v: %v", cleaned, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCleanPath(t *testing.T) {
+	Convey("When cleaning remote paths", t, func() {
+		Convey("relative components should not escape the store", func() {
+			cleaned, err := CleanPath("../../etc/passwd")
+			So(err, ShouldBeNil)
+			So(cleaned, ShouldEqual, "etc/passwd")
+
+			cleaned, err = CleanPath("/a/./b/../c.tgz")
+			So(err, ShouldBeNil)
+			So(cleaned, ShouldEqual, "a/c.tgz")
+		})
+		Convey("paths naming the root should be rejected", func() {
+			_, err := CleanPath("")
+			So(err, ShouldNotBeNil)
+			_, err = CleanPath("a/..")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestLocalStore(t *testing.T) {
+	Convey("With a local store in a temporary directory", t, func() {
+		root, err := ioutil.TempDir("", "local_store")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		s := NewLocalStore(root, "/plugin/artifacts/files/")
+
+		Convey("a file that was put should be gettable", func() {
+			file, err := s.PutReader(strings.NewReader("contents"), "proj/dir/out.txt")
+			So(err, ShouldBeNil)
+			So(file.Store, ShouldEqual, Local)
+			So(file.Path, ShouldEqual, "proj/dir/out.txt")
+			So(file.Link, ShouldEqual, "/plugin/artifacts/files/proj/dir/out.txt")
+			So(file.Size, ShouldEqual, 8)
+			So(file.SHA256, ShouldEqual, contentsSum)
+
+			reader, err := s.Get("proj/dir/out.txt")
+			So(err, ShouldBeNil)
+			defer reader.Close()
+			contents, err := ioutil.ReadAll(reader)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "contents")
+
+			Convey("and putting it again should replace it", func() {
+				_, err := s.PutReader(strings.NewReader("new"), "proj/dir/out.txt")
+				So(err, ShouldBeNil)
+				contents, err := ioutil.ReadFile(filepath.Join(root, "proj", "dir", "out.txt"))
+				So(err, ShouldBeNil)
+				So(string(contents), ShouldEqual, "new")
+			})
+		})
+
+		Convey("a deleted file should be gone", func() {
+			_, err := s.PutReader(strings.NewReader("contents"), "proj/out.txt")
+			So(err, ShouldBeNil)
+			So(s.Delete("proj/out.txt"), ShouldBeNil)
+			_, err = s.Get("proj/out.txt")
+			So(err, ShouldHaveSameTypeAs, ErrNotFound{})
+
+			Convey("and deleting it again should not be an error", func() {
+				So(s.Delete("proj/out.txt"), ShouldBeNil)
+			})
+		})
+
+		Convey("getting a missing file or a directory should return ErrNotFound", func() {
+			_, err := s.Get("proj/missing.txt")
+			So(err, ShouldHaveSameTypeAs, ErrNotFound{})
+
+			So(os.MkdirAll(filepath.Join(root, "proj", "dir"), 0755), ShouldBeNil)
+			_, err = s.Get("proj/dir")
+			So(err, ShouldHaveSameTypeAs, ErrNotFound{})
+		})
+	})
+}
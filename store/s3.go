@@ -0,0 +1,163 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+)
+
+// S3BaseURL is the root of the links to files put in S3.
+var S3BaseURL = "https://s3.amazonaws.com/"
+
+// S3Store keeps files in an S3 bucket.
+type S3Store struct {
+	Auth   aws.Auth
+	Bucket string
+	// Permissions is the canned ACL applied to files put in the bucket.
+	Permissions string
+	// ContentType is the MIME type of files put in the bucket.
+	ContentType string
+	// PartSize is the size of the parts that files larger than it are
+	// uploaded in. If zero, files are always uploaded whole.
+	PartSize int64
+}
+
+// NewS3Store returns a store for the given bucket.
+func NewS3Store(key, secret, bucket, permissions, contentType string) *S3Store {
+	return &S3Store{
+		Auth:        aws.Auth{AccessKey: key, SecretKey: secret},
+		Bucket:      bucket,
+		Permissions: permissions,
+		ContentType: contentType,
+	}
+}
+
+// Name returns "s3".
+func (s *S3Store) Name() string {
+	return S3
+}
+
+func (s *S3Store) url(remotePath string) string {
+	u := url.URL{
+		Scheme: "s3",
+		Host:   s.Bucket,
+		Path:   remotePath,
+	}
+	return u.String()
+}
+
+const (
+	// sha256Meta is the name of the metadata holding a file's checksum.
+	sha256Meta = "sha256"
+
+	// sha256Suffix is appended to the path of a file uploaded in parts to
+	// get the path of the object holding its checksum, since S3 can't store
+	// metadata with multipart uploads.
+	sha256Suffix = ".sha256"
+)
+
+func (s *S3Store) bucket() *s3.Bucket {
+	return thirdparty.NewS3Session(&s.Auth, aws.USEast).Bucket(s.Bucket)
+}
+
+// Put uploads the local file to the bucket, recording its checksum with it.
+// Files larger than the part size are uploaded in parts, and their checksum
+// is put next to them instead.
+func (s *S3Store) Put(localPath, remotePath string) (*artifact.File, error) {
+	sum, size, err := FileChecksum(localPath)
+	if err != nil {
+		return nil, err
+	}
+	if s.PartSize > 0 && size > s.PartSize {
+		err = thirdparty.PutS3FileMultipart(&s.Auth, localPath, s.url(remotePath), s.ContentType, s.Permissions, s.PartSize)
+		if err == nil {
+			err = s.bucket().Put(remotePath+sha256Suffix, []byte(sum), "text/plain", s3.ACL(s.Permissions), s3.Options{})
+		}
+	} else {
+		meta := map[string]string{sha256Meta: sum}
+		err = thirdparty.PutS3FileWithMeta(&s.Auth, localPath, s.url(remotePath), s.ContentType, s.Permissions, meta)
+	}
+	if err != nil {
+		return nil, err
+	}
+	remotePath = filepath.ToSlash(remotePath)
+	return &artifact.File{
+		Link:        S3BaseURL + s.Bucket + "/" + remotePath,
+		Store:       S3,
+		Bucket:      s.Bucket,
+		Path:        remotePath,
+		Size:        size,
+		SHA256:      sum,
+		ContentType: s.ContentType,
+	}, nil
+}
+
+// Get downloads the file from the bucket. Files put with a checksum are
+// verified as they're read.
+func (s *S3Store) Get(remotePath string) (io.ReadCloser, error) {
+	reader, info, err := thirdparty.GetS3FileWithInfo(&s.Auth, s.url(remotePath))
+	if err != nil {
+		return nil, fmt.Errorf("error getting bucket reader for file %v: %v", remotePath, err)
+	}
+	sum := info.Meta[sha256Meta]
+	// only files uploaded in parts can have their checksum put next to them
+	if sum == "" && info.Multipart() {
+		if sum, err = s.partsChecksum(remotePath); err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+	return NewVerifyingReader(reader, remotePath, sum), nil
+}
+
+// partsChecksum returns the checksum put next to a file uploaded in parts,
+// or an empty string for files put without one. S3 reports a missing file
+// as forbidden rather than not found to credentials that can't list the
+// bucket, so either means there's no checksum.
+func (s *S3Store) partsChecksum(remotePath string) (string, error) {
+	sum, err := s.bucket().Get(remotePath + sha256Suffix)
+	if s3Err, ok := err.(*s3.Error); ok && (s3Err.StatusCode == http.StatusNotFound ||
+		s3Err.StatusCode == http.StatusForbidden || s3Err.Code == "NoSuchKey") {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting checksum for file %v: %v", remotePath, err)
+	}
+	return strings.TrimSpace(string(sum)), nil
+}
+
+// SignedURL returns a link to the file that works without credentials until
+// it expires.
+func (s *S3Store) SignedURL(remotePath string, expires time.Time) string {
+	return s.bucket().SignedURL(remotePath, expires)
+}
+
+// AbortPut discards the parts of an unfinished upload of the file, for
+// when a put that was uploading it in parts is given up on.
+func (s *S3Store) AbortPut(remotePath string) error {
+	if err := thirdparty.AbortS3Multipart(&s.Auth, s.url(remotePath)); err != nil {
+		return fmt.Errorf("error aborting upload of file %v: %v", remotePath, err)
+	}
+	return nil
+}
+
+// Delete removes the file, and the checksum put next to it if it was
+// uploaded in parts, from the bucket.
+func (s *S3Store) Delete(remotePath string) error {
+	if err := thirdparty.DeleteS3File(&s.Auth, s.url(remotePath)); err != nil {
+		return fmt.Errorf("error deleting file %v: %v", remotePath, err)
+	}
+	if err := thirdparty.DeleteS3File(&s.Auth, s.url(remotePath+sha256Suffix)); err != nil {
+		return fmt.Errorf("error deleting checksum for file %v: %v", remotePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// DefaultSignedURLExpiration is how long signed links work for, if the
+// settings don't say otherwise.
+const DefaultSignedURLExpiration = time.Hour
+
+// SignedLink returns the link that the file should be downloaded from as of
+// now. Private files recorded as being in one of the configured S3 buckets
+// get a signed link that works without credentials until it expires; other
+// files, and all files when no signing credentials are configured, keep
+// their links.
+func SignedLink(file artifact.File, conf evergreen.SignedURLConfig, now time.Time) string {
+	if file.Visibility != artifact.Private || conf.AWSKey == "" || conf.AWSSecret == "" {
+		return file.Link
+	}
+	if file.Store != S3 || file.Path == "" || !util.SliceContains(conf.Buckets, file.Bucket) {
+		return file.Link
+	}
+
+	expiration := DefaultSignedURLExpiration
+	if conf.ExpirationMinutes > 0 {
+		expiration = time.Duration(conf.ExpirationMinutes) * time.Minute
+	}
+	s := NewS3Store(conf.AWSKey, conf.AWSSecret, file.Bucket, "", "")
+	return s.SignedURL(file.Path, now.Add(expiration))
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSignedLink(t *testing.T) {
+	Convey("With files in S3", t, func() {
+		now := time.Now()
+		conf := evergreen.SignedURLConfig{AWSKey: "key", AWSSecret: "secret", Buckets: []string{"bucket"}}
+		private := artifact.File{
+			Link:       "https://s3.amazonaws.com/bucket/dir/out.tgz",
+			Visibility: artifact.Private,
+			Store:      S3,
+			Bucket:     "bucket",
+			Path:       "dir/out.tgz",
+		}
+
+		Convey("private files should get signed links that expire", func() {
+			link := SignedLink(private, conf, now)
+			So(link, ShouldNotEqual, private.Link)
+			So(link, ShouldContainSubstring, "dir/out.tgz")
+			So(link, ShouldContainSubstring, "Signature=")
+			So(link, ShouldContainSubstring, "Expires=")
+		})
+
+		Convey("private files attached before their store was recorded should keep their links", func() {
+			legacy := artifact.File{Link: private.Link, Visibility: artifact.Private}
+			So(SignedLink(legacy, conf, now), ShouldEqual, private.Link)
+		})
+
+		Convey("private files in buckets that aren't configured should keep their links", func() {
+			other := private
+			other.Bucket = "other"
+			other.Link = "https://s3.amazonaws.com/other/dir/out.tgz"
+			So(SignedLink(other, conf, now), ShouldEqual, other.Link)
+		})
+
+		Convey("public files, and all files without credentials, should keep their links", func() {
+			public := private
+			public.Visibility = artifact.Public
+			So(SignedLink(public, conf, now), ShouldEqual, private.Link)
+			So(SignedLink(private, evergreen.SignedURLConfig{}, now), ShouldEqual, private.Link)
+		})
+	})
+}
@@ -0,0 +1,48 @@
+// Package store abstracts where the files that tasks upload are kept, so that
+// build outputs can live in S3 or on storage that Evergreen hosts itself.
+package store
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+)
+
+const (
+	// names of the kinds of artifact store
+	S3    = "s3"
+	Local = "local"
+)
+
+var ValidStores = []string{S3, Local}
+
+// ArtifactStore is a place files can be put and fetched from.
+type ArtifactStore interface {
+	// Name returns the kind of store, e.g. "s3" or "local".
+	Name() string
+
+	// Put copies the local file into the store at the remote path, and
+	// returns an entry describing where it was put. The caller fills in the
+	// entry's name and visibility.
+	Put(localPath, remotePath string) (*artifact.File, error)
+
+	// Get returns the contents of the file at the remote path. The caller
+	// must close it.
+	Get(remotePath string) (io.ReadCloser, error)
+}
+
+// ErrNotFound is returned when fetching a file that isn't in a store.
+type ErrNotFound struct {
+	Path string
+}
+
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("no artifact at '%v'", e.Path)
+}
+
+// Deleter is a store that files can be removed from, for cleaning up
+// expired artifacts. The stores the servers reach directly are Deleters.
+type Deleter interface {
+	Delete(remotePath string) error
+}
@@ -25,6 +25,11 @@ const (
 	StartAgentTimeout = 2 * time.Minute
 	agentFile         = "agent"
 	pidFile           = ".pid"
+	hostSecretFile    = ".host_secret"
+
+	// PullAgentTimeout is how long a long-running agent may go without
+	// contacting the API server before it is presumed dead and restarted.
+	PullAgentTimeout = 5 * time.Minute
 )
 
 // HostGateway is responsible for kicking off tasks on remote machines.
@@ -45,11 +50,25 @@ type AgentHostGateway struct {
 	currentAgentHash string
 }
 
+// NewAgentHostGateway returns an AgentHostGateway that copies agents from the
+// executables directory in the settings.
+func NewAgentHostGateway(settings *evergreen.Settings) *AgentHostGateway {
+	return &AgentHostGateway{
+		ExecutablesDir: filepath.Join(evergreen.FindEvergreenHome(), settings.AgentExecutablesDir),
+	}
+}
+
 // Start the task specified, on the host specified.  First runs any necessary
 // preparation on the remote machine, then kicks off the agent process on the
 // machine.
+// If the host's distro uses long-running agents and the host's agent is alive,
+// nothing is done over SSH: the agent picks the task up the next time it polls.
 // Returns an error if any step along the way fails.
 func (agbh *AgentHostGateway) RunTaskOnHost(settings *evergreen.Settings, taskToRun task.Task, hostObj host.Host) (string, error) {
+	if hostObj.Distro.PullAgent && hostObj.HasLiveAgent(PullAgentTimeout) {
+		evergreen.Logger.Logf(slogger.INFO, "Agent on host %v will poll for task %v", hostObj.Id, taskToRun.Id)
+		return hostObj.AgentRevision, nil
+	}
 
 	// get the host's SSH options
 	cloudHost, err := providers.GetCloudHost(&hostObj, settings)
@@ -93,6 +112,11 @@ func (agbh *AgentHostGateway) GetAgentRevision() (string, error) {
 	return strings.TrimSpace(string(hashBytes)), nil
 }
 
+// AgentExecutablePath returns the path to the compiled agent for the distro.
+func (agbh *AgentHostGateway) AgentExecutablePath(d *distro.Distro) string {
+	return filepath.Join(agbh.ExecutablesDir, agentSubPath(d))
+}
+
 // executableSubPath returns the directory containing the compiled agents.
 func executableSubPath(id string) (string, error) {
 
@@ -102,12 +126,18 @@ func executableSubPath(id string) (string, error) {
 		return "", fmt.Errorf("error finding distro %v: %v", id, err)
 	}
 
+	return agentSubPath(d), nil
+}
+
+// agentSubPath returns the path of the distro's compiled agent within the
+// executables directory.
+func agentSubPath(d *distro.Distro) string {
 	mainName := "main"
 	if strings.HasPrefix(d.Arch, "windows") {
 		mainName = "main.exe"
 	}
 
-	return filepath.Join(d.Arch, mainName), nil
+	return filepath.Join(d.Arch, mainName)
 }
 
 func newCappedOutputLog() *util.CappedWriter {
@@ -221,6 +251,20 @@ func startAgentOnRemote(apiURL string, task *task.Task, hostObj *host.Host, sshO
 		pathToExecutable, apiURL, task.Id, task.Secret, filepath.Join(hostObj.Distro.WorkDir,
 			agentFile), "", filepath.Join(hostObj.Distro.WorkDir, pidFile),
 	)
+	if hostObj.Distro.PullAgent {
+		// a new secret locks out any previous agent that is still running
+		if err := hostObj.SetSecret(); err != nil {
+			return fmt.Errorf("error setting secret for host %v: %v", hostObj.Id, err)
+		}
+		// the secret is written to a file, so that it's never on a command
+		// line or in the logs
+		secretPath := filepath.Join(hostObj.Distro.WorkDir, hostSecretFile)
+		remoteCmd = fmt.Sprintf(
+			`%v -api_server "%v" -host_id "%v" -host_secret_file "%v" -log_prefix "%v" -https_cert "%v" -pid_file "%v"`,
+			pathToExecutable, apiURL, hostObj.Id, secretPath, filepath.Join(hostObj.Distro.WorkDir,
+				agentFile), "", filepath.Join(hostObj.Distro.WorkDir, pidFile),
+		)
+	}
 	evergreen.Logger.Logf(slogger.INFO, "%v", remoteCmd)
 
 	// compute any info necessary to ssh into the host
@@ -229,6 +273,12 @@ func startAgentOnRemote(apiURL string, task *task.Task, hostObj *host.Host, sshO
 		return fmt.Errorf("error parsing ssh info %v: %v", hostObj.Host, err)
 	}
 
+	if hostObj.Distro.PullAgent {
+		if err = writeHostSecret(hostObj, hostInfo, sshOptions); err != nil {
+			return err
+		}
+	}
+
 	// run the command to kick off the agent remotely
 	var startAgentLog bytes.Buffer
 	startAgentCmd := &command.RemoteCommand{
@@ -257,3 +307,30 @@ func startAgentOnRemote(apiURL string, task *task.Task, hostObj *host.Host, sshO
 
 	return nil
 }
+
+// writeHostSecret writes the host's secret to a file in the distro's working
+// directory that only the host's user can read, for a long-running agent to
+// authenticate with. The secret is sent over ssh's standard input.
+func writeHostSecret(hostObj *host.Host, hostInfo *util.StaticHostInfo, sshOptions []string) error {
+	output := newCappedOutputLog()
+	writeCmd := &command.RemoteCommand{
+		Id:             fmt.Sprintf("hostsecret-%v", rand.Int()),
+		CmdString:      fmt.Sprintf(`umask 077 && cat > "%v"`, filepath.Join(hostObj.Distro.WorkDir, hostSecretFile)),
+		Stdin:          strings.NewReader(hostObj.Secret),
+		Stdout:         output,
+		Stderr:         output,
+		RemoteHostName: hostInfo.Hostname,
+		User:           hostObj.User,
+		Options:        append([]string{"-p", hostInfo.Port}, sshOptions...),
+		Background:     false,
+	}
+	err := util.RunFunctionWithTimeout(writeCmd.Run, MakeShellTimeout)
+	if err != nil {
+		if err == util.ErrTimedOut {
+			writeCmd.Stop()
+			return fmt.Errorf("writing host secret timed out: %v", output.String())
+		}
+		return fmt.Errorf("error writing secret for host %v (%v): %v", hostObj.Id, err, output.String())
+	}
+	return nil
+}
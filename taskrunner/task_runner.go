@@ -26,17 +26,11 @@ var (
 )
 
 func NewTaskRunner(settings *evergreen.Settings) *TaskRunner {
-	// get mci home, and set the source and destination for the agent
-	// executables
-	evgHome := evergreen.FindEvergreenHome()
-
 	return &TaskRunner{
 		settings,
 		&DBHostFinder{},
 		&DBTaskQueueFinder{},
-		&AgentHostGateway{
-			ExecutablesDir: filepath.Join(evgHome, settings.AgentExecutablesDir),
-		},
+		NewAgentHostGateway(settings),
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math"
 	"net/url"
+	"strings"
 )
 
 // JiraTickets marshal to and unmarshal from the json issue
@@ -95,13 +96,28 @@ type JiraHandler struct {
 	Password   string
 }
 
+// url returns the address of the given path on the JIRA server. The server
+// is reached over https unless its address includes a scheme.
+func (jiraHandler *JiraHandler) url(path string) string {
+	if strings.Contains(jiraHandler.JiraServer, "://") {
+		return strings.TrimSuffix(jiraHandler.JiraServer, "/") + path
+	}
+	return fmt.Sprintf("https://%v%v", jiraHandler.JiraServer, path)
+}
+
+// IssueURL returns the address of the web page for the ticket with the given
+// key.
+func (jiraHandler *JiraHandler) IssueURL(key string) string {
+	return jiraHandler.url("/browse/" + url.QueryEscape(key))
+}
+
 // CreateTicket takes a map of fields to initialize a JIRA ticket with. Returns a response containing the
 // new ticket's key, id, and API URL. See the JIRA API documentation for help.
 func (jiraHandler *JiraHandler) CreateTicket(fields map[string]interface{}) (*JiraCreateTicketResponse, error) {
 	postArgs := struct {
 		Fields map[string]interface{} `json:"fields"`
 	}{fields}
-	apiEndpoint := fmt.Sprintf("https://%v/rest/api/2/issue", jiraHandler.JiraServer)
+	apiEndpoint := jiraHandler.url("/rest/api/2/issue")
 	res, err := jiraHandler.MyHttp.doPost(apiEndpoint, jiraHandler.UserName, jiraHandler.Password, postArgs)
 	if res != nil {
 		defer res.Body.Close()
@@ -123,7 +139,7 @@ func (jiraHandler *JiraHandler) CreateTicket(fields map[string]interface{}) (*Ji
 
 // UpdateTicket sets the given fields of the ticket with the given key. Returns any errors JIRA returns.
 func (jiraHandler *JiraHandler) UpdateTicket(key string, fields map[string]interface{}) error {
-	apiEndpoint := fmt.Sprintf("https://%v/rest/api/2/issue/%v", jiraHandler.JiraServer, url.QueryEscape(key))
+	apiEndpoint := jiraHandler.url("/rest/api/2/issue/" + url.QueryEscape(key))
 	putArgs := struct {
 		Fields map[string]interface{} `json:"fields"`
 	}{fields}
@@ -144,7 +160,7 @@ func (jiraHandler *JiraHandler) UpdateTicket(key string, fields map[string]inter
 
 // GetJIRATicket returns the ticket with the given key.
 func (jiraHandler *JiraHandler) GetJIRATicket(key string) (*JiraTicket, error) {
-	apiEndpoint := fmt.Sprintf("https://%v/rest/api/latest/issue/%v", jiraHandler.JiraServer, url.QueryEscape(key))
+	apiEndpoint := jiraHandler.url("/rest/api/latest/issue/" + url.QueryEscape(key))
 
 	res, err := jiraHandler.MyHttp.doGet(apiEndpoint, jiraHandler.UserName, jiraHandler.Password)
 	if res != nil {
@@ -179,7 +195,7 @@ func (jiraHandler *JiraHandler) GetJIRATicket(key string) (*JiraTicket, error) {
 // JQLSearch runs the given JQL query against the given jira instance and returns
 // the results in a JiraSearchResults
 func (jiraHandler *JiraHandler) JQLSearch(query string, startAt, maxResults int) (*JiraSearchResults, error) {
-	apiEndpoint := fmt.Sprintf("https://%v/rest/api/latest/search?jql=%v&startAt=%d&maxResults=%d", jiraHandler.JiraServer, url.QueryEscape(query), startAt, maxResults)
+	apiEndpoint := jiraHandler.url(fmt.Sprintf("/rest/api/latest/search?jql=%v&startAt=%d&maxResults=%d", url.QueryEscape(query), startAt, maxResults))
 
 	res, err := jiraHandler.MyHttp.doGet(apiEndpoint, jiraHandler.UserName, jiraHandler.Password)
 	if err != nil {
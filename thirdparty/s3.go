@@ -222,6 +222,13 @@ func S3CopyFile(awsAuth *aws.Auth, fromS3Bucket, fromS3Path,
 // PutS3File writes the specified file to an s3 bucket using the given permissions and content type.
 // The details of where to put the file are included in the s3URL
 func PutS3File(pushAuth *aws.Auth, localFilePath, s3URL, contentType, permissionACL string) error {
+	return PutS3FileWithMeta(pushAuth, localFilePath, s3URL, contentType, permissionACL, nil)
+}
+
+// PutS3FileWithMeta writes the specified file to an s3 bucket like PutS3File,
+// and stores the given user metadata with it.
+func PutS3FileWithMeta(pushAuth *aws.Auth, localFilePath, s3URL, contentType, permissionACL string,
+	meta map[string]string) error {
 	urlParsed, err := url.Parse(s3URL)
 	if err != nil {
 		return err
@@ -245,6 +252,12 @@ func PutS3File(pushAuth *aws.Auth, localFilePath, s3URL, contentType, permission
 	bucket := session.Bucket(urlParsed.Host)
 	// options for the header
 	options := s3.Options{}
+	if len(meta) > 0 {
+		options.Meta = map[string][]string{}
+		for key, value := range meta {
+			options.Meta[key] = []string{value}
+		}
+	}
 	err = bucket.PutReader(urlParsed.Path, localFileReader, fi.Size(), contentType, s3.ACL(permissionACL), options)
 	if err != nil {
 		return err
@@ -263,6 +276,62 @@ func GetS3File(auth *aws.Auth, s3URL string) (io.ReadCloser, error) {
 	return bucket.GetReader(urlParsed.Path)
 }
 
+// S3FileInfo is what S3 reports about a file along with its contents.
+type S3FileInfo struct {
+	// Meta is the user metadata stored with the file.
+	Meta map[string]string
+	// ETag identifies the file's contents.
+	ETag string
+}
+
+// Multipart returns true if the file was uploaded in parts, which S3 marks
+// by ending its ETag with a dash and the number of parts.
+func (info *S3FileInfo) Multipart() bool {
+	return strings.Contains(info.ETag, "-")
+}
+
+// GetS3FileWithInfo returns the file at the s3 URL along with its user
+// metadata and ETag.
+func GetS3FileWithInfo(auth *aws.Auth, s3URL string) (io.ReadCloser, *S3FileInfo, error) {
+	urlParsed, err := url.Parse(s3URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	session := NewS3Session(auth, aws.USEast)
+
+	bucket := session.Bucket(urlParsed.Host)
+	resp, err := bucket.GetResponse(urlParsed.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &S3FileInfo{
+		Meta: map[string]string{},
+		ETag: resp.Header.Get("ETag"),
+	}
+	for header := range resp.Header {
+		key := strings.ToLower(header)
+		if strings.HasPrefix(key, s3MetaPrefix) {
+			info.Meta[strings.TrimPrefix(key, s3MetaPrefix)] = resp.Header.Get(header)
+		}
+	}
+	return resp.Body, info, nil
+}
+
+// s3MetaPrefix starts the names of the headers that hold user metadata.
+const s3MetaPrefix = "x-amz-meta-"
+
+// DeleteS3File removes the file at the s3 URL.
+func DeleteS3File(auth *aws.Auth, s3URL string) error {
+	urlParsed, err := url.Parse(s3URL)
+	if err != nil {
+		return err
+	}
+	session := NewS3Session(auth, aws.USEast)
+
+	bucket := session.Bucket(urlParsed.Host)
+	return bucket.Del(urlParsed.Path)
+}
+
 //Taken from https://github.com/mitchellh/goamz/blob/master/s3/sign.go
 //Modified to access the headers/params on an HTTP req directly.
 func SignAWSRequest(auth aws.Auth, canonicalPath string, req *http.Request) {
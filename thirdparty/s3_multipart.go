@@ -0,0 +1,185 @@
+package thirdparty
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+)
+
+const (
+	// MinS3PartSize is the smallest part S3 accepts in a multipart upload,
+	// other than the last part.
+	MinS3PartSize = 5 * 1024 * 1024
+
+	// maxS3Parts is the most parts S3 accepts in a multipart upload.
+	maxS3Parts = 10000
+)
+
+var (
+	s3PartAttempts = 5
+	s3PartSleep    = 2 * time.Second
+)
+
+// S3PartSize returns the size of the parts a file of the given size is
+// uploaded in, given the preferred part size. The size is raised if it's
+// smaller than S3 allows, or if the file would need too many parts.
+func S3PartSize(fileSize, partSize int64) int64 {
+	if partSize < MinS3PartSize {
+		partSize = MinS3PartSize
+	}
+	if fileSize/partSize >= maxS3Parts {
+		partSize = fileSize/maxS3Parts + 1
+	}
+	return partSize
+}
+
+// PutS3FileMultipart writes the specified file to an s3 bucket in parts of
+// about the given size, retrying each part on its own. An upload that fails
+// partway is left unfinished, so that putting the same file to the same URL
+// again resumes it, reusing the parts that were already uploaded. Callers
+// that give up on the file must call AbortS3Multipart, since S3 keeps (and
+// bills for) the parts of an unfinished upload. Unlike PutS3FileWithMeta,
+// it can't store metadata with the file.
+func PutS3FileMultipart(auth *aws.Auth, localFilePath, s3URL, contentType, permissionACL string,
+	partSize int64) error {
+	urlParsed, err := url.Parse(s3URL)
+	if err != nil {
+		return err
+	}
+	if urlParsed.Scheme != "s3" {
+		return fmt.Errorf("Don't know how to use URL with scheme %v", urlParsed.Scheme)
+	}
+
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := fi.Size()
+	partSize = S3PartSize(size, partSize)
+
+	session := NewS3Session(auth, aws.USEast)
+	bucket := session.Bucket(urlParsed.Host)
+	// Multi resumes an unfinished upload to the same key, if there is one
+	multi, err := bucket.Multi(s3Key(urlParsed), contentType, s3.ACL(permissionACL))
+	if err != nil {
+		return fmt.Errorf("error starting multipart upload: %v", err)
+	}
+	existing := map[int]s3.Part{}
+	if uploaded, err := multi.ListParts(); err == nil {
+		for _, part := range uploaded {
+			existing[part.N] = part
+		}
+	}
+
+	parts := []s3.Part{}
+	// an empty file is uploaded as a single empty part
+	for n, offset := 1, int64(0); offset < size || n == 1; n, offset = n+1, offset+partSize {
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		section := io.NewSectionReader(f, offset, length)
+
+		if part, ok := existing[n]; ok && part.Size == length {
+			sum, err := sectionMD5(section)
+			if err != nil {
+				return err
+			}
+			if part.ETag == `"`+sum+`"` {
+				parts = append(parts, part)
+				continue
+			}
+		}
+
+		part, err := putS3Part(multi, n, section)
+		if err != nil {
+			return fmt.Errorf("error uploading part %v: %v", n, err)
+		}
+		parts = append(parts, part)
+	}
+
+	if err = multi.Complete(parts); err != nil {
+		return fmt.Errorf("error completing multipart upload: %v", err)
+	}
+	return nil
+}
+
+// AbortS3Multipart aborts any unfinished multipart upload to the given URL,
+// removing the parts uploaded so far.
+func AbortS3Multipart(auth *aws.Auth, s3URL string) error {
+	urlParsed, err := url.Parse(s3URL)
+	if err != nil {
+		return err
+	}
+	if urlParsed.Scheme != "s3" {
+		return fmt.Errorf("Don't know how to use URL with scheme %v", urlParsed.Scheme)
+	}
+
+	session := NewS3Session(auth, aws.USEast)
+	bucket := session.Bucket(urlParsed.Host)
+	key := s3Key(urlParsed)
+	multis, _, err := bucket.ListMulti(key, "")
+	if err != nil {
+		return fmt.Errorf("error listing multipart uploads: %v", err)
+	}
+	for _, multi := range multis {
+		// the listing includes uploads to keys the key is a prefix of
+		if multi.Key != key {
+			continue
+		}
+		if err = multi.Abort(); err != nil {
+			return fmt.Errorf("error aborting multipart upload: %v", err)
+		}
+	}
+	return nil
+}
+
+// s3Key returns the key an s3 URL names. S3 lists keys without the leading
+// slash, so it's dropped for uploads to be found again.
+func s3Key(s3URL *url.URL) string {
+	return strings.TrimPrefix(s3URL.Path, "/")
+}
+
+// putS3Part uploads one part of a multipart upload, retrying failures.
+func putS3Part(multi *s3.Multi, n int, section *io.SectionReader) (s3.Part, error) {
+	var part s3.Part
+	retriablePut := util.RetriableFunc(
+		func() error {
+			var err error
+			part, err = multi.PutPart(n, section)
+			if err != nil {
+				return util.RetriableError{Failure: err}
+			}
+			return nil
+		},
+	)
+	_, err := util.RetryArithmeticBackoff(retriablePut, s3PartAttempts, s3PartSleep)
+	return part, err
+}
+
+// sectionMD5 returns the hex-encoded MD5 of the section, which S3 uses as
+// the ETag of an uploaded part.
+func sectionMD5(section *io.SectionReader) (string, error) {
+	if _, err := section.Seek(0, 0); err != nil {
+		return "", err
+	}
+	digest := md5.New()
+	if _, err := io.Copy(digest, section); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
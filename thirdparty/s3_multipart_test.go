@@ -0,0 +1,40 @@
+package thirdparty
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestS3PartSize(t *testing.T) {
+	Convey("When choosing the part size for a multipart upload", t, func() {
+		Convey("the preferred size should be used if S3 allows it", func() {
+			So(S3PartSize(1<<30, 100*1024*1024), ShouldEqual, 100*1024*1024)
+		})
+		Convey("parts should be no smaller than S3's minimum", func() {
+			So(S3PartSize(1<<30, 1024), ShouldEqual, MinS3PartSize)
+		})
+		Convey("parts should grow so that no more than the maximum are needed", func() {
+			fileSize := int64(maxS3Parts) * MinS3PartSize * 2
+			partSize := S3PartSize(fileSize, MinS3PartSize)
+			So(partSize, ShouldBeGreaterThan, MinS3PartSize)
+			So((fileSize+partSize-1)/partSize, ShouldBeLessThanOrEqualTo, maxS3Parts)
+		})
+	})
+}
+
+func TestS3Key(t *testing.T) {
+	Convey("The key of an s3 URL should not have a leading slash", t, func() {
+		u, err := url.Parse("s3://bucket/path/to/file.tgz")
+		So(err, ShouldBeNil)
+		So(s3Key(u), ShouldEqual, "path/to/file.tgz")
+	})
+}
+
+func TestS3FileInfoMultipart(t *testing.T) {
+	Convey("A file should be recognized as uploaded in parts by its ETag", t, func() {
+		So((&S3FileInfo{ETag: `"d41d8cd98f00b204e9800998ecf8427e"`}).Multipart(), ShouldBeFalse)
+		So((&S3FileInfo{ETag: `"0b8a5c9a8d2f6c8e1e4f3b2a1c0d9e8f-12"`}).Multipart(), ShouldBeTrue)
+	})
+}
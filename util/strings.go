@@ -37,3 +37,9 @@ func IndexWhiteSpace(s string) int {
 	}
 	return -1
 }
+
+// ShellQuote quotes the string so that a POSIX shell reads it as a single
+// word, whatever characters it holds.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -2,10 +2,12 @@ package validator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud/providers"
 	"github.com/evergreen-ci/evergreen/cloud/providers/static"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mitchellh/mapstructure"
@@ -19,6 +21,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidSSHOptions,
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
+	ensureValidBootstrapSteps,
+	ensureValidAliasesAndTags,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -156,3 +160,53 @@ func ensureValidSSHOptions(d *distro.Distro, s *evergreen.Settings) []Validation
 	}
 	return nil
 }
+
+// ensureValidAliasesAndTags checks that the distro's aliases and capability
+// tags can be used in run_on selectors: each must be a single word that
+// doesn't start with a selector operator, and no alias may repeat the id.
+func ensureValidAliasesAndTags(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	errs := []ValidationError{}
+	check := func(kind string, names []string) {
+		for _, name := range names {
+			if name == "" || len(strings.Fields(name)) != 1 || name != strings.TrimSpace(name) {
+				errs = append(errs, ValidationError{Error, fmt.Sprintf("distro %v '%v' must be a single word", kind, name)})
+			} else if strings.IndexAny(name, model.InvalidCriterionRunes) == 0 {
+				errs = append(errs, ValidationError{Error, fmt.Sprintf("distro %v '%v' cannot start with '%v'",
+					kind, name, string(name[0]))})
+			}
+		}
+	}
+	check("alias", d.Aliases)
+	check("tag", d.Tags)
+	if util.SliceContains(d.Aliases, d.Id) {
+		errs = append(errs, ValidationError{Error, fmt.Sprintf("distro '%v' cannot be its own alias", d.Id)})
+	}
+	return errs
+}
+
+// ensureValidBootstrapSteps checks that every bootstrap step has a unique name,
+// a known type with the fields that type needs, and sane timeouts and retries.
+func ensureValidBootstrapSteps(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	errs := []ValidationError{}
+	names := map[string]bool{}
+	for i, step := range d.BootstrapSteps {
+		if step.Name == "" {
+			errs = append(errs, ValidationError{Error, fmt.Sprintf("bootstrap step %v must have a name", i+1)})
+		} else if names[step.Name] {
+			errs = append(errs, ValidationError{Error, fmt.Sprintf("bootstrap step name '%v' is used more than once", step.Name)})
+		}
+		names[step.Name] = true
+
+		if _, err := step.RunScript(); err != nil {
+			errs = append(errs, ValidationError{Error, err.Error()})
+		}
+		if step.TimeoutSecs < 0 || step.Retries < 0 || step.RetryWaitSecs < 0 {
+			errs = append(errs, ValidationError{Error,
+				fmt.Sprintf("bootstrap step '%v' cannot have a negative timeout, retry count or retry wait", step.Name)})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
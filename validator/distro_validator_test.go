@@ -240,3 +240,87 @@ func TestEnsureValidSSHOptions(t *testing.T) {
 		})
 	})
 }
+
+func TestEnsureValidBootstrapSteps(t *testing.T) {
+	Convey("When validating a distro's bootstrap steps...", t, func() {
+		Convey("valid steps of every type should not return an error", func() {
+			d := &distro.Distro{
+				BootstrapSteps: []distro.BootstrapStep{
+					{Name: "deps", Type: distro.BootstrapStepPackages, Packages: []string{"git", "make"}},
+					{Name: "tool", Type: distro.BootstrapStepFetch, URL: "https://example.com/tool", Path: "/opt/tool"},
+					{Name: "setup", Type: distro.BootstrapStepScript, Script: "echo hi", Retries: 2, TimeoutSecs: 60},
+				},
+			}
+			So(ensureValidBootstrapSteps(d, conf), ShouldBeNil)
+		})
+		Convey("a step without a name should return an error", func() {
+			d := &distro.Distro{
+				BootstrapSteps: []distro.BootstrapStep{
+					{Type: distro.BootstrapStepScript, Script: "echo hi"},
+				},
+			}
+			So(len(ensureValidBootstrapSteps(d, conf)), ShouldEqual, 1)
+		})
+		Convey("duplicate step names should return an error", func() {
+			d := &distro.Distro{
+				BootstrapSteps: []distro.BootstrapStep{
+					{Name: "a", Type: distro.BootstrapStepScript, Script: "echo hi"},
+					{Name: "a", Type: distro.BootstrapStepScript, Script: "echo bye"},
+				},
+			}
+			So(len(ensureValidBootstrapSteps(d, conf)), ShouldEqual, 1)
+		})
+		Convey("an unknown type or missing fields should return an error", func() {
+			d := &distro.Distro{
+				BootstrapSteps: []distro.BootstrapStep{
+					{Name: "a", Type: "magic"},
+					{Name: "b", Type: distro.BootstrapStepFetch, URL: "https://example.com/tool"},
+					{Name: "c", Type: distro.BootstrapStepPackages},
+				},
+			}
+			So(len(ensureValidBootstrapSteps(d, conf)), ShouldEqual, 3)
+		})
+		Convey("negative retries should return an error", func() {
+			d := &distro.Distro{
+				BootstrapSteps: []distro.BootstrapStep{
+					{Name: "a", Type: distro.BootstrapStepScript, Script: "echo hi", Retries: -1},
+				},
+			}
+			So(len(ensureValidBootstrapSteps(d, conf)), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestEnsureValidAliasesAndTags(t *testing.T) {
+	Convey("When validating a distro's aliases and tags...", t, func() {
+		Convey("single-word aliases and tags should be allowed", func() {
+			d := &distro.Distro{
+				Id:      "rhel62-large",
+				Aliases: []string{"rhel62"},
+				Tags:    []string{"linux", "large-disk"},
+			}
+			So(ensureValidAliasesAndTags(d, conf), ShouldResemble, []ValidationError{})
+		})
+		Convey("an error should be returned for names that aren't one word", func() {
+			d := &distro.Distro{
+				Aliases: []string{"rhel 62"},
+				Tags:    []string{""},
+			}
+			So(len(ensureValidAliasesAndTags(d, conf)), ShouldEqual, 2)
+		})
+		Convey("an error should be returned for names that look like selectors", func() {
+			d := &distro.Distro{
+				Aliases: []string{"!rhel62"},
+				Tags:    []string{".linux"},
+			}
+			So(len(ensureValidAliasesAndTags(d, conf)), ShouldEqual, 2)
+		})
+		Convey("an error should be returned if the distro is its own alias", func() {
+			d := &distro.Distro{
+				Id:      "rhel62",
+				Aliases: []string{"rhel62"},
+			}
+			So(len(ensureValidAliasesAndTags(d, conf)), ShouldEqual, 1)
+		})
+	})
+}
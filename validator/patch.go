@@ -45,10 +45,13 @@ func GetPatchedProject(p *patch.Patch, settings *evergreen.Settings) (*model.Pro
 		}
 	}
 
+	// included files are read at the same revision, with the patch applied
+	fetch := model.GithubIncludeFetcher(settings.Credentials["github"], projectRef, p.Githash)
+
 	project := &model.Project{}
 	// apply remote configuration patch if needed
 	if p.ConfigChanged(projectRef.RemotePath) {
-		project, err = model.MakePatchedConfig(p, projectRef.RemotePath, string(projectFileBytes))
+		project, err = model.MakePatchedConfig(p, projectRef.RemotePath, string(projectFileBytes), fetch)
 		if err != nil {
 			return nil, fmt.Errorf("Could not patch remote configuration file: %v", err)
 		}
@@ -67,8 +70,10 @@ func GetPatchedProject(p *patch.Patch, settings *evergreen.Settings) (*model.Pro
 			return nil, fmt.Errorf(message)
 		}
 	} else {
-		// configuration is not patched
-		if err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, project); err != nil {
+		// the configuration file is not patched, though files it includes may be
+		err = model.LoadProjectWithIncludes(projectFileBytes, projectRef.Identifier, project,
+			model.PatchedIncludeFetcher(p, fetch))
+		if err != nil {
 			return nil, err
 		}
 	}
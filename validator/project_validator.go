@@ -100,15 +100,26 @@ func CheckProjectSyntax(project *model.Project) ([]ValidationError, error) {
 			projectSyntaxValidator(project)...)
 	}
 
-	// get distroIds for ensureReferentialIntegrity validation
-	distroIds, err := getDistroIds()
+	// get distros for ensureReferentialIntegrity validation
+	distros, err := distro.Find(distro.All)
 	if err != nil {
 		return nil, err
 	}
-	validationErrs = append(validationErrs, ensureReferentialIntegrity(project, distroIds)...)
+	validationErrs = append(validationErrs, ensureReferentialIntegrity(project, distroNames(distros))...)
+	validationErrs = append(validationErrs, ensureDistroSelectorsMatch(project, distros)...)
 	return validationErrs, nil
 }
 
+// distroNames returns the ids and aliases of the distros.
+func distroNames(distros []distro.Distro) []string {
+	names := []string{}
+	for _, d := range distros {
+		names = append(names, d.Id)
+		names = append(names, d.Aliases...)
+	}
+	return util.UniqueStrings(names)
+}
+
 // ensure that if any task spec references 'model.AllDependencies', it
 // references no other dependency
 func checkAllDependenciesSpec(project *model.Project) []ValidationError {
@@ -361,7 +372,7 @@ func ensureReferentialIntegrity(project *model.Project, distroIds []string) []Va
 			}
 			buildVariantTasks[task.Name] = true
 			for _, distroId := range task.Distros {
-				if !util.SliceContains(distroIds, distroId) {
+				if !model.IsDistroSelector(distroId) && !util.SliceContains(distroIds, distroId) {
 					errs = append(errs,
 						ValidationError{
 							Message: fmt.Sprintf("task '%v' in buildvariant "+
@@ -377,7 +388,7 @@ func ensureReferentialIntegrity(project *model.Project, distroIds []string) []Va
 			}
 		}
 		for _, distroId := range buildVariant.RunOn {
-			if !util.SliceContains(distroIds, distroId) {
+			if !model.IsDistroSelector(distroId) && !util.SliceContains(distroIds, distroId) {
 				errs = append(errs,
 					ValidationError{
 						Message: fmt.Sprintf("buildvariant '%v' in project "+
@@ -394,6 +405,35 @@ func ensureReferentialIntegrity(project *model.Project, distroIds []string) []Va
 	return errs
 }
 
+// ensureDistroSelectorsMatch warns about distro selectors in build variants'
+// run_on lists and tasks' distros that don't match any distro.
+func ensureDistroSelectorsMatch(project *model.Project, distros []distro.Distro) []ValidationError {
+	errs := []ValidationError{}
+	distroEval := model.NewDistroSelectorEvaluator(distros)
+	checkSelectors := func(entries []string, owner string) {
+		for _, entry := range entries {
+			if !model.IsDistroSelector(entry) {
+				continue
+			}
+			if _, err := distroEval.Resolve(entry); err != nil {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("%v in project '%v' has a distro selector "+
+						"that matches no distros: %v", owner, project.Identifier, err),
+					Level: Warning,
+				})
+			}
+		}
+	}
+	for _, buildVariant := range project.BuildVariants {
+		checkSelectors(buildVariant.RunOn, fmt.Sprintf("buildvariant '%v'", buildVariant.Name))
+		for _, task := range buildVariant.Tasks {
+			checkSelectors(task.Distros, fmt.Sprintf("task '%v' in buildvariant '%v'",
+				task.Name, buildVariant.Name))
+		}
+	}
+	return errs
+}
+
 // Ensures there aren't any duplicate buildvariant names specified in the given
 // project
 func validateBVNames(project *model.Project) []ValidationError {
@@ -496,6 +536,57 @@ func validateCommands(section string, project *model.Project, registry plugin.Re
 				errs = append(errs, ValidationError{Message: msg})
 			}
 		}
+		if cmd.Condition != nil {
+			if err := cmd.Condition.Validate(); err != nil {
+				msg := fmt.Sprintf("%v section in %v: invalid condition: %v", section, command, err)
+				errs = append(errs, ValidationError{Message: msg})
+			}
+		}
+		errs = append(errs, validateRetry(section, command, cmd)...)
+	}
+	return errs
+}
+
+// validateRetry checks that a command's retry and continue_on_err options
+// make sense together and in the section the command is in.
+func validateRetry(section, command string, cmd model.PluginCommandConf) []ValidationError {
+	errs := []ValidationError{}
+	if cmd.Retry != nil {
+		if cmd.Retry.Attempts < 1 || cmd.Retry.Attempts > model.MaxCommandAttempts {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("%v section in %v: retry attempts must be between 1 and %v, not %v",
+					section, command, model.MaxCommandAttempts, cmd.Retry.Attempts),
+			})
+		}
+		if cmd.Retry.BackoffSecs < 0 {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("%v section in %v: retry backoff cannot be negative", section, command),
+			})
+		}
+		timeoutSecs := cmd.TimeoutSecs
+		if timeoutSecs <= 0 {
+			timeoutSecs = model.DefaultCommandTimeoutSecs
+		}
+		if longest := cmd.Retry.BackoffSecs * (cmd.Retry.Attempts - 1); longest > timeoutSecs {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("%v section in %v: the last retry backoff (%vs) is longer "+
+					"than the command's timeout (%vs)", section, command, longest, timeoutSecs),
+			})
+		}
+		if cmd.Retry.Attempts == 1 && cmd.Retry.BackoffSecs > 0 {
+			errs = append(errs, ValidationError{
+				Level: Warning,
+				Message: fmt.Sprintf("%v section in %v: retry backoff has no effect with a single attempt",
+					section, command),
+			})
+		}
+	}
+	if cmd.ContinueOnError && (section == "pre" || section == "post" || section == "timeout") {
+		errs = append(errs, ValidationError{
+			Level: Warning,
+			Message: fmt.Sprintf("%v section in %v: continue_on_err has no effect, since "+
+				"the %v section always continues after failures", section, command, section),
+		})
 	}
 	return errs
 }
@@ -936,6 +936,80 @@ func TestValidatePluginCommands(t *testing.T) {
 			So(validatePluginCommands(project), ShouldNotResemble, []ValidationError{})
 			So(len(validatePluginCommands(project)), ShouldEqual, 1)
 		})
+		Convey("an error should be thrown if a command has an invalid condition", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{
+					{
+						Name: "compile",
+						Commands: []model.PluginCommandConf{
+							{
+								Command: "gotest.parse_files",
+								Params: map[string]interface{}{
+									"files": []interface{}{"test"},
+								},
+								Condition: &model.CommandCondition{Requester: "nightly"},
+							},
+						},
+					},
+				},
+			}
+			So(len(validatePluginCommands(project)), ShouldEqual, 1)
+		})
+		Convey("an error should be thrown if a command's retry options are invalid", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{
+					{
+						Name: "compile",
+						Commands: []model.PluginCommandConf{
+							{
+								Command: "gotest.parse_files",
+								Params: map[string]interface{}{
+									"files": []interface{}{"test"},
+								},
+								Retry: &model.CommandRetry{Attempts: 0, BackoffSecs: -1},
+							},
+						},
+					},
+				},
+			}
+			So(len(validatePluginCommands(project)), ShouldEqual, 2)
+		})
+		Convey("an error should be thrown if a retry backoff is longer than the command's timeout", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{
+					{
+						Name: "compile",
+						Commands: []model.PluginCommandConf{
+							{
+								Command: "gotest.parse_files",
+								Params: map[string]interface{}{
+									"files": []interface{}{"test"},
+								},
+								TimeoutSecs: 60,
+								Retry:       &model.CommandRetry{Attempts: 3, BackoffSecs: 40},
+							},
+						},
+					},
+				},
+			}
+			So(len(validatePluginCommands(project)), ShouldEqual, 1)
+		})
+		Convey("a warning should be returned if continue_on_err is used in post", func() {
+			project := &model.Project{
+				Post: &model.YAMLCommandSet{
+					SingleCommand: &model.PluginCommandConf{
+						Command: "gotest.parse_files",
+						Params: map[string]interface{}{
+							"files": []interface{}{"test"},
+						},
+						ContinueOnError: true,
+					},
+				},
+			}
+			errs := validatePluginCommands(project)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Level, ShouldEqual, Warning)
+		})
 		Convey("no error should be thrown if a function plugin command is valid", func() {
 			project := &model.Project{
 				Functions: map[string]*model.YAMLCommandSet{
@@ -1441,3 +1515,42 @@ func TestEnsureHasNecessaryBVFields(t *testing.T) {
 		})
 	})
 }
+
+func TestEnsureDistroSelectorsMatch(t *testing.T) {
+	Convey("When validating a project's distro selectors", t, func() {
+		distros := []distro.Distro{
+			{Id: "rhel62", Tags: []string{"linux"}},
+			{Id: "windows", Aliases: []string{"win"}},
+		}
+
+		Convey("a warning should be returned if a selector matches no distro", func() {
+			project := &model.Project{
+				BuildVariants: []model.BuildVariant{
+					{
+						Name:  "enterprise",
+						RunOn: []string{".linux .gpu"},
+						Tasks: []model.BuildVariantTask{
+							{Name: "compile", Distros: []string{".osx"}},
+						},
+					},
+				},
+			}
+			errs := ensureDistroSelectorsMatch(project, distros)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Level, ShouldEqual, Warning)
+		})
+
+		Convey("no warning should be returned for selectors, aliases and ids"+
+			" that match", func() {
+			project := &model.Project{
+				BuildVariants: []model.BuildVariant{
+					{
+						Name:  "enterprise",
+						RunOn: []string{".linux", "win", "rhel62"},
+					},
+				},
+			}
+			So(ensureDistroSelectorsMatch(project, distros), ShouldResemble, []ValidationError{})
+		})
+	})
+}